@@ -1,11 +1,17 @@
 package server
 
 import (
+	"context"
+	"github.com/christo-andrew/haven/pkg/budgets"
 	"github.com/christo-andrew/haven/pkg/config"
 	"github.com/christo-andrew/haven/pkg/database"
+	"github.com/christo-andrew/haven/pkg/jobs"
+	"github.com/christo-andrew/haven/pkg/scheduler"
 	"log"
 
 	"github.com/christo-andrew/haven/internal/api"
+	"github.com/christo-andrew/haven/internal/api/handlers"
+	"github.com/christo-andrew/haven/internal/models"
 )
 
 //	@title			Haven API
@@ -35,8 +41,35 @@ func StartApp() {
 		log.Fatal(err)
 	}
 
-	server := app.SetupRouter(db)
+	store, err := currentConfig.Storage.GetStorage()
+	if err != nil {
+		log.Fatalf("Failed to set up storage: %v", err)
+	}
+
+	alerter := budgets.NewAlerter(db, currentConfig.Notifications.GetNotifier(db))
+	defer alerter.Wait()
+	server := app.SetupRouter(db, store, alerter)
 	database.Migrate(db)
+	database.RegisterAudit(db)
+
+	if currentConfig.Scheduler.Enabled {
+		jobScheduler := scheduler.New()
+		jobScheduler.Every(currentConfig.Scheduler.RecurringInterval, "recurring-transactions", func(ctx context.Context) error {
+			return jobs.PostDueRecurringTransactions(ctx, db, func(userId uint, transaction models.Transaction, replaced *models.Transaction) {
+				handlers.TransactionPosted(userId, transaction, replaced)
+				alerter.CheckLater(userId)
+			})
+		})
+		jobScheduler.Every(currentConfig.Scheduler.BudgetInterval, "budget-periods", func(ctx context.Context) error {
+			return jobs.AdvanceBudgetPeriods(ctx, db)
+		})
+		jobScheduler.Every(currentConfig.Scheduler.AlertInterval, "budget-alerts", func(ctx context.Context) error {
+			return jobs.CheckBudgetAlerts(ctx, alerter)
+		})
+		jobScheduler.Start(context.Background())
+		defer jobScheduler.Stop()
+	}
+
 	err = server.Run()
 	if err != nil {
 		panic(err)
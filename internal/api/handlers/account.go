@@ -4,9 +4,13 @@ import (
 	"fmt"
 	"github.com/christo-andrew/haven/internal/api/responses"
 	"github.com/christo-andrew/haven/pkg/auth"
+	"github.com/christo-andrew/haven/pkg/budgets"
+	"github.com/christo-andrew/haven/pkg/errors"
+	"github.com/christo-andrew/haven/pkg/ledger"
 	"github.com/christo-andrew/haven/pkg/pagination"
 	"github.com/christo-andrew/haven/pkg/utils"
 	"io"
+	"math"
 	"mime/multipart"
 	"net/http"
 	"strconv"
@@ -87,6 +91,104 @@ func getAccount(accountID int, db *gorm.DB) models.Account {
 	return account
 }
 
+func getUserAccount(accountID int, userID uint, db *gorm.DB) (models.Account, error) {
+	var account models.Account
+	scopes.GetUserAccountById(accountID, userID, db).First(&account)
+	if account.ID == 0 {
+		return account, errors.AccountNotFoundError()
+	}
+	return account, nil
+}
+
+// GetAccountTagsHandler GetAccountTags godoc
+// @Summary Get all tags for an account
+// @Description Retrieve all tags for an account
+// @Produce json
+// @Param id path int true "Account ID"
+// @Success 200 {array} responses.TagResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /accounts/{id}/tags [get]
+// @Tags accounts
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetAccountTagsHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	accountId, _ := strconv.Atoi(c.Param("id"))
+	account, err := getUserAccount(accountId, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	tags, err := attachedTags(&account, db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, serializers.NewTagSerializer(tags, true).Serialize())
+}
+
+// AddAccountTagHandler AddAccountTag godoc
+// @Summary Add a tag to an account
+// @Description Add a tag to an account, creating the tag when no tag has that name yet
+// @Accept json
+// @Produce json
+// @Param id path int true "Account ID"
+// @Param tag body requests.CreateTagRequest true "Create Tag Request"
+// @Success 201 {object} responses.TagResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /accounts/{id}/tags [post]
+// @Tags accounts
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func AddAccountTagHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	accountId, _ := strconv.Atoi(c.Param("id"))
+	tag, err := requestedTag(c, userId, db)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	account, err := getUserAccount(accountId, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err := db.Model(&account).Association("Tags").Append(tag); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, responses.TagResponse{}.FromTag(*tag))
+}
+
+// RemoveAccountTagHandler RemoveAccountTag godoc
+// @Summary Remove a tag from an account
+// @Description Remove a tag from an account. The tag itself is kept.
+// @Produce json
+// @Param id path int true "Account ID"
+// @Param tag_id path int true "Tag ID"
+// @Success 200 {array} responses.TagResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /accounts/{id}/tags/{tag_id} [delete]
+// @Tags accounts
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func RemoveAccountTagHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	accountId, _ := strconv.Atoi(c.Param("id"))
+	account, err := getUserAccount(accountId, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	tags, err := detachTag(&account, c.Param("tag_id"), db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, serializers.NewTagSerializer(tags, true).Serialize())
+}
+
 // GetAccountTransactionsHandler GetAccountTransactions godoc
 // @Summary Get an account's transactions
 // @Description Retrieve an account's transactions
@@ -97,7 +199,9 @@ func getAccount(accountID int, db *gorm.DB) models.Account {
 // @Param from query string false "From" Format(YYYY-MM-DD)
 // @Param to query string false "To" Format(YYYY-MM-DD)
 // @Param unixTime query boolean false "Unix Time"
+// @Param status query string false "Comma separated statuses" Enums(pending, cleared, reconciled, void)
 // @Success 200 {array} responses.TransactionResponse
+// @Failure 400 {object} responses.ErrorResponse
 // @Router /accounts/{id}/transactions [get]
 // @Tags accounts
 // @Security AuthToken
@@ -109,6 +213,11 @@ func GetAccountTransactionsHandler(c *gin.Context, db *gorm.DB) {
 	from := c.Query("from")
 	to := c.Query("to")
 	unixTime, _ := strconv.ParseBool(c.Query("unixTime"))
+	statuses, err := transactionStatusFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	paginator := pagination.Pagination{Page: page, Limit: limit}
 	var results []models.Transaction
 	var transactions *gorm.DB
@@ -124,6 +233,9 @@ func GetAccountTransactionsHandler(c *gin.Context, db *gorm.DB) {
 	} else {
 		transactions = scopes.GetTransactionsByAccountId(accountId, db)
 	}
+	if len(statuses) > 0 {
+		transactions = transactions.Scopes(scopes.WithTransactionStatus(statuses))
+	}
 
 	paginator.Paginate(transactions, models.Transaction{}).Find(&results)
 	serializer := serializers.NewTransactionSerializer(results, true)
@@ -146,7 +258,10 @@ func GetAccountTransactionsHandler(c *gin.Context, db *gorm.DB) {
 // @Param id path string true "Account ID"
 // @Param limit query int false "Limit"
 // @Param filter query string false "Filter" Enums(category)
+// @Param level query int false "Roll categories up to this depth of the category tree, 0 being the top level. Without it every category is reported on its own."
+// @Param parent_id query int false "Only report categories within this one, with levels counted from its children"
 // @Success 200 {array} responses.PercentageOfTotalAmountByTransactionResponse
+// @Failure 400 {object} responses.ErrorResponse
 // @Router /accounts/{id}/transactions/percentage [get]
 // @Tags accounts
 // @Security AuthToken
@@ -155,7 +270,13 @@ func PercentageOfTotalAmountByTransactionHandler(c *gin.Context, db *gorm.DB) {
 	accountId, _ := strconv.Atoi(c.Param("id"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "5"))
 	filter := c.DefaultQuery("filter", "category")
-	result := getPercentageOfTotalAmountBy(accountId, limit, db, filter)
+	under, level, err := categoryLevelQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := uint(auth.GetUserIdFromContext(c))
+	result := getPercentageOfTotalAmountBy(accountId, userId, limit, under, level, db, filter)
 	c.JSON(http.StatusOK, result)
 }
 
@@ -188,6 +309,46 @@ func CreateAccountHandler(c *gin.Context, db *gorm.DB) {
 	c.JSON(http.StatusCreated, response)
 }
 
+// UpdateAccountHandler UpdateAccount godoc
+// @Summary Update an account
+// @Description Change the interest rate or the minimum payment of an account, used when planning to pay off debts. Fields left out keep their values.
+// @Param id path int true "Account ID"
+// @Param account body requests.UpdateAccountRequest true "Update Account Request"
+// @Accept json
+// @Produce json
+// @Success 200 {object} responses.AccountResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /accounts/{id}/update [put]
+// @Tags accounts
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func UpdateAccountHandler(c *gin.Context, db *gorm.DB) {
+	var updateAccountRequest requests.UpdateAccountRequest
+	if err := c.ShouldBindJSON(&updateAccountRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	accountId, _ := strconv.Atoi(c.Param("id"))
+	account, err := getUserAccount(accountId, uint(auth.GetUserIdFromContext(c)), db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if updates := updateAccountRequest.Updates(); len(updates) > 0 {
+		if err := db.Model(&account).Updates(updates).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	response, err := serializers.NewAccountSerializer(account, false).Serialize()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
 func createAccount(account models.IAccount, db *gorm.DB) (models.IAccount, error) {
 	result := db.Create(account)
 	if result.Error != nil {
@@ -202,6 +363,7 @@ func createAccount(account models.IAccount, db *gorm.DB) (models.IAccount, error
 // @Accept json
 // @Produce json
 // @Param id path int true "Account ID"
+// @Param status query string false "Comma separated statuses" Enums(pending, cleared, reconciled, void)
 // @Success 200 {array} responses.TransactionResponse
 // @Router /accounts/{id}/transactions/recent [get]
 // @Failure 400 {object} responses.ErrorResponse
@@ -210,8 +372,17 @@ func createAccount(account models.IAccount, db *gorm.DB) (models.IAccount, error
 // @Param Authorization header string true "Authorization"
 func GetRecentTransactionsHandler(c *gin.Context, db *gorm.DB) {
 	accountId, _ := strconv.Atoi(c.Param("id"))
+	statuses, err := transactionStatusFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	var transactions []models.Transaction
-	scopes.GetRecentTransactions(db, accountId, 4).Find(&transactions)
+	recent := scopes.GetRecentTransactions(db, accountId, 4)
+	if len(statuses) > 0 {
+		recent = recent.Scopes(scopes.WithTransactionStatus(statuses))
+	}
+	recent.Find(&transactions)
 	response := serializers.NewTransactionSerializer(transactions, true).Serialize()
 	c.JSON(http.StatusOK, response)
 }
@@ -234,6 +405,46 @@ func AccountStatisticsHandler(c *gin.Context, db *gorm.DB) {
 	c.JSON(http.StatusOK, statistics)
 }
 
+// GetAccountBalanceHandler GetAccountBalance godoc
+// @Summary Get the balance of an account
+// @Description Break the balance of an account down by status. The cleared balance counts cleared and reconciled transactions on top of the opening balance,
+// @Description the available balance also holds back pending debits, and the current balance includes everything pending. Voided transactions are ignored.
+// @Produce json
+// @Param id path int true "Account ID"
+// @Success 200 {object} responses.AccountBalanceResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /accounts/{id}/balance [get]
+// @Tags accounts
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetAccountBalanceHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	accountId, _ := strconv.Atoi(c.Param("id"))
+	var account models.Account
+	scopes.GetUserAccountById(accountId, uint(userId), db).First(&account)
+	if account.ID == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": errors.AccountNotFoundError().Error()})
+		return
+	}
+	balance := getAccountBalance(account, db)
+	c.JSON(http.StatusOK, responses.AccountBalanceResponse{
+		AccountID:         account.ID,
+		Currency:          account.Currency,
+		OpeningBalance:    balance.Opening,
+		ReconciledBalance: balance.Reconciled,
+		ClearedBalance:    balance.Cleared,
+		PendingAmount:     balance.Pending,
+		AvailableBalance:  balance.Available,
+		CurrentBalance:    balance.Current,
+	})
+}
+
+func getAccountBalance(account models.Account, db *gorm.DB) ledger.Balance {
+	var totals []ledger.StatusTotal
+	scopes.AccountTotalsByStatus(account.ID, db).Scan(&totals)
+	return ledger.NewBalance(account.Balance, totals)
+}
+
 // UploadAccountTransactionsHandler Post Upload Account Transactions godoc
 // @Summary Upload account transactions
 // @Description Upload account transactions
@@ -245,11 +456,12 @@ func AccountStatisticsHandler(c *gin.Context, db *gorm.DB) {
 // @Success 200 {array} responses.TransactionResponse
 // @Router /accounts/{id}/transactions/upload [post]
 // @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
 // @Consumes multipart/form-data
 // @Tags accounts
 // @Security AuthToken
 // @Param Authorization header string true "Authorization"
-func UploadAccountTransactionsHandler(c *gin.Context, db *gorm.DB) {
+func UploadAccountTransactionsHandler(c *gin.Context, db *gorm.DB, alerter *budgets.Alerter) {
 	accountId, _ := strconv.Atoi(c.Param("id"))
 	transactionSchemaType := c.PostForm("transaction_schema")
 	file, err := c.FormFile("file")
@@ -257,9 +469,9 @@ func UploadAccountTransactionsHandler(c *gin.Context, db *gorm.DB) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	account := getAccount(accountId, db)
-	if account.AccountName == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Account not found"})
+	account, err := getUserAccount(accountId, uint(auth.GetUserIdFromContext(c)), db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 	transactionSchema := schemas.GetTransactionSchemaFromName(transactionSchemaType, &account, db)
@@ -278,8 +490,26 @@ func UploadAccountTransactionsHandler(c *gin.Context, db *gorm.DB) {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		}
 	}(openFile)
+	poster := ledger.NewPoster(account.UserID, db)
 	transactions := parseTransactionsFile(openFile, transactionSchema)
-	db.Create(&transactions)
+	replaced := make([]*models.Transaction, len(transactions))
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for i, transaction := range transactions {
+			var err error
+			if replaced[i], err = poster.Post(transaction, tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for i, transaction := range transactions {
+		TransactionPosted(account.UserID, *transaction, replaced[i])
+	}
+	alerter.CheckLater(account.UserID)
 	c.JSON(http.StatusOK, transactions)
 }
 
@@ -295,11 +525,31 @@ func parseTransactionsFile(file multipart.File, transactionSchema schemas.ITrans
 	return transactions
 }
 
-func getPercentageOfTotalAmountBy(accountId int, limit int, db *gorm.DB, filter string) interface{} {
+func getPercentageOfTotalAmountBy(accountId int, userId uint, limit int, under int, level int, db *gorm.DB, filter string) interface{} {
 	var result []*responses.PercentageOfTotalAmountByTransactionResponse
 	switch filter {
 	case "category":
-		scopes.PercentageOfTotalAmountByTransactionCategory(accountId, limit, db).Scan(&result)
+		totals := accountCategoryTotals(accountId, userId, under, level, nil, db)
+		var sum float64
+		for _, total := range totals {
+			sum += total.Amount
+		}
+		for _, total := range totals {
+			if limit > 0 && len(result) == limit {
+				break
+			}
+			percentage := 0.0
+			if sum != 0 {
+				percentage = math.Round(total.Amount/sum*10000) / 100
+			}
+			result = append(result, &responses.PercentageOfTotalAmountByTransactionResponse{
+				CategoryID: total.Node.Category.ID,
+				Category:   total.Node.Category.Name,
+				Path:       total.Node.Path(),
+				Amount:     math.Round(total.Amount),
+				Percentage: percentage,
+			})
+		}
 		return result
 	}
 	return nil
@@ -313,6 +563,7 @@ type TotalTransactionLastWeekVsThisWeek struct {
 
 func getStatistics(accountId int, db *gorm.DB) interface{} {
 	response := responses.AccountStatisticsResponse{}
+	response.ClearedBalance = getAccountBalance(getAccount(accountId, db), db).Cleared
 	var weekComparison responses.WeekComparison
 	scopes.TransactionsTotalThisWeekVsLastWeek(accountId, db).Scan(&weekComparison)
 	weekComparison.CalculateChange()
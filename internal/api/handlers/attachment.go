@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/christo-andrew/haven/internal/api/serializers"
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/auth"
+	"github.com/christo-andrew/haven/pkg/database/scopes"
+	"github.com/christo-andrew/haven/pkg/errors"
+	"github.com/christo-andrew/haven/pkg/storage"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// attachmentContentTypes are the sniffed content types accepted as
+// attachments. Anything a browser could execute, such as HTML or SVG, is
+// refused.
+var attachmentContentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"text/plain":      true,
+}
+
+// GetTransactionAttachmentsHandler GetTransactionAttachments godoc
+// @Summary Get the attachments of a transaction
+// @Description Retrieve the receipts and other files attached to a transaction
+// @Produce json
+// @Param id path string true "Transaction ID"
+// @Success 200 {array} responses.AttachmentResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /transactions/{id}/attachments [get]
+// @Tags transactions
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetTransactionAttachmentsHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	transactionId, _ := strconv.Atoi(c.Param("id"))
+	transaction, err := getUserTransaction(transactionId, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	var attachments []models.Attachment
+	scopes.GetTransactionAttachments(transaction.ID, db).Find(&attachments)
+	response, err := serializers.NewAttachmentSerializer(attachments, true).Serialize()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// UploadTransactionAttachmentHandler UploadTransactionAttachment godoc
+// @Summary Attach a file to a transaction
+// @Description Upload a receipt or invoice. The content type is detected from the file itself and a SHA-256 checksum is recorded.
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Transaction ID"
+// @Param file formData file true "File"
+// @Success 201 {object} responses.AttachmentResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Failure 413 {object} responses.ErrorResponse
+// @Failure 415 {object} responses.ErrorResponse
+// @Router /transactions/{id}/attachments [post]
+// @Tags transactions
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func UploadTransactionAttachmentHandler(c *gin.Context, db *gorm.DB, store storage.Storage, maxUploadSize int64) {
+	userId := auth.GetUserIdFromContext(c)
+	transactionId, _ := strconv.Atoi(c.Param("id"))
+	transaction, err := getUserTransaction(transactionId, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Leave room for the multipart envelope around the file.
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadSize+1<<20)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if stderrors.As(err, &maxBytesError) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": errors.AttachmentTooLargeError(maxUploadSize).Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if fileHeader.Size > maxUploadSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": errors.AttachmentTooLargeError(maxUploadSize).Error()})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+	content, err := io.ReadAll(io.LimitReader(file, maxUploadSize+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if int64(len(content)) > maxUploadSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": errors.AttachmentTooLargeError(maxUploadSize).Error()})
+		return
+	}
+
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(content))
+	if !attachmentContentTypes[contentType] {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": errors.UnsupportedAttachmentTypeError(contentType).Error()})
+		return
+	}
+	checksum := sha256.Sum256(content)
+	storageKey, err := attachmentStorageKey(uint(userId), transaction.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := store.Put(c.Request.Context(), storageKey, bytes.NewReader(content), contentType); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	attachment := models.Attachment{
+		TransactionID: transaction.ID,
+		UserID:        uint(userId),
+		FileName:      attachmentFileName(fileHeader.Filename),
+		ContentType:   contentType,
+		Size:          int64(len(content)),
+		Checksum:      hex.EncodeToString(checksum[:]),
+		StorageKey:    storageKey,
+	}
+	if err := db.Create(&attachment).Error; err != nil {
+		if err := store.Delete(c.Request.Context(), storageKey); err != nil {
+			log.Printf("removing orphaned attachment %s: %v", storageKey, err)
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	response, err := serializers.NewAttachmentSerializer(attachment, false).Serialize()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, response)
+}
+
+// DownloadTransactionAttachmentHandler DownloadTransactionAttachment godoc
+// @Summary Download an attachment
+// @Description Download a file attached to a transaction of the current user
+// @Produce octet-stream
+// @Param id path string true "Transaction ID"
+// @Param attachment_id path string true "Attachment ID"
+// @Success 200 {file} file
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /transactions/{id}/attachments/{attachment_id} [get]
+// @Tags transactions
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func DownloadTransactionAttachmentHandler(c *gin.Context, db *gorm.DB, store storage.Storage) {
+	attachment, err := getUserAttachment(c, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	content, err := store.Get(c.Request.Context(), attachment.StorageKey)
+	if stderrors.Is(err, storage.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": errors.AttachmentNotFoundError().Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer content.Close()
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+		"X-Content-Type-Options": "nosniff",
+		"Cache-Control":          "private, no-store",
+	})
+}
+
+// DeleteTransactionAttachmentHandler DeleteTransactionAttachment godoc
+// @Summary Delete an attachment
+// @Description Delete a file attached to a transaction
+// @Param id path string true "Transaction ID"
+// @Param attachment_id path string true "Attachment ID"
+// @Success 204
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /transactions/{id}/attachments/{attachment_id} [delete]
+// @Tags transactions
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func DeleteTransactionAttachmentHandler(c *gin.Context, db *gorm.DB, store storage.Storage) {
+	attachment, err := getUserAttachment(c, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err := db.Delete(&attachment).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := store.Delete(c.Request.Context(), attachment.StorageKey); err != nil {
+		log.Printf("removing attachment %s: %v", attachment.StorageKey, err)
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// getUserAttachment loads the attachment in the path after checking that its
+// transaction belongs to the current user.
+func getUserAttachment(c *gin.Context, db *gorm.DB) (models.Attachment, error) {
+	var attachment models.Attachment
+	userId := auth.GetUserIdFromContext(c)
+	transactionId, _ := strconv.Atoi(c.Param("id"))
+	attachmentId, _ := strconv.Atoi(c.Param("attachment_id"))
+	transaction, err := getUserTransaction(transactionId, userId, db)
+	if err != nil {
+		return attachment, err
+	}
+	scopes.GetTransactionAttachmentById(attachmentId, transaction.ID, db).First(&attachment)
+	if attachment.ID == 0 {
+		return attachment, errors.AttachmentNotFoundError()
+	}
+	return attachment, nil
+}
+
+func attachmentStorageKey(userId uint, transactionId int) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("attachments/%d/%d/%s", userId, transactionId, hex.EncodeToString(random)), nil
+}
+
+// attachmentFileName keeps the base name of an uploaded file without control
+// characters so it is safe to send back in a Content-Disposition header.
+func attachmentFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	return name
+}
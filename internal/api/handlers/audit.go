@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/christo-andrew/haven/internal/api/serializers"
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/auth"
+	"github.com/christo-andrew/haven/pkg/database/scopes"
+	"github.com/christo-andrew/haven/pkg/errors"
+	"github.com/christo-andrew/haven/pkg/pagination"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetAuditLogHandler GetAuditLog godoc
+// @Summary Get the audit log
+// @Description With an entity and id, list every change made to that record. Accounts, transactions and budgets must belong to the current user;
+// @Description categories and tags are shared, so only the current user's own changes to them are listed. Without an id, list the changes the current user made, optionally to one kind of entity.
+// @Produce json
+// @Param entity query string false "Entity" Enums(account, transaction, budget, category, tag)
+// @Param id query int false "Entity ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Limit"
+// @Success 200 {object} pagination.Response{results=[]responses.AuditLogResponse}
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /audit [get]
+// @Tags audit
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetAuditLogHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	entity := c.Query("entity")
+	entityId, _ := strconv.Atoi(c.Query("id"))
+
+	var query *gorm.DB
+	switch {
+	case entity == "" && entityId == 0:
+		query = scopes.GetActorAuditLog(userId, db)
+	case entity == "":
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.UnknownAuditEntityError(entity).Error()})
+		return
+	case entityId == 0:
+		if !isAuditedEntity(entity) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errors.UnknownAuditEntityError(entity).Error()})
+			return
+		}
+		query = scopes.GetActorAuditLog(userId, db).Where("entity = ?", entity)
+	default:
+		var err error
+		query, err = recordAuditLog(entity, entityId, userId, db)
+		if err != nil {
+			status := http.StatusNotFound
+			if !isAuditedEntity(entity) {
+				status = http.StatusBadRequest
+			}
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	respondWithAuditLog(c, query)
+}
+
+// GetTransactionHistoryHandler GetTransactionHistory godoc
+// @Summary Get the change history of a transaction
+// @Description List every change made to a transaction, newest first, with the user and request that made it
+// @Produce json
+// @Param id path int true "Transaction ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Limit"
+// @Success 200 {object} pagination.Response{results=[]responses.AuditLogResponse}
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /transactions/{id}/history [get]
+// @Tags transactions
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetTransactionHistoryHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	transactionId, _ := strconv.Atoi(c.Param("id"))
+	query, err := recordAuditLog("transaction", transactionId, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	respondWithAuditLog(c, query)
+}
+
+func isAuditedEntity(entity string) bool {
+	switch entity {
+	case "account", "transaction", "budget", "category", "tag":
+		return true
+	}
+	return false
+}
+
+// recordAuditLog returns the log of one record after checking that the user
+// may see it. Deleted records keep their history.
+func recordAuditLog(entity string, entityId int, userId uint, db *gorm.DB) (*gorm.DB, error) {
+	var count int64
+	switch entity {
+	case "account":
+		db.Unscoped().Model(&models.Account{}).Where("id = ? AND user_id = ?", entityId, userId).Count(&count)
+	case "transaction":
+		db.Unscoped().Model(&models.Transaction{}).Where("id = ?", entityId).Scopes(scopes.UserTransactions(int(userId))).Count(&count)
+	case "budget":
+		db.Unscoped().Model(&models.Budget{}).Where("id = ? AND user_id = ?", entityId, userId).Count(&count)
+	case "category", "tag":
+		return scopes.GetActorAuditLog(userId, db).Where("entity = ? AND entity_id = ?", entity, entityId), nil
+	default:
+		return nil, errors.UnknownAuditEntityError(entity)
+	}
+	if count == 0 {
+		return nil, errors.AuditRecordNotFoundError()
+	}
+	return scopes.GetEntityAuditLog(entity, entityId, db), nil
+}
+
+func respondWithAuditLog(c *gin.Context, query *gorm.DB) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	paginator := pagination.Pagination{Page: page, Limit: limit}
+	var entries []models.AuditLog
+	if err := paginator.Paginate(query, models.AuditLog{}).Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	results, err := serializers.NewAuditLogSerializer(entries, true).Serialize()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pagination.Response{
+		Results:    results,
+		NextPage:   paginator.NextPage(),
+		PrevPage:   paginator.PrevPage(),
+		TotalCount: paginator.TotalCount,
+		Limit:      paginator.Limit,
+		Page:       paginator.Page,
+		LastPage:   paginator.LastPage(),
+	})
+}
@@ -6,32 +6,55 @@ import (
 	"github.com/christo-andrew/haven/internal/api/serializers"
 	"github.com/christo-andrew/haven/internal/models"
 	"github.com/christo-andrew/haven/pkg/auth"
+	"github.com/christo-andrew/haven/pkg/budgets"
+	"github.com/christo-andrew/haven/pkg/errors"
+	"github.com/christo-andrew/haven/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 // CreateBudgetHandler CreateBudget godoc
 // @Summary Create a budget
-// @Description Create a budget
+// @Description Create a budget covering either one category, or several categories listed in categories with a limit each.
+// @Description The amount of a budget with categories is the sum of their limits.
 // @Param budget body requests.CreateOrUpdateBudgetRequest true "Budget"
 // @Accept json
 // @Produce json
 // @Success 201 {object} responses.BudgetResponse
+// @Failure 400 {object} responses.ErrorResponse
 // @Router /budgets/create [post]
 // @Tags budgets
 // @Security AuthToken
 // @Param Authorization header string true "Authorization"
-func CreateBudgetHandler(c *gin.Context, db *gorm.DB) {
+func CreateBudgetHandler(c *gin.Context, db *gorm.DB, tracker *budgets.Tracker) {
 	userId := auth.GetUserIdFromContext(c)
 	var createOrUpdateBudgetRequest requests.CreateOrUpdateBudgetRequest
 	if err := c.ShouldBindJSON(&createOrUpdateBudgetRequest); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if err := createOrUpdateBudgetRequest.Validate(false); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	lines := createOrUpdateBudgetRequest.Lines()
+	if err := checkBudgetLines(lines, uint(userId), db); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := checkBudgetCategory(createOrUpdateBudgetRequest.BudgetCategoryID, uint(userId), db); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	budget := createOrUpdateBudgetRequest.Budget()
 	budget.UserId = uint(userId)
-	budget, err := createBudget(budget, db)
+	budget, err := createBudget(budget, lines, db)
+	if err == nil {
+		err = tracker.Fill(budget)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -42,67 +65,134 @@ func CreateBudgetHandler(c *gin.Context, db *gorm.DB) {
 
 // UpdateBudgetHandler UpdateBudget godoc
 // @Summary Update a budget
-// @Description Update a budget
+// @Description Update a budget. Fields left out keep their values; categories, when given, replace the lines of the budget.
 // @Param id path int true "Budget ID"
 // @Param budget body requests.CreateOrUpdateBudgetRequest true "Budget"
 // @Accept json
 // @Produce json
 // @Success 200 {object} responses.BudgetResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
 // @Router /budgets/{id}/update [put]
 // @Tags budgets
 // @Security AuthToken
 // @Param Authorization header string true "Authorization"
-func UpdateBudgetHandler(c *gin.Context, db *gorm.DB) {
+func UpdateBudgetHandler(c *gin.Context, db *gorm.DB, tracker *budgets.Tracker) {
+	userId := uint(auth.GetUserIdFromContext(c))
 	var updateBudgetRequest requests.CreateOrUpdateBudgetRequest
 	if err := c.ShouldBindJSON(&updateBudgetRequest); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	var budget models.Budget
-	id := c.Param("id")
-	db.First(&budget, id)
-	db.Model(&budget).Updates(updateBudgetRequest.Budget())
+	id, _ := strconv.Atoi(c.Param("id"))
+	budget, err := getUserBudget(id, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	updateBudgetRequest.KeepUnset(budget)
+	var lineCount int64
+	db.Model(&models.BudgetCategory{}).Where("budget_id = ?", budget.ID).Count(&lineCount)
+	if err := updateBudgetRequest.Validate(lineCount > 0); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	lines := updateBudgetRequest.Lines()
+	if err := checkBudgetLines(lines, userId, db); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := checkBudgetCategory(updateBudgetRequest.BudgetCategoryID, userId, db); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&budget).Updates(updateBudgetRequest.Updates()).Error; err != nil {
+			return err
+		}
+		if err := syncOpenBudgetPeriod(&budget, tx); err != nil {
+			return err
+		}
+		if len(lines) > 0 {
+			if err := tx.Where("budget_id = ?", budget.ID).Delete(&models.BudgetCategory{}).Error; err != nil {
+				return err
+			}
+			if err := createBudgetLines(&budget, lines, tx); err != nil {
+				return err
+			}
+		}
+		return syncBudgetAmount(&budget, tx)
+	})
+	if err == nil {
+		err = tracker.Fill(&budget)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
 	c.JSON(http.StatusOK, responses.BudgetResponse{}.FromBudget(budget))
 }
 
 // GetBudgetHandler GetBudget godoc
 // @Summary Get a budget
-// @Description Retrieve a budget
+// @Description Retrieve a budget with how much of it has been spent. Spend counts the budget's category and the categories below it,
+// @Description along with anything carrying one of the budget's tags, between the start and end dates. Refunds reduce it and transfers are left out.
+// @Description The forecast projects the spend at the end date from the run rate so far, or from how spending built up over the same
+// @Description stretch of the periods before when there was spending then, with the day the limit is likely to be reached.
 // @Produce json
 // @Success 200 {object} responses.BudgetResponse
+// @Failure 404 {object} responses.ErrorResponse
 // @Router /budgets/{id} [get]
 // @Param id path int true "Budget ID"
 // @Tags budgets
 // @Security AuthToken
 // @Param Authorization header string true "Authorization"
-func GetBudgetHandler(c *gin.Context, db *gorm.DB) {
-	var budget models.Budget
-	id := c.Param("id")
-	db.First(&budget, id)
-	response, err := serializers.NewBudgetSerializer(budget, false).Serialize()
+func GetBudgetHandler(c *gin.Context, db *gorm.DB, tracker *budgets.Tracker) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	id, _ := strconv.Atoi(c.Param("id"))
+	budget, err := getUserBudget(id, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err := tracker.Fill(&budget); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	forecast, err := tracker.Forecast(&budget, time.Now().UTC())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	response := responses.BudgetResponse{}.FromBudget(budget)
+	response.Forecast = responses.BudgetForecastResponse{}.FromForecast(forecast)
 	c.JSON(http.StatusOK, response)
 }
 
 // GetBudgetsHandler GetBudgets godoc
 // @Summary Get all budgets for a user
-// @Description Retrieve all budgets for a user
+// @Description Retrieve all budgets for a user with how much of each has been spent
 // @Produce json
 // @Success 200 {array} responses.BudgetResponse
 // @Router /budgets [get]
 // @Tags budgets
 // @Security AuthToken
 // @Param Authorization header string true "Authorization"
-func GetBudgetsHandler(c *gin.Context, db *gorm.DB) {
+func GetBudgetsHandler(c *gin.Context, db *gorm.DB, tracker *budgets.Tracker) {
 	userId := auth.GetUserIdFromContext(c)
-	var budgets []models.Budget
-	db.Where("user_id = ?", userId).Find(&budgets)
-	result, err := serializers.NewBudgetSerializer(budgets, true).Serialize()
+	var userBudgets []models.Budget
+	db.Where("user_id = ?", userId).Find(&userBudgets)
+	filled := make([]*models.Budget, len(userBudgets))
+	for i := range userBudgets {
+		filled[i] = &userBudgets[i]
+	}
+	if err := tracker.Fill(filled...); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := serializers.NewBudgetSerializer(userBudgets, true).Serialize()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -110,63 +200,222 @@ func GetBudgetsHandler(c *gin.Context, db *gorm.DB) {
 	c.JSON(http.StatusOK, result)
 }
 
+// GetBudgetPeriodsHandler GetBudgetPeriods godoc
+// @Summary Get the periods of a budget
+// @Description Retrieve the periods of a recurring budget, oldest first, with the limit, spend and carryover of each.
+// @Description Closed periods keep what was spent in them, the open period is spent so far.
+// @Produce json
+// @Param id path int true "Budget ID"
+// @Success 200 {array} responses.BudgetPeriodResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /budgets/{id}/periods [get]
+// @Tags budgets
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetBudgetPeriodsHandler(c *gin.Context, db *gorm.DB, tracker *budgets.Tracker) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	id, _ := strconv.Atoi(c.Param("id"))
+	budget, err := getUserBudget(id, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err := tracker.Fill(&budget); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	var periods []models.BudgetPeriod
+	db.Where("budget_id = ?", budget.ID).Order("start_date").Find(&periods)
+	result := []*responses.BudgetPeriodResponse{}
+	for _, period := range periods {
+		if !period.Closed && period.StartDate.Equal(budget.StartDate) {
+			period.Amount = budget.Amount
+			period.SpentAmount = budget.SpentAmount
+		}
+		result = append(result, responses.BudgetPeriodResponse{}.FromBudgetPeriod(period))
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetBudgetTagsHandler GetBudgetTags godoc
+// @Summary Get all tags for a budget
+// @Description Retrieve all tags for a budget
+// @Produce json
+// @Param id path int true "Budget ID"
+// @Success 200 {array} responses.TagResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /budgets/{id}/tags [get]
+// @Tags budgets
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetBudgetTagsHandler(ctx *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(ctx))
+	budgetId, _ := strconv.Atoi(ctx.Param("id"))
+	budget, err := getUserBudget(budgetId, userId, db)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	tags, err := attachedTags(&budget, db)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, serializers.NewTagSerializer(tags, true).Serialize())
+}
+
 // AddBudgetTagHandler AddBudgetTag godoc
 // @Summary Add a tag to a budget
-// @Description Add a tag to a budget
-// @Param id path int true "Budget ID"
-// @Param tag_id path int true "Tag ID"
+// @Description Add a tag to a budget, creating the tag when no tag has that name yet
+// @Accept json
 // @Produce json
-// @Success 200 {object} responses.TagResponse
-// @Router /budgets/{id}/tags/ [post]
+// @Param id path int true "Budget ID"
+// @Param tag body requests.CreateTagRequest true "Create Tag Request"
+// @Success 201 {object} responses.TagResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /budgets/{id}/tags [post]
 // @Tags budgets
 // @Security AuthToken
 // @Param Authorization header string true "Authorization"
 func AddBudgetTagHandler(ctx *gin.Context, db *gorm.DB) {
-	var budget models.Budget
-	id := ctx.Param("id")
-	db.First(&budget, id)
-	var tag models.Tag
-	tagId := ctx.Param("tag_id")
-	db.First(&tag, tagId)
-	err := db.Model(&budget).Association("Tags").Append(&tag)
+	userId := uint(auth.GetUserIdFromContext(ctx))
+	budgetId, _ := strconv.Atoi(ctx.Param("id"))
+	tag, err := requestedTag(ctx, userId, db)
 	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	budget, err := getUserBudget(budgetId, userId, db)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err := db.Model(&budget).Association("Tags").Append(tag); err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	ctx.JSON(http.StatusOK, responses.TagResponse{}.FromTag(tag))
+	ctx.JSON(http.StatusCreated, responses.TagResponse{}.FromTag(*tag))
 }
 
-func RemoveBudgetTagHandler(ctx *gin.Context, db *gorm.DB) error {
-	var budget models.Budget
-	id := ctx.Param("id")
-	db.First(&budget, id)
-	var tag models.Tag
-	tagId := ctx.Param("tag_id")
-	db.First(&tag, tagId)
-	err := db.Model(&budget).Association("Tags").Delete(&tag)
+// RemoveBudgetTagHandler RemoveBudgetTag godoc
+// @Summary Remove a tag from a budget
+// @Description Remove a tag from a budget. The tag itself is kept.
+// @Produce json
+// @Param id path int true "Budget ID"
+// @Param tag_id path int true "Tag ID"
+// @Success 200 {array} responses.TagResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /budgets/{id}/tags/{tag_id} [delete]
+// @Tags budgets
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func RemoveBudgetTagHandler(ctx *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(ctx))
+	budgetId, _ := strconv.Atoi(ctx.Param("id"))
+	budget, err := getUserBudget(budgetId, userId, db)
 	if err != nil {
-		return err
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
 	}
-	return nil
+	tags, err := detachTag(&budget, ctx.Param("tag_id"), db)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, serializers.NewTagSerializer(tags, true).Serialize())
 }
 
-func GetBudgetTagsHandler(ctx *gin.Context, db *gorm.DB) ([]models.Tag, error) {
+func getUserBudget(budgetId int, userId uint, db *gorm.DB) (models.Budget, error) {
 	var budget models.Budget
-	id := ctx.Param("id")
-	db.First(&budget, id)
-	var tags []models.Tag
-	err := db.Model(&budget).Association("Tags").Find(&tags)
-	if err != nil {
-		return tags, err
+	db.Where("user_id = ?", userId).First(&budget, budgetId)
+	if budget.ID == 0 {
+		return budget, errors.BudgetNotFoundError()
 	}
-	return tags, nil
+	return budget, nil
+}
+
+func createBudget(budget *models.Budget, lines []models.BudgetCategory, db *gorm.DB) (*models.Budget, error) {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(budget).Error; err != nil {
+			return err
+		}
+		if budget.Recurring {
+			if _, err := budgets.CurrentPeriod(budget, tx); err != nil {
+				return err
+			}
+		}
+		return createBudgetLines(budget, lines, tx)
+	})
+	return budget, err
 }
 
-func createBudget(budget *models.Budget, db *gorm.DB) (*models.Budget, error) {
-	result := db.Create(&budget)
-	if result.Error != nil {
-		return budget, result.Error
+func createBudgetLines(budget *models.Budget, lines []models.BudgetCategory, db *gorm.DB) error {
+	for i := range lines {
+		lines[i].BudgetID = int(budget.ID)
+	}
+	if len(lines) == 0 {
+		return nil
 	}
+	return db.Omit("Budget", "Category").Create(&lines).Error
+}
 
-	return budget, nil
+// checkBudgetLines makes sure the categories of budget lines are ones the
+// user can see, and the goals they are linked to the user's.
+func checkBudgetLines(lines []models.BudgetCategory, userId uint, db *gorm.DB) error {
+	for _, line := range lines {
+		if _, err := getCategory(line.CategoryID, userId, db); err != nil {
+			return err
+		}
+		if line.GoalID != nil {
+			if _, err := getUserGoal(int(*line.GoalID), userId, db); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkBudgetCategory makes sure the category a budget covers, when it has
+// one, is a category the user can see.
+func checkBudgetCategory(categoryId uint, userId uint, db *gorm.DB) error {
+	if categoryId == 0 {
+		return nil
+	}
+	_, err := getCategory(int(categoryId), userId, db)
+	return err
+}
+
+// syncOpenBudgetPeriod moves the open period of a recurring budget to the
+// budget's dates.
+func syncOpenBudgetPeriod(budget *models.Budget, db *gorm.DB) error {
+	if !budget.Recurring {
+		return nil
+	}
+	err := db.Model(&models.BudgetPeriod{}).
+		Where("budget_id = ? AND closed = ?", budget.ID, false).
+		Updates(map[string]interface{}{"start_date": budget.StartDate, "end_date": budget.EndDate}).Error
+	if err != nil {
+		return err
+	}
+	_, err = budgets.CurrentPeriod(budget, db)
+	return err
+}
+
+// syncBudgetAmount sets the amount of a budget with lines to the sum of their
+// limits. A budget without lines keeps its own amount.
+func syncBudgetAmount(budget *models.Budget, db *gorm.DB) error {
+	var lines []models.BudgetCategory
+	if err := db.Where("budget_id = ?", budget.ID).Find(&lines).Error; err != nil {
+		return err
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	var amount float64
+	for _, line := range lines {
+		amount += line.Amount
+	}
+	budget.Amount = utils.RoundToCents(amount)
+	return db.Model(budget).Update("amount", budget.Amount).Error
 }
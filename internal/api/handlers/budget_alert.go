@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/christo-andrew/haven/internal/api/requests"
+	"github.com/christo-andrew/haven/internal/api/responses"
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/auth"
+	"github.com/christo-andrew/haven/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetBudgetAlertsHandler GetBudgetAlerts godoc
+// @Summary Get the alerts of a budget
+// @Description List the alerts of a budget, with the start of the period each last fired in
+// @Produce json
+// @Param id path int true "Budget ID"
+// @Success 200 {array} responses.BudgetAlertResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /budgets/{id}/alerts [get]
+// @Tags budgets
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetBudgetAlertsHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	budgetId, _ := strconv.Atoi(c.Param("id"))
+	budget, err := getUserBudget(budgetId, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	var alerts []models.BudgetAlert
+	db.Where("budget_id = ?", budget.ID).Order("id").Find(&alerts)
+	result := []*responses.BudgetAlertResponse{}
+	for _, alert := range alerts {
+		result = append(result, responses.BudgetAlertResponse{}.FromBudgetAlert(alert))
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// CreateBudgetAlertHandler CreateBudgetAlert godoc
+// @Summary Create an alert for a budget
+// @Description Notify the user when spending reaches a percentage of the budget's limit, or is projected to at its current daily rate.
+// @Description Alerts are checked whenever transactions are created or imported and on a schedule, and fire once per budget period.
+// @Description They are delivered to the in-app notification feed, by email or to a webhook.
+// @Accept json
+// @Produce json
+// @Param id path int true "Budget ID"
+// @Param alert body requests.BudgetAlertRequest true "Budget Alert Request"
+// @Success 201 {object} responses.BudgetAlertResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /budgets/{id}/alerts [post]
+// @Tags budgets
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func CreateBudgetAlertHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	budgetId, _ := strconv.Atoi(c.Param("id"))
+	var alertRequest requests.BudgetAlertRequest
+	if err := c.ShouldBindJSON(&alertRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	budget, err := getUserBudget(budgetId, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err := checkBudgetAlert(budget, alertRequest, db); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	alert := alertRequest.BudgetAlert(budget.ID, userId)
+	if err := db.Create(&alert).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, responses.BudgetAlertResponse{}.FromBudgetAlert(alert))
+}
+
+// UpdateBudgetAlertHandler UpdateBudgetAlert godoc
+// @Summary Update an alert of a budget
+// @Description Replace the settings of an alert. The alert can fire again in the current period.
+// @Accept json
+// @Produce json
+// @Param id path int true "Budget ID"
+// @Param alert_id path int true "Alert ID"
+// @Param alert body requests.BudgetAlertRequest true "Budget Alert Request"
+// @Success 200 {object} responses.BudgetAlertResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /budgets/{id}/alerts/{alert_id} [put]
+// @Tags budgets
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func UpdateBudgetAlertHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	budgetId, _ := strconv.Atoi(c.Param("id"))
+	alertId, _ := strconv.Atoi(c.Param("alert_id"))
+	var alertRequest requests.BudgetAlertRequest
+	if err := c.ShouldBindJSON(&alertRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	budget, alert, err := getBudgetAlert(budgetId, alertId, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err := checkBudgetAlert(budget, alertRequest, db); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	updated := alertRequest.BudgetAlert(budget.ID, userId)
+	err = db.Model(&alert).Updates(map[string]interface{}{
+		"category_id":      updated.CategoryID,
+		"type":             updated.Type,
+		"threshold":        updated.Threshold,
+		"channels":         updated.Channels,
+		"webhook_url":      updated.WebhookURL,
+		"active":           updated.Active,
+		"fired_period":     nil,
+		"pending_channels": "",
+	}).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	db.First(&alert, alert.ID)
+	c.JSON(http.StatusOK, responses.BudgetAlertResponse{}.FromBudgetAlert(alert))
+}
+
+// DeleteBudgetAlertHandler DeleteBudgetAlert godoc
+// @Summary Delete an alert of a budget
+// @Description Delete an alert of a budget
+// @Param id path int true "Budget ID"
+// @Param alert_id path int true "Alert ID"
+// @Success 204
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /budgets/{id}/alerts/{alert_id} [delete]
+// @Tags budgets
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func DeleteBudgetAlertHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	budgetId, _ := strconv.Atoi(c.Param("id"))
+	alertId, _ := strconv.Atoi(c.Param("alert_id"))
+	_, alert, err := getBudgetAlert(budgetId, alertId, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err := db.Delete(&alert).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func getBudgetAlert(budgetId int, alertId int, userId uint, db *gorm.DB) (models.Budget, models.BudgetAlert, error) {
+	var alert models.BudgetAlert
+	budget, err := getUserBudget(budgetId, userId, db)
+	if err != nil {
+		return budget, alert, err
+	}
+	db.Where("budget_id = ?", budget.ID).First(&alert, alertId)
+	if alert.ID == 0 {
+		return budget, alert, errors.BudgetAlertNotFoundError()
+	}
+	return budget, alert, nil
+}
+
+// checkBudgetAlert makes sure an alert watching a line of a budget names one
+// of the budget's categories.
+func checkBudgetAlert(budget models.Budget, alertRequest requests.BudgetAlertRequest, db *gorm.DB) error {
+	if err := alertRequest.Validate(); err != nil {
+		return err
+	}
+	if alertRequest.CategoryID == nil {
+		return nil
+	}
+	var lines int64
+	db.Model(&models.BudgetCategory{}).Where("budget_id = ? AND category_id = ?", budget.ID, *alertRequest.CategoryID).Count(&lines)
+	if lines == 0 {
+		return errors.AlertCategoryNotInBudgetError()
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/christo-andrew/haven/internal/api/requests"
+	"github.com/christo-andrew/haven/internal/api/responses"
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/auth"
+	"github.com/christo-andrew/haven/pkg/budgets"
+	"github.com/christo-andrew/haven/pkg/errors"
+	"github.com/christo-andrew/haven/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetBudgetCategoriesHandler GetBudgetCategories godoc
+// @Summary Get the categories of a budget
+// @Description List the lines of a budget, each with its limit and how much of it has been spent
+// @Produce json
+// @Param id path int true "Budget ID"
+// @Success 200 {array} responses.BudgetCategoryResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /budgets/{id}/categories [get]
+// @Tags budgets
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetBudgetCategoriesHandler(c *gin.Context, db *gorm.DB, tracker *budgets.Tracker) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	budgetId, _ := strconv.Atoi(c.Param("id"))
+	budget, err := getUserBudget(budgetId, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err := tracker.Fill(&budget); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, responses.BudgetResponse{}.FromBudget(budget).Categories)
+}
+
+// AddBudgetCategoryHandler AddBudgetCategory godoc
+// @Summary Add a category to a budget
+// @Description Add a line to a budget with a limit of its own. The amount of the budget becomes the sum of its lines.
+// @Accept json
+// @Produce json
+// @Param id path int true "Budget ID"
+// @Param category body requests.BudgetCategoryRequest true "Budget Category Request"
+// @Success 201 {object} responses.BudgetCategoryResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /budgets/{id}/categories [post]
+// @Tags budgets
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func AddBudgetCategoryHandler(c *gin.Context, db *gorm.DB, tracker *budgets.Tracker) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	budgetId, _ := strconv.Atoi(c.Param("id"))
+	var budgetCategoryRequest requests.BudgetCategoryRequest
+	if err := c.ShouldBindJSON(&budgetCategoryRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	budget, err := getUserBudget(budgetId, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	line := budgetCategoryRequest.BudgetCategory()
+	if err := checkBudgetLine(budget, line, userId, db); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	lines := []models.BudgetCategory{line}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := createBudgetLines(&budget, lines, tx); err != nil {
+			return err
+		}
+		return syncBudgetAmount(&budget, tx)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	respondWithBudgetCategory(c, http.StatusCreated, budget, lines[0].ID, tracker)
+}
+
+// UpdateBudgetCategoryHandler UpdateBudgetCategory godoc
+// @Summary Update a category of a budget
+// @Description Change the category, the limit or the goal of a budget line. The amount of the budget becomes the sum of its lines.
+// @Accept json
+// @Produce json
+// @Param id path int true "Budget ID"
+// @Param line_id path int true "Budget category ID"
+// @Param category body requests.BudgetCategoryRequest true "Budget Category Request"
+// @Success 200 {object} responses.BudgetCategoryResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /budgets/{id}/categories/{line_id} [put]
+// @Tags budgets
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func UpdateBudgetCategoryHandler(c *gin.Context, db *gorm.DB, tracker *budgets.Tracker) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	budgetId, _ := strconv.Atoi(c.Param("id"))
+	lineId, _ := strconv.Atoi(c.Param("line_id"))
+	var budgetCategoryRequest requests.BudgetCategoryRequest
+	if err := c.ShouldBindJSON(&budgetCategoryRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	budget, line, err := getBudgetLine(budgetId, lineId, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	line.CategoryID = budgetCategoryRequest.CategoryID
+	line.Amount = budgetCategoryRequest.Amount
+	line.GoalID = budgetCategoryRequest.GoalID
+	if err := checkBudgetLine(budget, line, userId, db); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&line).Updates(map[string]interface{}{
+			"category_id": line.CategoryID,
+			"amount":      line.Amount,
+			"goal_id":     line.GoalID,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return syncBudgetAmount(&budget, tx)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	respondWithBudgetCategory(c, http.StatusOK, budget, line.ID, tracker)
+}
+
+// DeleteBudgetCategoryHandler DeleteBudgetCategory godoc
+// @Summary Remove a category from a budget
+// @Description Remove a line from a budget. The amount of the budget becomes the sum of the remaining lines; a budget left without lines keeps its last amount.
+// @Produce json
+// @Param id path int true "Budget ID"
+// @Param line_id path int true "Budget category ID"
+// @Success 200 {array} responses.BudgetCategoryResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /budgets/{id}/categories/{line_id} [delete]
+// @Tags budgets
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func DeleteBudgetCategoryHandler(c *gin.Context, db *gorm.DB, tracker *budgets.Tracker) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	budgetId, _ := strconv.Atoi(c.Param("id"))
+	lineId, _ := strconv.Atoi(c.Param("line_id"))
+	budget, line, err := getBudgetLine(budgetId, lineId, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&line).Error; err != nil {
+			return err
+		}
+		return syncBudgetAmount(&budget, tx)
+	})
+	if err == nil {
+		err = tracker.Fill(&budget)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, responses.BudgetResponse{}.FromBudget(budget).Categories)
+}
+
+func getBudgetLine(budgetId int, lineId int, userId uint, db *gorm.DB) (models.Budget, models.BudgetCategory, error) {
+	var line models.BudgetCategory
+	budget, err := getUserBudget(budgetId, userId, db)
+	if err != nil {
+		return budget, line, err
+	}
+	db.Where("budget_id = ?", budget.ID).First(&line, lineId)
+	if line.ID == 0 {
+		return budget, line, errors.BudgetCategoryNotFoundError()
+	}
+	return budget, line, nil
+}
+
+// checkBudgetLine makes sure a line is for a category the user can see that
+// no other line of the budget has.
+func checkBudgetLine(budget models.Budget, line models.BudgetCategory, userId uint, db *gorm.DB) error {
+	if err := checkBudgetLines([]models.BudgetCategory{line}, userId, db); err != nil {
+		return err
+	}
+	var taken int64
+	db.Model(&models.BudgetCategory{}).
+		Where("budget_id = ? AND category_id = ? AND id <> ?", budget.ID, line.CategoryID, line.ID).
+		Count(&taken)
+	if taken > 0 {
+		return errors.DuplicateBudgetCategoryError()
+	}
+	return nil
+}
+
+// mergeBudgetLines moves the budget lines of the source categories to the
+// target category. A budget that already has a line for the target gets the
+// amount of the source line added to it and loses the source line, keeping
+// one line per category.
+func mergeBudgetLines(sourceIds []int, targetId int, db *gorm.DB) error {
+	var merged []models.BudgetCategory
+	if err := db.Where("category_id IN ?", sourceIds).Order("id").Find(&merged).Error; err != nil {
+		return err
+	}
+	for _, line := range merged {
+		var target models.BudgetCategory
+		db.Where("budget_id = ? AND category_id = ?", line.BudgetID, targetId).First(&target)
+		if target.ID == 0 {
+			if err := db.Model(&line).Update("category_id", targetId).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		updates := map[string]interface{}{"amount": utils.RoundToCents(target.Amount + line.Amount)}
+		if target.GoalID == nil && line.GoalID != nil {
+			updates["goal_id"] = line.GoalID
+		}
+		if err := db.Model(&target).Updates(updates).Error; err != nil {
+			return err
+		}
+		if err := db.Delete(&line).Error; err != nil {
+			return err
+		}
+		var budget models.Budget
+		if err := db.First(&budget, line.BudgetID).Error; err != nil {
+			return err
+		}
+		if err := syncBudgetAmount(&budget, db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func respondWithBudgetCategory(c *gin.Context, status int, budget models.Budget, lineId uint, tracker *budgets.Tracker) {
+	if err := tracker.Fill(&budget); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for _, line := range budget.Categories {
+		if line.ID == lineId {
+			c.JSON(status, responses.BudgetCategoryResponse{}.FromBudgetCategory(line))
+			return
+		}
+	}
+	c.JSON(http.StatusNotFound, gin.H{"error": errors.BudgetCategoryNotFoundError().Error()})
+}
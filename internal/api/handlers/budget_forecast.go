@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/api/responses"
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/auth"
+	"github.com/christo-andrew/haven/pkg/budgets"
+	"github.com/christo-andrew/haven/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetBudgetForecastsHandler GetBudgetForecasts godoc
+// @Summary Get the forecasts of running budgets
+// @Description Forecast the spend at the end of each budget running today, with a warning for those likely to go over their limit.
+// @Description Budgets heading over come first, the furthest over first, followed by the rest with the least to spare first.
+// @Produce json
+// @Success 200 {object} responses.BudgetForecastSummaryResponse
+// @Router /budgets/forecast [get]
+// @Tags budgets
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetBudgetForecastsHandler(c *gin.Context, db *gorm.DB, tracker *budgets.Tracker) {
+	userId := auth.GetUserIdFromContext(c)
+	now := time.Now().UTC()
+	today := now.Truncate(24 * time.Hour)
+	var userBudgets []models.Budget
+	err := db.Where("user_id = ? AND start_date <= ? AND end_date >= ?", userId, today, today).Find(&userBudgets).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	filled := make([]*models.Budget, len(userBudgets))
+	for i := range userBudgets {
+		filled[i] = &userBudgets[i]
+	}
+	if err := tracker.Fill(filled...); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	summary := responses.BudgetForecastSummaryResponse{Budgets: []responses.BudgetForecastEntryResponse{}}
+	for _, budget := range filled {
+		forecast, err := tracker.Forecast(budget, now)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if forecast.WillExceed {
+			summary.AtRisk++
+		}
+		summary.Budgets = append(summary.Budgets, responses.BudgetForecastEntryResponse{
+			BudgetID:      budget.ID,
+			Name:          budget.Name,
+			EndDate:       budget.EndDate.Format("2006-01-02"),
+			Limit:         utils.RoundToCents(budget.Limit()),
+			SpentAmount:   budget.SpentAmount,
+			DaysRemaining: budget.DaysRemaining(),
+			Forecast:      *responses.BudgetForecastResponse{}.FromForecast(forecast),
+			Warning:       forecast.Warning(),
+		})
+	}
+	sort.SliceStable(summary.Budgets, func(i, j int) bool {
+		a, b := summary.Budgets[i].Forecast, summary.Budgets[j].Forecast
+		if a.WillExceed != b.WillExceed {
+			return a.WillExceed
+		}
+		return a.ProjectedVariance < b.ProjectedVariance
+	})
+	c.JSON(http.StatusOK, summary)
+}
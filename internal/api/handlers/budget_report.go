@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/christo-andrew/haven/internal/api/requests"
+	"github.com/christo-andrew/haven/internal/api/responses"
+	"github.com/christo-andrew/haven/pkg/auth"
+	"github.com/christo-andrew/haven/pkg/budgets"
+	"github.com/christo-andrew/haven/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetBudgetReportHandler GetBudgetReport godoc
+// @Summary Get a budget performance report
+// @Description Report the limit, actual spend, variance and percentage used of each budget and each of its categories, period by period.
+// @Description A budget running for part of a period has the part of its limit for the days it runs. The report can be downloaded
+// @Description as CSV, or as a PDF with a bar chart and a table per budget. A report covers at most 120 periods.
+// @Produce json
+// @Produce text/csv
+// @Produce application/pdf
+// @Param from query string false "First day of the report, YYYY-MM-DD, the start of the year if not given"
+// @Param to query string false "Last day of the report, YYYY-MM-DD, today if not given"
+// @Param period query string false "Period to report by" Enums(weekly, monthly, quarterly, yearly)
+// @Param format query string false "Format of the report" Enums(json, csv, pdf)
+// @Success 200 {object} responses.BudgetReportResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Router /budgets/reports [get]
+// @Tags budgets
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetBudgetReportHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	from, to, period, err := requests.BudgetReportQuery(c.Query("from"), c.Query("to"), c.Query("period"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" && format != "pdf" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.InvalidReportFormatError(format).Error()})
+		return
+	}
+	report, err := budgets.BuildReport(userId, from, to, period, db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if format == "json" {
+		c.JSON(http.StatusOK, responses.BudgetReportResponse{}.FromReport(report))
+		return
+	}
+
+	var content bytes.Buffer
+	contentType := "text/csv; charset=utf-8"
+	if format == "pdf" {
+		contentType = "application/pdf"
+		err = report.WritePDF(&content)
+	} else {
+		err = report.WriteCSV(&content)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	fileName := fmt.Sprintf("budget-report-%s-%s.%s", from.Format("20060102"), to.Format("20060102"), format)
+	c.DataFromReader(http.StatusOK, int64(content.Len()), contentType, &content, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": fileName}),
+		"Cache-Control":       "private, no-store",
+	})
+}
@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/api/requests"
+	"github.com/christo-andrew/haven/internal/api/responses"
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/auth"
+	"github.com/christo-andrew/haven/pkg/budgets"
+	"github.com/christo-andrew/haven/pkg/errors"
+	"github.com/christo-andrew/haven/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// presetHistoryMonths is how many months of spending split the amount of a
+// preset's group between its categories.
+const presetHistoryMonths = 3
+
+// GetBudgetTemplatesHandler GetBudgetTemplates godoc
+// @Summary Get budget templates
+// @Description Retrieve the built-in presets, such as 50/30/20, and the templates the user saved.
+// @Produce json
+// @Success 200 {object} responses.BudgetTemplatesResponse
+// @Router /budgets/templates [get]
+// @Tags budgets
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetBudgetTemplatesHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	var templates []models.BudgetTemplate
+	db.Preload("Lines").Where("user_id = ?", userId).Order("name").Find(&templates)
+	result := responses.BudgetTemplatesResponse{
+		Presets:   []responses.BudgetPresetResponse{},
+		Templates: []*responses.BudgetTemplateResponse{},
+	}
+	for _, preset := range budgets.Presets {
+		result.Presets = append(result.Presets, responses.BudgetPresetResponse{}.FromPreset(preset))
+	}
+	for _, template := range templates {
+		result.Templates = append(result.Templates, responses.BudgetTemplateResponse{}.FromBudgetTemplate(template))
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// CreateBudgetTemplateHandler CreateBudgetTemplate godoc
+// @Summary Save a budget template
+// @Description Save a template to start budgets from. Each category has either a fixed amount or a percentage of the income
+// @Description the budget is drafted for.
+// @Param template body requests.BudgetTemplateRequest true "Template"
+// @Accept json
+// @Produce json
+// @Success 201 {object} responses.BudgetTemplateResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Router /budgets/templates [post]
+// @Tags budgets
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func CreateBudgetTemplateHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	var templateRequest requests.BudgetTemplateRequest
+	if err := c.ShouldBindJSON(&templateRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := templateRequest.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	template := templateRequest.BudgetTemplate(userId)
+	for _, line := range template.Lines {
+		if _, err := getCategory(line.CategoryID, userId, db); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if err := db.Create(template).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, responses.BudgetTemplateResponse{}.FromBudgetTemplate(*template))
+}
+
+// DeleteBudgetTemplateHandler DeleteBudgetTemplate godoc
+// @Summary Delete a budget template
+// @Description Delete a saved template. Budgets started from it are kept.
+// @Param template_id path int true "Template ID"
+// @Success 204
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /budgets/templates/{template_id} [delete]
+// @Tags budgets
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func DeleteBudgetTemplateHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	id, _ := strconv.Atoi(c.Param("template_id"))
+	template, err := getUserBudgetTemplate(id, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("budget_template_id = ?", template.ID).Delete(&models.BudgetTemplateLine{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&template).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// DraftBudgetFromTemplateHandler DraftBudgetFromTemplate godoc
+// @Summary Draft a budget from a template
+// @Description Work out the limits of a monthly recurring budget from a saved template, or from a preset given by its key.
+// @Description Percentages are taken of income. A preset splits income between its groups, and the amount of each group
+// @Description between the categories given for it in proportion to what was spent in them over the last three months.
+// @Description Nothing is saved: the draft can be changed and then sent to /budgets/create.
+// @Param template_id path string true "Template ID or preset key"
+// @Param draft body requests.BudgetDraftRequest true "Draft"
+// @Accept json
+// @Produce json
+// @Success 200 {object} requests.CreateOrUpdateBudgetRequest
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /budgets/templates/{template_id}/draft [post]
+// @Tags budgets
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func DraftBudgetFromTemplateHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	var draftRequest requests.BudgetDraftRequest
+	if err := c.ShouldBindJSON(&draftRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	startDate, err := draftRequest.GetStartDate()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var name, period, rollover string
+	var lines []models.BudgetCategory
+	if preset, ok := budgets.FindPreset(c.Param("template_id")); ok {
+		if err := draftRequest.ValidateGroups(preset); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if draftRequest.Income <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errors.IncomeRequiredError().Error()})
+			return
+		}
+		for _, categoryIds := range draftRequest.Groups {
+			for _, categoryId := range categoryIds {
+				if _, err := getCategory(categoryId, userId, db); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+			}
+		}
+		suggestions, err := budgets.Suggest(userId, presetHistoryMonths, budgets.SuggestMedian, time.Now().UTC(), db)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		weights := make(map[int]float64)
+		for _, suggestion := range suggestions {
+			weights[suggestion.CategoryID] = suggestion.Amount
+		}
+		name, period, rollover = preset.Name, models.BudgetPeriodMonthly, models.BudgetRolloverNone
+		lines = preset.Lines(draftRequest.Income, draftRequest.Groups, weights)
+	} else {
+		id, _ := strconv.Atoi(c.Param("template_id"))
+		template, err := getUserBudgetTemplate(id, userId, db)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		for _, line := range template.Lines {
+			if line.Percentage > 0 && draftRequest.Income <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": errors.IncomeRequiredError().Error()})
+				return
+			}
+		}
+		name, period, rollover = template.Name, template.Period, template.Rollover
+		lines = budgets.TemplateLines(template, draftRequest.Income)
+	}
+	if draftRequest.Name != "" {
+		name = draftRequest.Name
+	}
+	draft, err := requests.NewBudgetDraft(name, startDate, period, rollover, lines)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, draft)
+}
+
+// SuggestBudgetHandler SuggestBudget godoc
+// @Summary Suggest a budget from past spending
+// @Description Suggest a monthly limit for each category spent in over the last months, from the average or the median
+// @Description of the monthly spend; months without spending count as nothing spent. The draft is a monthly recurring budget
+// @Description starting this month with the suggested limits, which can be changed and then sent to /budgets/create.
+// @Produce json
+// @Param months query int false "Number of months before this one to look at, 3 if not given"
+// @Param method query string false "How limits are derived, median if not given" Enums(average, median)
+// @Success 200 {object} responses.BudgetSuggestionResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Router /budgets/suggest [get]
+// @Tags budgets
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func SuggestBudgetHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	months, method, err := requests.SuggestBudgetQuery(c.Query("months"), c.Query("method"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	now := time.Now().UTC()
+	suggestions, err := budgets.Suggest(userId, months, method, now, db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	result := responses.BudgetSuggestionResponse{Months: months, Method: method, Categories: []responses.BudgetSuggestionLineResponse{}}
+	var lines []models.BudgetCategory
+	for _, suggestion := range suggestions {
+		result.Categories = append(result.Categories, responses.BudgetSuggestionLineResponse{}.FromSuggestion(suggestion))
+		lines = append(lines, models.BudgetCategory{CategoryID: suggestion.CategoryID, Amount: suggestion.Amount})
+	}
+	startDate := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if draft, err := requests.NewBudgetDraft("Suggested budget", startDate, models.BudgetPeriodMonthly, models.BudgetRolloverNone, lines); err == nil {
+		result.Draft = &draft
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// mergeBudgetTemplateLines moves the template lines of the source categories
+// to the target category, adding a source line to the template's line for the
+// target when it already has one.
+func mergeBudgetTemplateLines(sourceIds []int, targetId int, db *gorm.DB) error {
+	var merged []models.BudgetTemplateLine
+	if err := db.Where("category_id IN ?", sourceIds).Order("id").Find(&merged).Error; err != nil {
+		return err
+	}
+	for _, line := range merged {
+		var target models.BudgetTemplateLine
+		db.Where("budget_template_id = ? AND category_id = ?", line.BudgetTemplateID, targetId).First(&target)
+		if target.ID == 0 {
+			if err := db.Model(&line).Update("category_id", targetId).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		err := db.Model(&target).Updates(map[string]interface{}{
+			"amount":     utils.RoundToCents(target.Amount + line.Amount),
+			"percentage": target.Percentage + line.Percentage,
+		}).Error
+		if err != nil {
+			return err
+		}
+		if err := db.Unscoped().Delete(&line).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func getUserBudgetTemplate(id int, userId uint, db *gorm.DB) (models.BudgetTemplate, error) {
+	var template models.BudgetTemplate
+	db.Preload("Lines").Where("user_id = ?", userId).First(&template, id)
+	if template.ID == 0 {
+		return template, errors.BudgetTemplateNotFoundError()
+	}
+	return template, nil
+}
@@ -1,30 +1,36 @@
 package handlers
 
 import (
-	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/christo-andrew/haven/internal/api/requests"
+	"github.com/christo-andrew/haven/internal/api/responses"
 	"github.com/christo-andrew/haven/internal/api/serializers"
 	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/audit"
+	"github.com/christo-andrew/haven/pkg/auth"
+	"github.com/christo-andrew/haven/pkg/categories"
 	"github.com/christo-andrew/haven/pkg/database/scopes"
+	"github.com/christo-andrew/haven/pkg/errors"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
 // GetAllCategoriesHandler GetCategories godoc
 // @Summary Get all categories
-// @Description Retrieve all categories
+// @Description Retrieve the system categories and the current user's own categories, with the user's overrides applied
 // @Produce json
+// @Param include_hidden query bool false "Include hidden categories"
 // @Success 200 {array} responses.CategoryResponse
 // @Router /categories [get]
 // @Tags categories
 // @Security AuthToken
 // @Param Authorization header string true "Authorization"
 func GetAllCategoriesHandler(c *gin.Context, db *gorm.DB) {
-	var categories []models.Category
-	db.Model(&models.Category{}).Find(&categories)
+	userId := uint(auth.GetUserIdFromContext(c))
+	categories := userCategories(userId, db, c.Query("include_hidden") == "true")
 	serializer := serializers.NewCategorySerializer(categories, true)
 	response, err := serializer.Serialize()
 	if err != nil {
@@ -55,7 +61,7 @@ func GetCategoryHandler(c *gin.Context, db *gorm.DB) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	category, err = getCategory(id, db)
+	category, err = getCategory(id, uint(auth.GetUserIdFromContext(c)), db)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
@@ -69,23 +75,46 @@ func GetCategoryHandler(c *gin.Context, db *gorm.DB) {
 	c.JSON(http.StatusOK, response)
 }
 
-func getCategory(id int, db *gorm.DB) (models.Category, error) {
+// getCategory returns a category the user can see, with their override
+// applied.
+func getCategory(id int, userId uint, db *gorm.DB) (models.Category, error) {
 	var category models.Category
-	db.First(&category, id)
+	db.Scopes(scopes.VisibleCategories(userId)).First(&category, id)
 
 	if category.ID == 0 {
-		return category, errors.New("category not found")
+		return category, errors.CategoryNotFoundError()
 	}
 
+	var override models.CategoryOverride
+	scopes.GetUserCategoryOverrides(userId, db).Where("category_id = ?", category.ID).First(&override)
+	if override.ID != 0 {
+		category = categories.Apply(category, override)
+	}
 	return category, nil
 }
 
+// userCategories returns the categories a user can see from those query
+// selects, with their overrides applied.
+func userCategories(userId uint, query *gorm.DB, includeHidden bool) []models.Category {
+	var all []models.Category
+	query.Scopes(scopes.VisibleCategories(userId)).Find(&all)
+	var overrides []models.CategoryOverride
+	scopes.GetUserCategoryOverrides(userId, query.Session(&gorm.Session{NewDB: true})).Find(&overrides)
+	visible := []models.Category{}
+	for _, category := range categories.Personalise(all, overrides) {
+		if includeHidden || !category.Hidden {
+			visible = append(visible, category)
+		}
+	}
+	return visible
+}
+
 // CreateCategoryHandler CreateCategory godoc
 // @Summary Create a category
-// @Description Create a category
+// @Description Create a category that only the current user can see
 // @Accept json
 // @Produce json
-// @Param category body requests.CreateCategoryRequest true "Create Category Request"
+// @Param category body requests.CreateCategoryRequest true "Create Category Request. A parent_id creates a subcategory."
 // @Success 201 {object} responses.CategoryResponse
 // @Failure 400 {object} responses.ErrorResponse
 // @Router /categories/create [post]
@@ -99,7 +128,7 @@ func CreateCategoryHandler(c *gin.Context, db *gorm.DB) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	category, err := createCategory(createCategoryRequest, db)
+	category, err := createCategory(createCategoryRequest, uint(auth.GetUserIdFromContext(c)), db)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -113,8 +142,18 @@ func CreateCategoryHandler(c *gin.Context, db *gorm.DB) {
 	c.JSON(http.StatusCreated, response)
 }
 
-func createCategory(createCategoryRequest requests.CreateCategoryRequest, db *gorm.DB) (*models.Category, error) {
+func createCategory(createCategoryRequest requests.CreateCategoryRequest, userId uint, db *gorm.DB) (*models.Category, error) {
 	category := createCategoryRequest.Category()
+	category.UserID = &userId
+	if category.ParentID != nil {
+		parent, err := getCategory(*category.ParentID, userId, db)
+		if err != nil {
+			return category, err
+		}
+		if parent.Context != category.Context || parent.ContextType != category.ContextType {
+			return category, errors.CategoryContextMismatchError()
+		}
+	}
 	result := db.Create(category)
 	if result.Error != nil {
 		return category, result.Error
@@ -136,8 +175,8 @@ func createCategory(createCategoryRequest requests.CreateCategoryRequest, db *go
 func GetCategoryByContextAndContextTypeHandler(c *gin.Context, db *gorm.DB) {
 	context := c.Query("context")
 	contextType := c.Query("context_type")
-	var categories []models.Category
-	scopes.GetCategoriesByContextAndContextType(context, contextType, db).Find(&categories)
+	userId := uint(auth.GetUserIdFromContext(c))
+	categories := userCategories(userId, scopes.GetCategoriesByContextAndContextType(context, contextType, db), false)
 	serializer := serializers.NewCategorySerializer(categories, true)
 	response, err := serializer.Serialize()
 	if err != nil {
@@ -146,3 +185,478 @@ func GetCategoryByContextAndContextTypeHandler(c *gin.Context, db *gorm.DB) {
 	}
 	c.JSON(http.StatusOK, response)
 }
+
+// GetCategoryTreeHandler GetCategoryTree godoc
+// @Summary Get the category tree
+// @Description Retrieve categories nested under their parents, top level categories first
+// @Produce json
+// @Param context query string false "Context"
+// @Param context_type query string false "Context Type"
+// @Param include_hidden query bool false "Include hidden categories"
+// @Success 200 {array} responses.CategoryTreeResponse
+// @Router /categories/tree [get]
+// @Tags categories
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetCategoryTreeHandler(c *gin.Context, db *gorm.DB) {
+	context := c.DefaultQuery("context", "accounts")
+	contextType := c.DefaultQuery("context_type", "transaction_categories")
+	userId := uint(auth.GetUserIdFromContext(c))
+	query := scopes.GetCategoriesByContextAndContextType(context, contextType, db)
+	tree := categories.NewTree(userCategories(userId, query, c.Query("include_hidden") == "true"))
+	response := []*responses.CategoryTreeResponse{}
+	for _, root := range tree.Roots() {
+		response = append(response, categoryTreeResponse(root))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func categoryTreeResponse(node *categories.Node) *responses.CategoryTreeResponse {
+	response := &responses.CategoryTreeResponse{
+		ID:       node.Category.ID,
+		Name:     node.Category.Name,
+		ParentID: node.Category.ParentID,
+		Path:     node.Path(),
+		IsSystem: node.Category.IsSystem,
+		Color:    node.Category.Color,
+		Icon:     node.Category.Icon,
+		Hidden:   node.Category.Hidden,
+		Children: []*responses.CategoryTreeResponse{},
+	}
+	for _, child := range node.Children {
+		response.Children = append(response.Children, categoryTreeResponse(child))
+	}
+	return response
+}
+
+// MoveCategoryHandler MoveCategory godoc
+// @Summary Move a category
+// @Description Move one of the current user's categories, with everything below it, under another category of the same context. A null parent_id makes it a top level category.
+// @Description System categories cannot be moved.
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param category body requests.MoveCategoryRequest true "Move Category Request"
+// @Success 200 {object} responses.CategoryResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /categories/{id}/move [put]
+// @Tags categories
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func MoveCategoryHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	id, _ := strconv.Atoi(c.Param("id"))
+	var moveRequest requests.MoveCategoryRequest
+	if err := c.ShouldBindJSON(&moveRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	category, err := getCategory(id, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if category.IsSystem {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.SystemCategoryError().Error()})
+		return
+	}
+	if moveRequest.ParentID != nil {
+		parent, err := getCategory(*moveRequest.ParentID, userId, db)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if parent.Context != category.Context || parent.ContextType != category.ContextType {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errors.CategoryContextMismatchError().Error()})
+			return
+		}
+		if categoryTree(userId, db).IsDescendant(parent.ID, category.ID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errors.CategoryCycleError().Error()})
+			return
+		}
+	}
+	if err := db.Model(&category).Update("parent_id", moveRequest.ParentID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	category.ParentID = moveRequest.ParentID
+	response, err := serializers.NewCategorySerializer(category, false).Serialize()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// MergeCategoriesHandler MergeCategories godoc
+// @Summary Merge categories
+// @Description Merge some of the current user's categories into a target category of the same context, which may be a system category. Transactions, splits, recurring transactions, payee defaults, budgets and rules move to the target,
+// @Description subcategories of the merged categories move under the target and the merged categories are deleted.
+// @Description A budget or template with lines for both a merged category and the target keeps one line, the amounts added up.
+// @Accept json
+// @Produce json
+// @Param merge body requests.MergeCategoriesRequest true "Merge Categories Request"
+// @Success 200 {object} responses.CategoryResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /categories/merge [post]
+// @Tags categories
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func MergeCategoriesHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	var mergeRequest requests.MergeCategoriesRequest
+	if err := c.ShouldBindJSON(&mergeRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	target, err := getCategory(mergeRequest.TargetID, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	var sources []models.Category
+	for _, sourceId := range mergeRequest.SourceIDs {
+		if sourceId == target.ID {
+			continue
+		}
+		source, err := getCategory(sourceId, userId, db)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if source.IsSystem {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errors.SystemCategoryError().Error()})
+			return
+		}
+		if source.Context != target.Context || source.ContextType != target.ContextType {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errors.CategoryContextMismatchError().Error()})
+			return
+		}
+		sources = append(sources, source)
+	}
+	if err := mergeCategories(target, sources, db); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	categoryClassifiers.Forget(userId)
+	target, _ = getCategory(target.ID, userId, db)
+	response, err := serializers.NewCategorySerializer(target, false).Serialize()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// mergeCategories points everything that refers to the sources at the target
+// and deletes the sources. When the target sits below one of the sources it
+// first moves up to the nearest ancestor that is not being merged.
+func mergeCategories(target models.Category, sources []models.Category, db *gorm.DB) error {
+	if len(sources) == 0 {
+		return nil
+	}
+	merged := make(map[int]*models.Category, len(sources))
+	var sourceIds []int
+	var sourceValues []string
+	for i, source := range sources {
+		merged[source.ID] = &sources[i]
+		sourceIds = append(sourceIds, source.ID)
+		sourceValues = append(sourceValues, strconv.Itoa(source.ID))
+	}
+	targetParent := target.ParentID
+	for targetParent != nil && merged[*targetParent] != nil {
+		targetParent = merged[*targetParent].ParentID
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if !sameParent(targetParent, target.ParentID) {
+			if err := tx.Model(&target).Update("parent_id", targetParent).Error; err != nil {
+				return err
+			}
+		}
+		if err := mergeEnvelopes(sourceIds, target.ID, tx); err != nil {
+			return err
+		}
+		if err := mergeBudgetLines(sourceIds, target.ID, tx); err != nil {
+			return err
+		}
+		if err := mergeBudgetTemplateLines(sourceIds, target.ID, tx); err != nil {
+			return err
+		}
+		updates := []struct {
+			model  interface{}
+			column string
+		}{
+			{&models.Transaction{}, "category_id"},
+			{&models.Transaction{}, "transaction_type_id"},
+			{&models.TransactionSplit{}, "category_id"},
+			{&models.RecurringTransaction{}, "category_id"},
+			{&models.RecurringTransaction{}, "transaction_type_id"},
+			{&models.Payee{}, "default_category_id"},
+			{&models.Budget{}, "category_id"},
+			{&models.Category{}, "parent_id"},
+		}
+		for _, update := range updates {
+			err := tx.Model(update.model).Where(update.column+" IN ?", sourceIds).Update(update.column, target.ID).Error
+			if err != nil {
+				return err
+			}
+		}
+		err := tx.Model(&models.RuleAction{}).
+			Where("type IN ? AND value IN ?", []string{models.RuleActionSetCategory, models.RuleActionSetType}, sourceValues).
+			Update("value", strconv.Itoa(target.ID)).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.Category{}, sourceIds).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, "category", target.ID, models.AuditActionMerge, map[string]audit.Change{
+			"merged": {Old: sourceIds, New: target.ID},
+		})
+	})
+}
+
+func sameParent(a *int, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// categoryTree returns every category a user can see, hidden ones included,
+// as a tree.
+func categoryTree(userId uint, db *gorm.DB) *categories.Tree {
+	return categories.NewTree(userCategories(userId, db, true))
+}
+
+// categoryLevelQuery reads the level and parent_id query parameters of the
+// category reports. A missing level is returned as -1, which reports every
+// category on its own.
+func categoryLevelQuery(c *gin.Context) (int, int, error) {
+	level := -1
+	if value := c.Query("level"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return 0, 0, errors.InvalidCategoryLevelError()
+		}
+		level = parsed
+	}
+	under, _ := strconv.Atoi(c.Query("parent_id"))
+	return under, level, nil
+}
+
+type categoryAmount struct {
+	CategoryID int
+	Amount     float64
+}
+
+// accountCategoryTotals adds up the transactions of an account by category,
+// rolled up as described by categories.Tree.Rollup. With tags given, only
+// lines carrying one of them are added up.
+func accountCategoryTotals(accountId int, userId uint, under int, level int, tagIds []int, db *gorm.DB) []categories.Total {
+	var rows []categoryAmount
+	scopes.AccountTransactionTotalsByCategory(accountId, tagIds, db).Scan(&rows)
+	amounts := make(map[int]float64, len(rows))
+	for _, row := range rows {
+		amounts[row.CategoryID] += row.Amount
+	}
+	return categoryTree(userId, db).Rollup(amounts, under, level)
+}
+
+// UpdateCategoryOverrideHandler UpdateCategoryOverride godoc
+// @Summary Personalise a category
+// @Description Rename, hide, recolour or change the icon of a category for the current user only. Empty fields keep the category's own value.
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param override body requests.UpdateCategoryOverrideRequest true "Category Override Request"
+// @Success 200 {object} responses.CategoryResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /categories/{id}/override [put]
+// @Tags categories
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func UpdateCategoryOverrideHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	id, _ := strconv.Atoi(c.Param("id"))
+	var overrideRequest requests.UpdateCategoryOverrideRequest
+	if err := c.ShouldBindJSON(&overrideRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := getCategory(id, userId, db); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	var override models.CategoryOverride
+	scopes.GetUserCategoryOverrides(userId, db).Where("category_id = ?", id).First(&override)
+	override.UserID = userId
+	override.CategoryID = id
+	overrideRequest.Apply(&override)
+	if err := db.Save(&override).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	category, _ := getCategory(id, userId, db)
+	response, err := serializers.NewCategorySerializer(category, false).Serialize()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteCategoryOverrideHandler DeleteCategoryOverride godoc
+// @Summary Reset a category
+// @Description Remove the current user's override of a category, restoring its own name, colour and icon and showing it again
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {object} responses.CategoryResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /categories/{id}/override [delete]
+// @Tags categories
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func DeleteCategoryOverrideHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	id, _ := strconv.Atoi(c.Param("id"))
+	if _, err := getCategory(id, userId, db); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	// Overrides are removed for good so that the unique index on user and
+	// category allows a new one to be created later.
+	err := db.Unscoped().Where("user_id = ? AND category_id = ?", userId, id).Delete(&models.CategoryOverride{}).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	category, _ := getCategory(id, userId, db)
+	response, err := serializers.NewCategorySerializer(category, false).Serialize()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// RenameCategoryHandler RenameCategory godoc
+// @Summary Rename a category
+// @Description Rename one of the current user's categories. Renaming a system category renames it for the current user only, through their override.
+// @Description A category cannot take the name of another of the user's categories; merge them instead.
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param category body requests.RenameRequest true "Rename Category Request"
+// @Success 200 {object} responses.CategoryResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /categories/{id}/rename [put]
+// @Tags categories
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func RenameCategoryHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	id, _ := strconv.Atoi(c.Param("id"))
+	var renameRequest requests.RenameRequest
+	if err := c.ShouldBindJSON(&renameRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	name := renameRequest.GetName()
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.EmptyNameError().Error()})
+		return
+	}
+	category, err := getCategory(id, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	var existing models.Category
+	scopes.GetCategoriesByContextAndContextType(category.Context, category.ContextType, db).
+		Where("user_id = ? AND id <> ? AND LOWER(name) = ?", userId, category.ID, strings.ToLower(name)).
+		First(&existing)
+	if existing.ID != 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.NameTakenError(name).Error()})
+		return
+	}
+	if category.IsSystem {
+		var override models.CategoryOverride
+		scopes.GetUserCategoryOverrides(userId, db).Where("category_id = ?", category.ID).First(&override)
+		override.UserID = userId
+		override.CategoryID = category.ID
+		override.Name = name
+		err = db.Save(&override).Error
+	} else {
+		err = db.Model(&models.Category{}).Where("id = ?", category.ID).Update("name", name).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	category, _ = getCategory(id, userId, db)
+	response, err := serializers.NewCategorySerializer(category, false).Serialize()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteCategoryHandler DeleteCategory godoc
+// @Summary Delete a category
+// @Description Delete one of the current user's categories. Everything that used it, including its subcategories, is moved to the reassign_to category first.
+// @Description System categories cannot be deleted; hide them with an override instead.
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param reassign_to query int true "Category to move transactions, budgets and rules to"
+// @Success 200 {object} responses.CategoryResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /categories/{id} [delete]
+// @Tags categories
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func DeleteCategoryHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	id, _ := strconv.Atoi(c.Param("id"))
+	targetId, _ := strconv.Atoi(c.Query("reassign_to"))
+	if targetId == 0 || targetId == id {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.ReassignmentRequiredError().Error()})
+		return
+	}
+	category, err := getCategory(id, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if category.IsSystem {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.SystemCategoryError().Error()})
+		return
+	}
+	target, err := getCategory(targetId, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if target.Context != category.Context || target.ContextType != category.ContextType {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.CategoryContextMismatchError().Error()})
+		return
+	}
+	if err := mergeCategories(target, []models.Category{category}, db); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	categoryClassifiers.Forget(userId)
+	target, _ = getCategory(target.ID, userId, db)
+	response, err := serializers.NewCategorySerializer(target, false).Serialize()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
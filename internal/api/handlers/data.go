@@ -1,11 +1,13 @@
 package handlers
 
 import (
+	"net/http"
 	"sort"
 	"strconv"
 	"time"
 
 	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/auth"
 	"github.com/christo-andrew/haven/pkg/database/scopes"
 	"github.com/emirpasic/gods/sets/hashset"
 	"github.com/gin-gonic/gin"
@@ -20,6 +22,14 @@ type transactionsByYearAndMonth struct {
 }
 
 type transactionsByCategory struct {
+	ID     int     `json:"id"`
+	Name   string  `json:"name"`
+	Path   string  `json:"path"`
+	Amount float64 `json:"amount"`
+}
+
+type transactionsByTag struct {
+	ID     int     `json:"id"`
 	Name   string  `json:"name"`
 	Amount float64 `json:"amount"`
 }
@@ -30,17 +40,24 @@ type transactionsByCategory struct {
 // @ID get-transaction-histogram-data
 // @Produce json
 // @Param account_id path int true "Account ID"
+// @Param tag_ids query string false "Comma separated tag ids. Only transactions carrying one of the tags, themselves or on a split, are counted."
 // @Success 200 {array} any
+// @Failure 400 {object} responses.ErrorResponse
 // @Router /data/{account_id}/transactions/histogram [get]
 // @Tags data
 // @Security AuthToken
 // @Param Authorization header string true "Authorization"
 func TransactionsHistogramHandler(c *gin.Context, db *gorm.DB) {
 	accountId, _ := strconv.Atoi(c.Param("account_id"))
-	c.JSON(200, buildTransactionsHistogramData(accountId, db))
+	tagIds, err := tagIdsQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, buildTransactionsHistogramData(accountId, tagIds, db))
 }
 
-func buildTransactionsHistogramData(accountID int, db *gorm.DB) map[string]interface{} {
+func buildTransactionsHistogramData(accountID int, tagIds []int, db *gorm.DB) map[string]interface{} {
 	var (
 		result           []transactionsByYearAndMonth
 		months           []int
@@ -48,7 +65,7 @@ func buildTransactionsHistogramData(accountID int, db *gorm.DB) map[string]inter
 	)
 
 	currentYear := 2024
-	scopes.AccountTransactionsByYearAndMonth(accountID, 2024, db).Scan(&result)
+	scopes.AccountTransactionsByYearAndMonth(accountID, 2024, tagIds, db).Scan(&result)
 
 	groupedByYearAndMonth := groupByYearAndMonth(result)
 	forThisYear := groupedByYearAndMonth[strconv.Itoa(currentYear)]
@@ -110,7 +127,7 @@ func groupByYearAndMonth(transactions []transactionsByYearAndMonth) map[string]m
 // @Summary Get transactions summary data
 // @Description Get transactions summary data
 //
-//	Available filters: transaction_category
+//	Available filters: transaction_category, tag
 //	Available intervals: month, year, week
 //	Available group_by: transaction_type
 //	Available sort_by: amount
@@ -119,7 +136,7 @@ func groupByYearAndMonth(transactions []transactionsByYearAndMonth) map[string]m
 //	Available offset: 0
 //	Available account_id: 1
 //
-// @Param filter query string false "Filter", Enums(transaction_category, transaction_type, month, year, week)
+// @Param filter query string false "Filter", Enums(transaction_category, tag, transaction_type, month, year, week)
 // @Param interval query string false "Interval", Enums(month, year, week)
 // @Param group_by query string false "Group by"
 // @Param sort_by query string false "Sort by"
@@ -127,6 +144,9 @@ func groupByYearAndMonth(transactions []transactionsByYearAndMonth) map[string]m
 // @Param limit query int false "Limit"
 // @Param offset query int false "Offset"
 // @Param account_id path int true "Account ID"
+// @Param level query int false "Roll categories up to this depth of the category tree, 0 being the top level. Without it every category is reported on its own."
+// @Param parent_id query int false "Only report categories within this one, with levels counted from its children"
+// @Param tag_ids query string false "Comma separated tag ids. Only category lines carrying one of the tags, on their transaction or their own split, are counted."
 // @Tags data
 // @Produce json
 // @Success 200 {array} any
@@ -138,24 +158,64 @@ func TransactionsSummaryHandler(c *gin.Context, db *gorm.DB) {
 	switch filter {
 	case "transaction_category":
 		transactionsSummaryByTransactionCategoryHandler(c, db)
+	case "tag":
+		transactionsSummaryByTagHandler(c, db)
 	}
 }
 
 func transactionsSummaryByTransactionCategoryHandler(c *gin.Context, db *gorm.DB) {
 	//interval := c.Query("interval")
 	accountId, _ := strconv.Atoi(c.Param("account_id"))
-	data, meta := buildTransactionsSummaryByTransactionCategory(accountId, db)
+	under, level, err := categoryLevelQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tagIds, err := tagIdsQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := uint(auth.GetUserIdFromContext(c))
+	data, meta := buildTransactionsSummaryByTransactionCategory(accountId, userId, under, level, tagIds, db)
 	c.JSON(200, gin.H{
 		"data": data,
 		"meta": meta,
 	})
 }
 
-func buildTransactionsSummaryByTransactionCategory(accountId int, db *gorm.DB) ([]transactionsByCategory, map[string]interface{}) {
-	var result []transactionsByCategory
-	scopes.GroupAccountTransactionsByTransactionCategory(accountId, db).Scan(&result)
+func buildTransactionsSummaryByTransactionCategory(accountId int, userId uint, under int, level int, tagIds []int, db *gorm.DB) ([]transactionsByCategory, map[string]interface{}) {
+	result := []transactionsByCategory{}
+	for _, total := range accountCategoryTotals(accountId, userId, under, level, tagIds, db) {
+		result = append(result, transactionsByCategory{
+			ID:     total.Node.Category.ID,
+			Name:   total.Node.Category.Name,
+			Path:   total.Node.Path(),
+			Amount: total.Amount,
+		})
+	}
 	meta := map[string]interface{}{
 		"colors": models.TransactionTypeColors(),
 	}
 	return result, meta
 }
+
+// transactionsSummaryByTagHandler sums an account's transactions by tag. A
+// transaction carrying several tags counts towards each of them.
+func transactionsSummaryByTagHandler(c *gin.Context, db *gorm.DB) {
+	accountId, _ := strconv.Atoi(c.Param("account_id"))
+	userId := uint(auth.GetUserIdFromContext(c))
+	if err := checkUserAccount(accountId, userId, db); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	result := []transactionsByTag{}
+	if err := scopes.AccountTransactionTotalsByTag(accountId, db).Scan(&result).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{
+		"data": result,
+		"meta": map[string]interface{}{},
+	})
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/api/requests"
+	"github.com/christo-andrew/haven/internal/api/responses"
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/auth"
+	"github.com/christo-andrew/haven/pkg/budgets"
+	"github.com/christo-andrew/haven/pkg/debts"
+	"github.com/christo-andrew/haven/pkg/errors"
+	"github.com/christo-andrew/haven/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// DebtPlanHandler DebtPlan godoc
+// @Summary Plan paying off debts
+// @Description Simulate paying off credit cards and loans out of a monthly budget, month by month. Each month interest is
+// @Description charged on what is owed, every debt gets its minimum payment and the rest of the budget goes to one debt
+// @Description at a time: the smallest first with snowball, the highest rate first with avalanche, or the order given
+// @Description with custom. Only credit card and loan accounts are planned. What is owed on an account is the negative of its
+// @Description current balance, and accounts in credit are left out; the interest rate and minimum payment of an account can
+// @Description be overridden for the plan. Plans stop after 50 years, leaving out the debt-free date.
+// @Param plan body requests.DebtPlanRequest true "Plan"
+// @Accept json
+// @Produce json
+// @Success 200 {object} responses.DebtPlanResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Router /data/debt-plan [post]
+// @Tags data
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func DebtPlanHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	var planRequest requests.DebtPlanRequest
+	if err := c.ShouldBindJSON(&planRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	start := time.Now().UTC()
+	start = time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if planRequest.StartMonth != "" {
+		var err error
+		if start, err = budgets.ParseMonth(planRequest.StartMonth); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	owed, err := userDebts(planRequest, userId, db)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	customOrder := planRequest.GetCustomOrder()
+	for _, accountId := range customOrder {
+		if !hasDebt(owed, accountId) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errors.DebtNotInPlanError(accountId).Error()})
+			return
+		}
+	}
+	minimum := debts.MinimumPayments(owed)
+	if minimum > planRequest.MonthlyBudget {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.DebtBudgetTooLowError(minimum).Error()})
+		return
+	}
+
+	orders := map[string][]int{
+		debts.Snowball:  debts.SnowballOrder(owed),
+		debts.Avalanche: debts.AvalancheOrder(owed),
+	}
+	strategies := []string{debts.Snowball, debts.Avalanche}
+	if len(customOrder) > 0 {
+		orders[debts.Custom] = debts.CustomOrder(owed, customOrder)
+		strategies = append(strategies, debts.Custom)
+	}
+	result := responses.DebtPlanResponse{
+		MonthlyBudget:   planRequest.MonthlyBudget,
+		MinimumPayments: minimum,
+		StartMonth:      start.Format(budgets.MonthLayout),
+		Debts:           []responses.DebtResponse{},
+		Strategies:      []responses.DebtStrategyResponse{},
+	}
+	for _, debt := range owed {
+		result.Debts = append(result.Debts, responses.DebtResponse{
+			AccountID:      debt.AccountID,
+			Name:           debt.Name,
+			Balance:        debt.Balance,
+			InterestRate:   debt.Rate,
+			MinimumPayment: debt.Minimum,
+		})
+	}
+	for _, strategy := range strategies {
+		plan, err := debts.Simulate(strategy, owed, orders[strategy], planRequest.MonthlyBudget, start)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		result.Strategies = append(result.Strategies, responses.DebtStrategyResponse{}.FromPlan(plan))
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// debtAccountTypes are the kinds of account money can be owed on.
+var debtAccountTypes = []string{"credit_card_accounts", "loan_accounts"}
+
+// userDebts loads what is owed on the accounts of a plan, all of the user's
+// credit card and loan accounts unless given, and applies the terms given for
+// them. Debts lower an account's balance, so what is owed is the negative of
+// its current balance; accounts in credit or paid off are left out.
+func userDebts(planRequest requests.DebtPlanRequest, userId uint, db *gorm.DB) ([]debts.Debt, error) {
+	var accounts []models.Account
+	if len(planRequest.AccountIDs) > 0 {
+		for _, accountId := range planRequest.AccountIDs {
+			account, err := getUserAccount(accountId, userId, db)
+			if err != nil {
+				return nil, err
+			}
+			if !isDebtAccount(account) {
+				return nil, errors.NotDebtAccountError(accountId)
+			}
+			accounts = append(accounts, account)
+		}
+	} else {
+		db.Where("user_id = ? AND base_account_type IN ?", userId, debtAccountTypes).Order("id").Find(&accounts)
+	}
+
+	var owed []debts.Debt
+	for _, account := range accounts {
+		balance := utils.RoundToCents(-getAccountBalance(account, db).Current)
+		if balance <= 0 || hasDebt(owed, account.ID) {
+			continue
+		}
+		owed = append(owed, debts.Debt{
+			AccountID: account.ID,
+			Name:      account.AccountName,
+			Balance:   balance,
+			Rate:      account.InterestRate,
+			Minimum:   account.MinimumPayment,
+		})
+	}
+	for _, terms := range planRequest.Debts {
+		found := false
+		for i := range owed {
+			if owed[i].AccountID != terms.AccountID {
+				continue
+			}
+			found = true
+			if terms.InterestRate != nil {
+				owed[i].Rate = *terms.InterestRate
+			}
+			if terms.MinimumPayment != nil {
+				owed[i].Minimum = *terms.MinimumPayment
+			}
+		}
+		if !found {
+			return nil, errors.DebtNotInPlanError(terms.AccountID)
+		}
+	}
+	if len(owed) == 0 {
+		return nil, errors.NoDebtsError()
+	}
+	return owed, nil
+}
+
+func isDebtAccount(account models.Account) bool {
+	for _, accountType := range debtAccountTypes {
+		if account.BaseAccountType == accountType {
+			return true
+		}
+	}
+	return false
+}
+
+func hasDebt(owed []debts.Debt, accountId int) bool {
+	for _, debt := range owed {
+		if debt.AccountID == accountId {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/api/requests"
+	"github.com/christo-andrew/haven/internal/api/responses"
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/auth"
+	"github.com/christo-andrew/haven/pkg/budgets"
+	"github.com/christo-andrew/haven/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetEnvelopesHandler GetEnvelopes godoc
+// @Summary Get the envelopes for a month
+// @Description Zero-based budgeting: income is assigned to envelopes until nothing is left to be budgeted. Each envelope shows what it carried in,
+// @Description what was assigned to it, what was spent from it and what is available. An envelope ending a month overspent is either covered
+// @Description by the next month's money to be budgeted or, with cover set to carry, starts the next month in the red.
+// @Produce json
+// @Param month query string false "Month in the form 2006-01, the current month if not given"
+// @Success 200 {object} responses.EnvelopeMonthResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Router /envelopes [get]
+// @Tags envelopes
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetEnvelopesHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	month, err := envelopeMonth(c.Query("month"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := budgets.Envelopes(userId, month, db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, responses.EnvelopeMonthResponse{}.FromEnvelopeMonth(result))
+}
+
+// CreateEnvelopeHandler CreateEnvelope godoc
+// @Summary Create an envelope
+// @Description Budget a category with an envelope. Spending in the category and in the categories below it that have no envelope of their own comes out of it.
+// @Accept json
+// @Produce json
+// @Param envelope body requests.CreateEnvelopeRequest true "Create Envelope Request"
+// @Success 201 {object} responses.EnvelopeResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Router /envelopes [post]
+// @Tags envelopes
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func CreateEnvelopeHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	var createEnvelopeRequest requests.CreateEnvelopeRequest
+	if err := c.ShouldBindJSON(&createEnvelopeRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := getCategory(createEnvelopeRequest.CategoryID, userId, db); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var taken int64
+	db.Model(&models.Envelope{}).Where("user_id = ? AND category_id = ?", userId, createEnvelopeRequest.CategoryID).Count(&taken)
+	if taken > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.DuplicateEnvelopeError().Error()})
+		return
+	}
+	envelope := models.Envelope{
+		UserID:     userId,
+		CategoryID: createEnvelopeRequest.CategoryID,
+		Cover:      createEnvelopeRequest.GetCover(),
+	}
+	if err := db.Create(&envelope).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	respondWithEnvelope(c, http.StatusCreated, userId, envelope.ID, firstOfThisMonth(), db)
+}
+
+// UpdateEnvelopeHandler UpdateEnvelope godoc
+// @Summary Update an envelope
+// @Description Change how an envelope that ends a month overspent is covered
+// @Accept json
+// @Produce json
+// @Param id path int true "Envelope ID"
+// @Param envelope body requests.UpdateEnvelopeRequest true "Update Envelope Request"
+// @Success 200 {object} responses.EnvelopeResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /envelopes/{id} [put]
+// @Tags envelopes
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func UpdateEnvelopeHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	id, _ := strconv.Atoi(c.Param("id"))
+	var updateEnvelopeRequest requests.UpdateEnvelopeRequest
+	if err := c.ShouldBindJSON(&updateEnvelopeRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	envelope, err := getUserEnvelope(uint(id), userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err := db.Model(&envelope).Update("cover", updateEnvelopeRequest.Cover).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	respondWithEnvelope(c, http.StatusOK, userId, envelope.ID, firstOfThisMonth(), db)
+}
+
+// DeleteEnvelopeHandler DeleteEnvelope godoc
+// @Summary Delete an envelope
+// @Description Stop budgeting a category with an envelope. What was assigned to it goes back to be budgeted.
+// @Param id path int true "Envelope ID"
+// @Success 204
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /envelopes/{id} [delete]
+// @Tags envelopes
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func DeleteEnvelopeHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	id, _ := strconv.Atoi(c.Param("id"))
+	envelope, err := getUserEnvelope(uint(id), userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("envelope_id = ?", envelope.ID).Delete(&models.EnvelopeAssignment{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&envelope).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// AssignEnvelopeHandler AssignEnvelope godoc
+// @Summary Assign money to an envelope
+// @Description Set what is assigned to an envelope for a month. It comes out of the money to be budgeted.
+// @Accept json
+// @Produce json
+// @Param id path int true "Envelope ID"
+// @Param month path string true "Month in the form 2006-01"
+// @Param assignment body requests.AssignEnvelopeRequest true "Assign Envelope Request"
+// @Success 200 {object} responses.EnvelopeResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /envelopes/{id}/months/{month} [put]
+// @Tags envelopes
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func AssignEnvelopeHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	id, _ := strconv.Atoi(c.Param("id"))
+	var assignEnvelopeRequest requests.AssignEnvelopeRequest
+	if err := c.ShouldBindJSON(&assignEnvelopeRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	month, err := budgets.ParseMonth(c.Param("month"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	envelope, err := getUserEnvelope(uint(id), userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err := budgets.Assign(envelope.ID, month, assignEnvelopeRequest.Amount, db); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	respondWithEnvelope(c, http.StatusOK, userId, envelope.ID, month, db)
+}
+
+// MoveEnvelopeMoneyHandler MoveEnvelopeMoney godoc
+// @Summary Move money between envelopes
+// @Description Move money assigned for a month from one envelope to another, for example to cover an overspent envelope.
+// @Description What is to be budgeted does not change.
+// @Accept json
+// @Produce json
+// @Param move body requests.MoveEnvelopeMoneyRequest true "Move Envelope Money Request"
+// @Success 200 {object} responses.EnvelopeMonthResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /envelopes/move [post]
+// @Tags envelopes
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func MoveEnvelopeMoneyHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	var moveRequest requests.MoveEnvelopeMoneyRequest
+	if err := c.ShouldBindJSON(&moveRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if moveRequest.FromEnvelopeID == moveRequest.ToEnvelopeID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.SameEnvelopeError().Error()})
+		return
+	}
+	month, err := budgets.ParseMonth(moveRequest.Month)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for _, id := range []uint{moveRequest.FromEnvelopeID, moveRequest.ToEnvelopeID} {
+		if _, err := getUserEnvelope(id, userId, db); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if err := budgets.Move(moveRequest.FromEnvelopeID, moveRequest.ToEnvelopeID, month, moveRequest.Amount, db); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := budgets.Envelopes(userId, month, db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, responses.EnvelopeMonthResponse{}.FromEnvelopeMonth(result))
+}
+
+func getUserEnvelope(id uint, userId uint, db *gorm.DB) (models.Envelope, error) {
+	var envelope models.Envelope
+	db.Where("user_id = ?", userId).First(&envelope, id)
+	if envelope.ID == 0 {
+		return envelope, errors.EnvelopeNotFoundError()
+	}
+	return envelope, nil
+}
+
+// envelopeMonth reads the month of a request, the current month if none is
+// given.
+func envelopeMonth(value string) (time.Time, error) {
+	if value == "" {
+		return firstOfThisMonth(), nil
+	}
+	return budgets.ParseMonth(value)
+}
+
+func firstOfThisMonth() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func respondWithEnvelope(c *gin.Context, status int, userId uint, envelopeId uint, month time.Time, db *gorm.DB) {
+	result, err := budgets.Envelopes(userId, month, db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for _, balance := range result.Envelopes {
+		if balance.Envelope.ID == envelopeId {
+			c.JSON(status, responses.EnvelopeResponse{}.FromEnvelopeBalance(balance))
+			return
+		}
+	}
+	c.JSON(http.StatusNotFound, gin.H{"error": errors.EnvelopeNotFoundError().Error()})
+}
+
+// mergeEnvelopes moves the envelopes of categories being merged into the
+// target category. A user who already has an envelope for the target keeps
+// it, with what was assigned to the merged envelopes added to it.
+func mergeEnvelopes(sourceIds []int, targetId int, db *gorm.DB) error {
+	var merged []models.Envelope
+	if err := db.Where("category_id IN ?", sourceIds).Order("id").Find(&merged).Error; err != nil {
+		return err
+	}
+	for _, envelope := range merged {
+		var target models.Envelope
+		db.Where("user_id = ? AND category_id = ?", envelope.UserID, targetId).First(&target)
+		if target.ID == 0 {
+			if err := db.Model(&envelope).Update("category_id", targetId).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		var assignments []models.EnvelopeAssignment
+		db.Where("envelope_id = ?", envelope.ID).Find(&assignments)
+		for _, assignment := range assignments {
+			month, err := budgets.ParseMonth(assignment.Month)
+			if err != nil {
+				return err
+			}
+			if err := budgets.Move(envelope.ID, target.ID, month, assignment.Amount, db); err != nil {
+				return err
+			}
+		}
+		if err := db.Unscoped().Where("envelope_id = ?", envelope.ID).Delete(&models.EnvelopeAssignment{}).Error; err != nil {
+			return err
+		}
+		if err := db.Unscoped().Delete(&envelope).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/api/requests"
+	"github.com/christo-andrew/haven/internal/api/responses"
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/auth"
+	"github.com/christo-andrew/haven/pkg/budgets"
+	"github.com/christo-andrew/haven/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetGoalsHandler GetGoals godoc
+// @Summary Get all goals for a user
+// @Description Retrieve the user's goals, soonest target date first, each with how far it has come
+// @Produce json
+// @Success 200 {array} responses.GoalResponse
+// @Router /goals [get]
+// @Tags goals
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetGoalsHandler(c *gin.Context, db *gorm.DB, tracker *budgets.Tracker) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	var goals []models.Goal
+	db.Preload("Accounts").Where("user_id = ?", userId).Order("target_date").Order("id").Find(&goals)
+	result := []*responses.GoalResponse{}
+	for _, goal := range goals {
+		response, err := goalResponse(goal, db, tracker)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		result = append(result, response)
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetGoalHandler GetGoal godoc
+// @Summary Get a goal
+// @Description Retrieve a goal with how far it has come. Saved adds up the current balances of the linked accounts, the
+// @Description transactions carrying the goal's tag since it started in other accounts, and what the linked budget lines
+// @Description left unspent in the periods that ended since it started. Expected is what would have been saved by now saving
+// @Description evenly, and required_monthly what has to be saved each month to reach the target in time.
+// @Produce json
+// @Param id path int true "Goal ID"
+// @Success 200 {object} responses.GoalResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /goals/{id} [get]
+// @Tags goals
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetGoalHandler(c *gin.Context, db *gorm.DB, tracker *budgets.Tracker) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	id, _ := strconv.Atoi(c.Param("id"))
+	goal, err := getUserGoal(id, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	respondWithGoal(c, http.StatusOK, goal, db, tracker)
+}
+
+// CreateGoalHandler CreateGoal godoc
+// @Summary Create a goal
+// @Description Create a goal to save a target amount by a target date, linked to savings accounts, a tag for contributions,
+// @Description or both. Budget lines are linked to a goal through their goal_id.
+// @Param goal body requests.GoalRequest true "Goal"
+// @Accept json
+// @Produce json
+// @Success 201 {object} responses.GoalResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Router /goals [post]
+// @Tags goals
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func CreateGoalHandler(c *gin.Context, db *gorm.DB, tracker *budgets.Tracker) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	var goalRequest requests.GoalRequest
+	if err := c.ShouldBindJSON(&goalRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := checkGoalRequest(goalRequest, userId, db); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	goal := goalRequest.Goal(userId)
+	if err := db.Create(goal).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	respondWithGoal(c, http.StatusCreated, *goal, db, tracker)
+}
+
+// UpdateGoalHandler UpdateGoal godoc
+// @Summary Update a goal
+// @Description Update a goal. The accounts given replace the accounts linked to it.
+// @Param id path int true "Goal ID"
+// @Param goal body requests.GoalRequest true "Goal"
+// @Accept json
+// @Produce json
+// @Success 200 {object} responses.GoalResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /goals/{id} [put]
+// @Tags goals
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func UpdateGoalHandler(c *gin.Context, db *gorm.DB, tracker *budgets.Tracker) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	var goalRequest requests.GoalRequest
+	if err := c.ShouldBindJSON(&goalRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	goal, err := getUserGoal(id, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err := checkGoalRequest(goalRequest, userId, db); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	updated := goalRequest.Goal(userId)
+	err = db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&goal).Omit("Accounts").Updates(map[string]interface{}{
+			"name":          updated.Name,
+			"description":   updated.Description,
+			"target_amount": updated.TargetAmount,
+			"start_date":    updated.StartDate,
+			"target_date":   updated.TargetDate,
+			"tag_id":        updated.TagID,
+		}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("goal_id = ?", goal.ID).Delete(&models.GoalAccount{}).Error; err != nil {
+			return err
+		}
+		for i := range updated.Accounts {
+			updated.Accounts[i].GoalID = goal.ID
+		}
+		if len(updated.Accounts) > 0 {
+			return tx.Create(&updated.Accounts).Error
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	goal.Accounts = updated.Accounts
+	respondWithGoal(c, http.StatusOK, goal, db, tracker)
+}
+
+// DeleteGoalHandler DeleteGoal godoc
+// @Summary Delete a goal
+// @Description Delete a goal. Budget lines linked to it are kept and no longer linked to a goal.
+// @Param id path int true "Goal ID"
+// @Success 204
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /goals/{id} [delete]
+// @Tags goals
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func DeleteGoalHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	id, _ := strconv.Atoi(c.Param("id"))
+	goal, err := getUserGoal(id, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.BudgetCategory{}).Where("goal_id = ?", goal.ID).Update("goal_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("goal_id = ?", goal.ID).Delete(&models.GoalAccount{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&goal).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func getUserGoal(id int, userId uint, db *gorm.DB) (models.Goal, error) {
+	var goal models.Goal
+	db.Preload("Accounts").Where("user_id = ?", userId).First(&goal, id)
+	if goal.ID == 0 {
+		return goal, errors.GoalNotFoundError()
+	}
+	return goal, nil
+}
+
+// checkGoalRequest makes sure the dates of a goal make sense and that its
+// accounts and tag are ones the user has.
+func checkGoalRequest(goalRequest requests.GoalRequest, userId uint, db *gorm.DB) error {
+	if err := goalRequest.Validate(); err != nil {
+		return err
+	}
+	for _, accountId := range goalRequest.AccountIDs {
+		if _, err := getUserAccount(accountId, userId, db); err != nil {
+			return err
+		}
+	}
+	if goalRequest.TagID != nil {
+		if _, err := getUserTag(*goalRequest.TagID, userId, db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func goalResponse(goal models.Goal, db *gorm.DB, tracker *budgets.Tracker) (*responses.GoalResponse, error) {
+	progress, err := tracker.GoalProgress(goal, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	var lines []models.BudgetCategory
+	err = db.Preload("Category").
+		Where("goal_id = ? AND budget_id IN (?)", goal.ID, db.Model(&models.Budget{}).Select("id").Where("user_id = ?", goal.UserID)).
+		Order("budget_id").Order("id").Find(&lines).Error
+	if err != nil {
+		return nil, err
+	}
+	return responses.GoalResponse{}.FromGoal(goal, lines, progress), nil
+}
+
+func respondWithGoal(c *gin.Context, status int, goal models.Goal, db *gorm.DB, tracker *budgets.Tracker) {
+	response, err := goalResponse(goal, db, tracker)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(status, response)
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/api/responses"
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/auth"
+	"github.com/christo-andrew/haven/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetNotificationsHandler GetNotifications godoc
+// @Summary Get notifications
+// @Description Retrieve the in-app notification feed of the current user, newest first
+// @Produce json
+// @Param unread query bool false "Only unread notifications"
+// @Success 200 {array} responses.NotificationResponse
+// @Router /notifications [get]
+// @Tags notifications
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetNotificationsHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	query := db.Where("user_id = ?", userId)
+	if c.Query("unread") == "true" {
+		query = query.Where("read_at IS NULL")
+	}
+	var notifications []models.Notification
+	query.Order("created_at DESC").Order("id DESC").Find(&notifications)
+	result := []*responses.NotificationResponse{}
+	for _, notification := range notifications {
+		result = append(result, responses.NotificationResponse{}.FromNotification(notification))
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// MarkNotificationReadHandler MarkNotificationRead godoc
+// @Summary Mark a notification as read
+// @Description Mark a notification as read
+// @Produce json
+// @Param id path int true "Notification ID"
+// @Success 200 {object} responses.NotificationResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /notifications/{id}/read [put]
+// @Tags notifications
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func MarkNotificationReadHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	id, _ := strconv.Atoi(c.Param("id"))
+	var notification models.Notification
+	db.Where("user_id = ?", userId).First(&notification, id)
+	if notification.ID == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": errors.NotificationNotFoundError().Error()})
+		return
+	}
+	if notification.ReadAt == nil {
+		now := time.Now()
+		if err := db.Model(&notification).Update("read_at", now).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		notification.ReadAt = &now
+	}
+	c.JSON(http.StatusOK, responses.NotificationResponse{}.FromNotification(notification))
+}
+
+// MarkAllNotificationsReadHandler MarkAllNotificationsRead godoc
+// @Summary Mark all notifications as read
+// @Description Mark every unread notification of the current user as read
+// @Success 204
+// @Router /notifications/read [put]
+// @Tags notifications
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func MarkAllNotificationsReadHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	err := db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userId).
+		Update("read_at", time.Now()).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
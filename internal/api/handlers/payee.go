@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/christo-andrew/haven/internal/api/requests"
+	"github.com/christo-andrew/haven/internal/api/responses"
+	"github.com/christo-andrew/haven/internal/api/serializers"
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/auth"
+	"github.com/christo-andrew/haven/pkg/database/scopes"
+	"github.com/christo-andrew/haven/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetPayeesHandler GetPayees godoc
+// @Summary Get all payees
+// @Description Retrieve the payees of the current user with their aliases
+// @Produce json
+// @Success 200 {array} responses.PayeeResponse
+// @Router /payees [get]
+// @Tags payees
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetPayeesHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	var payees []models.Payee
+	scopes.GetUserPayees(uint(userId), db).Find(&payees)
+	response, err := serializers.NewPayeeSerializer(payees, true).Serialize()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetPayeeHandler GetPayee godoc
+// @Summary Get a payee
+// @Description Retrieve a payee
+// @Produce json
+// @Param id path int true "Payee ID"
+// @Success 200 {object} responses.PayeeResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /payees/{id} [get]
+// @Tags payees
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetPayeeHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	id, _ := strconv.Atoi(c.Param("id"))
+	payee, err := getPayee(id, uint(userId), db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	response, err := serializers.NewPayeeSerializer(payee, false).Serialize()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// CreatePayeeHandler CreatePayee godoc
+// @Summary Create a payee
+// @Description Create a payee with aliases, match patterns and a default category
+// @Accept json
+// @Produce json
+// @Param payee body requests.CreateOrUpdatePayeeRequest true "Payee"
+// @Success 201 {object} responses.PayeeResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Router /payees/create [post]
+// @Tags payees
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func CreatePayeeHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	var payeeRequest requests.CreateOrUpdatePayeeRequest
+	if err := c.ShouldBindJSON(&payeeRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := payeeRequest.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	payee := payeeRequest.Payee(uint(userId), db)
+	payee.UserID = uint(userId)
+	if err := db.Omit("DefaultCategory").Create(payee).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	response, err := serializers.NewPayeeSerializer(*payee, false).Serialize()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, response)
+}
+
+// UpdatePayeeHandler UpdatePayee godoc
+// @Summary Update a payee
+// @Description Update a payee, its aliases and patterns are replaced by the ones in the request
+// @Accept json
+// @Produce json
+// @Param id path int true "Payee ID"
+// @Param payee body requests.CreateOrUpdatePayeeRequest true "Payee"
+// @Success 200 {object} responses.PayeeResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /payees/{id}/update [put]
+// @Tags payees
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func UpdatePayeeHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	id, _ := strconv.Atoi(c.Param("id"))
+	var payeeRequest requests.CreateOrUpdatePayeeRequest
+	if err := c.ShouldBindJSON(&payeeRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := payeeRequest.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	payee, err := getPayee(id, uint(userId), db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	payee, err = updatePayee(payee, payeeRequest.Payee(uint(userId), db), db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	response, err := serializers.NewPayeeSerializer(payee, false).Serialize()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// MergePayeesHandler MergePayees godoc
+// @Summary Merge payees
+// @Description Merge payees into a target payee. Transactions and aliases move to the target, the names of the merged payees become aliases and the merged payees are deleted.
+// @Accept json
+// @Produce json
+// @Param merge body requests.MergePayeesRequest true "Merge Payees Request"
+// @Success 200 {object} responses.PayeeResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /payees/merge [post]
+// @Tags payees
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func MergePayeesHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	var mergeRequest requests.MergePayeesRequest
+	if err := c.ShouldBindJSON(&mergeRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	target, err := getPayee(mergeRequest.TargetID, uint(userId), db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	var sources []models.Payee
+	for _, sourceId := range mergeRequest.SourceIDs {
+		if sourceId == target.ID {
+			continue
+		}
+		source, err := getPayee(sourceId, uint(userId), db)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		sources = append(sources, source)
+	}
+	if err := mergePayees(target, sources, db); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	target, _ = getPayee(target.ID, uint(userId), db)
+	response, err := serializers.NewPayeeSerializer(target, false).Serialize()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetPayeeSpendHandler GetPayeeSpend godoc
+// @Summary Get spend per payee
+// @Description Retrieve the total spent with each payee, largest first
+// @Produce json
+// @Param from query string false "From" Format(YYYY-MM-DD)
+// @Param to query string false "To" Format(YYYY-MM-DD)
+// @Success 200 {array} responses.PayeeSpendResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Router /payees/spend [get]
+// @Tags payees
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetPayeeSpendHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	from, to, err := requests.DateRangeQuery(c.Query("from"), c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result := make([]responses.PayeeSpendResponse, 0)
+	scopes.PayeeSpend(uint(userId), from, to, db).Scan(&result)
+	c.JSON(http.StatusOK, result)
+}
+
+func getPayee(id int, userId uint, db *gorm.DB) (models.Payee, error) {
+	var payee models.Payee
+	scopes.GetUserPayeeById(id, userId, db).First(&payee)
+	if payee.ID == 0 {
+		return payee, errors.PayeeNotFoundError()
+	}
+	return payee, nil
+}
+
+func updatePayee(payee models.Payee, update *models.Payee, db *gorm.DB) (models.Payee, error) {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("payee_id = ?", payee.ID).Delete(&models.PayeeAlias{}).Error; err != nil {
+			return err
+		}
+		err := tx.Model(&models.Payee{}).Where("id = ?", payee.ID).Updates(map[string]interface{}{
+			"name":                update.Name,
+			"default_category_id": update.DefaultCategoryID,
+		}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Transaction{}).Where("payee_id = ?", payee.ID).Update("payee", update.Name).Error; err != nil {
+			return err
+		}
+		for i := range update.Aliases {
+			update.Aliases[i].PayeeID = payee.ID
+		}
+		if len(update.Aliases) == 0 {
+			return nil
+		}
+		return tx.Create(&update.Aliases).Error
+	})
+	if err != nil {
+		return payee, err
+	}
+	return getPayee(payee.ID, payee.UserID, db)
+}
+
+func mergePayees(target models.Payee, sources []models.Payee, db *gorm.DB) error {
+	if len(sources) == 0 {
+		return nil
+	}
+	var sourceIds []int
+	for _, source := range sources {
+		sourceIds = append(sourceIds, source.ID)
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.Transaction{}).Where("payee_id IN ?", sourceIds).Updates(map[string]interface{}{
+			"payee_id": target.ID,
+			"payee":    target.Name,
+		}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.PayeeAlias{}).Where("payee_id IN ?", sourceIds).Update("payee_id", target.ID).Error; err != nil {
+			return err
+		}
+		for _, source := range sources {
+			if err := tx.Create(&models.PayeeAlias{PayeeID: target.ID, Name: source.Name}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&models.Payee{}, sourceIds).Error
+	})
+}
@@ -0,0 +1,295 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/api/requests"
+	"github.com/christo-andrew/haven/internal/api/responses"
+	"github.com/christo-andrew/haven/internal/api/serializers"
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/auth"
+	"github.com/christo-andrew/haven/pkg/database/scopes"
+	"github.com/christo-andrew/haven/pkg/errors"
+	"github.com/christo-andrew/haven/pkg/recurrence"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetRecurringTransactionsHandler GetRecurringTransactions godoc
+// @Summary Get all recurring transactions
+// @Description Retrieve the recurring transaction templates of the current user, soonest first
+// @Produce json
+// @Success 200 {array} responses.RecurringTransactionResponse
+// @Router /recurring [get]
+// @Tags recurring
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetRecurringTransactionsHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	var recurringTransactions []models.RecurringTransaction
+	scopes.GetUserRecurringTransactions(uint(userId), db).Find(&recurringTransactions)
+	response, err := serializers.NewRecurringTransactionSerializer(recurringTransactions, true).Serialize()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetRecurringTransactionHandler GetRecurringTransaction godoc
+// @Summary Get a recurring transaction
+// @Description Retrieve a recurring transaction template
+// @Produce json
+// @Param id path int true "Recurring Transaction ID"
+// @Success 200 {object} responses.RecurringTransactionResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /recurring/{id} [get]
+// @Tags recurring
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetRecurringTransactionHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	id, _ := strconv.Atoi(c.Param("id"))
+	recurring, err := getRecurringTransaction(id, uint(userId), db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	response, err := serializers.NewRecurringTransactionSerializer(recurring, false).Serialize()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateRecurringTransactionHandler CreateRecurringTransaction godoc
+// @Summary Create a recurring transaction
+// @Description Create a template that posts a transaction on every occurrence of an RRULE-like recurrence, either cleared or pending confirmation
+// @Accept json
+// @Produce json
+// @Param recurring body requests.CreateOrUpdateRecurringTransactionRequest true "Recurring Transaction"
+// @Success 201 {object} responses.RecurringTransactionResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Router /recurring/create [post]
+// @Tags recurring
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func CreateRecurringTransactionHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	var recurringRequest requests.CreateOrUpdateRecurringTransactionRequest
+	if err := c.ShouldBindJSON(&recurringRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := recurringRequest.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := checkUserAccount(recurringRequest.AccountID, uint(userId), db); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	recurring, err := recurringRequest.RecurringTransaction(uint(userId), db)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	recurring.UserID = uint(userId)
+	if err := db.Omit("Account", "Category", "TransactionType").Create(recurring).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	response, err := serializers.NewRecurringTransactionSerializer(*recurring, false).Serialize()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, response)
+}
+
+// UpdateRecurringTransactionHandler UpdateRecurringTransaction godoc
+// @Summary Update a recurring transaction
+// @Description Update a recurring transaction template. Occurrences that were already posted are not posted again.
+// @Accept json
+// @Produce json
+// @Param id path int true "Recurring Transaction ID"
+// @Param recurring body requests.CreateOrUpdateRecurringTransactionRequest true "Recurring Transaction"
+// @Success 200 {object} responses.RecurringTransactionResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /recurring/{id}/update [put]
+// @Tags recurring
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func UpdateRecurringTransactionHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	id, _ := strconv.Atoi(c.Param("id"))
+	var recurringRequest requests.CreateOrUpdateRecurringTransactionRequest
+	if err := c.ShouldBindJSON(&recurringRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := recurringRequest.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	recurring, err := getRecurringTransaction(id, uint(userId), db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err := checkUserAccount(recurringRequest.AccountID, uint(userId), db); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	update, err := recurringRequest.RecurringTransaction(uint(userId), db)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	skipPostedOccurrences(recurring.ID, update, db)
+	err = db.Model(&models.RecurringTransaction{}).Where("id = ?", recurring.ID).Updates(map[string]interface{}{
+		"name":                update.Name,
+		"account_id":          update.AccountID,
+		"amount":              update.Amount,
+		"currency":            update.Currency,
+		"description":         update.Description,
+		"payee":               update.Payee,
+		"category_id":         update.CategoryID,
+		"transaction_type_id": update.TransactionTypeID,
+		"recurrence":          update.Recurrence,
+		"start_date":          update.StartDate,
+		"next_date":           update.NextDate,
+		"auto_post":           update.AutoPost,
+		"active":              update.Active,
+	}).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	recurring, _ = getRecurringTransaction(recurring.ID, uint(userId), db)
+	response, err := serializers.NewRecurringTransactionSerializer(recurring, false).Serialize()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteRecurringTransactionHandler DeleteRecurringTransaction godoc
+// @Summary Delete a recurring transaction
+// @Description Delete a recurring transaction template, transactions it already posted are kept
+// @Param id path int true "Recurring Transaction ID"
+// @Success 204
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /recurring/{id} [delete]
+// @Tags recurring
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func DeleteRecurringTransactionHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	id, _ := strconv.Atoi(c.Param("id"))
+	recurring, err := getRecurringTransaction(id, uint(userId), db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err := db.Delete(&recurring).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetUpcomingTransactionsHandler GetUpcomingTransactions godoc
+// @Summary Get upcoming bills
+// @Description List the occurrences of the active recurring transactions over the next days, soonest first
+// @Produce json
+// @Param days query int false "Days" default(30)
+// @Success 200 {array} responses.UpcomingTransactionResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Router /recurring/upcoming [get]
+// @Tags recurring
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetUpcomingTransactionsHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 || days > 366 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and 366"})
+		return
+	}
+	var recurringTransactions []models.RecurringTransaction
+	scopes.GetUserRecurringTransactions(uint(userId), db).Where("active = ? AND next_date IS NOT NULL", true).Find(&recurringTransactions)
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	until := today.AddDate(0, 0, days)
+	upcoming := make([]responses.UpcomingTransactionResponse, 0)
+	for _, recurring := range recurringTransactions {
+		rule, err := recurrence.Parse(recurring.Recurrence)
+		if err != nil {
+			continue
+		}
+		for _, date := range rule.Between(recurring.StartDate, *recurring.NextDate, until, 0) {
+			upcoming = append(upcoming, responses.UpcomingTransactionResponse{
+				RecurringID: recurring.ID,
+				Name:        recurring.Name,
+				Date:        date.Unix(),
+				AccountID:   recurring.AccountID,
+				Amount:      recurring.Amount,
+				Currency:    recurring.Currency,
+				Payee:       recurring.Payee,
+				Category:    recurring.Category.Name,
+				AutoPost:    recurring.AutoPost,
+			})
+		}
+	}
+	sort.SliceStable(upcoming, func(i, j int) bool { return upcoming[i].Date < upcoming[j].Date })
+	c.JSON(http.StatusOK, upcoming)
+}
+
+func getRecurringTransaction(id int, userId uint, db *gorm.DB) (models.RecurringTransaction, error) {
+	var recurring models.RecurringTransaction
+	scopes.GetUserRecurringTransactionById(id, userId, db).First(&recurring)
+	if recurring.ID == 0 {
+		return recurring, errors.RecurringTransactionNotFoundError()
+	}
+	return recurring, nil
+}
+
+func checkUserAccount(accountId int, userId uint, db *gorm.DB) error {
+	var account models.Account
+	scopes.GetUserAccountById(accountId, userId, db).First(&account)
+	if account.ID == 0 {
+		return errors.AccountNotFoundError()
+	}
+	return nil
+}
+
+// skipPostedOccurrences moves the next date of an updated template past the
+// last transaction it posted.
+func skipPostedOccurrences(recurringId int, update *models.RecurringTransaction, db *gorm.DB) {
+	var lastPosted sql.NullTime
+	if err := scopes.LastRecurringTransactionDate(recurringId, db).Row().Scan(&lastPosted); err != nil {
+		return
+	}
+	if !lastPosted.Valid || update.NextDate == nil || update.NextDate.After(lastPosted.Time) {
+		return
+	}
+	rule, err := recurrence.Parse(update.Recurrence)
+	if err != nil {
+		return
+	}
+	if next, ok := rule.Next(update.StartDate, lastPosted.Time); ok {
+		update.NextDate = &next
+		return
+	}
+	update.NextDate = nil
+	update.Active = false
+}
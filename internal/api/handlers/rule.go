@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/christo-andrew/haven/internal/api/requests"
+	"github.com/christo-andrew/haven/internal/api/responses"
+	"github.com/christo-andrew/haven/internal/api/serializers"
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/auth"
+	"github.com/christo-andrew/haven/pkg/database/scopes"
+	"github.com/christo-andrew/haven/pkg/errors"
+	"github.com/christo-andrew/haven/pkg/rules"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetRulesHandler GetRules godoc
+// @Summary Get all rules
+// @Description Retrieve the categorisation rules of the current user in the order they are applied
+// @Produce json
+// @Success 200 {array} responses.RuleResponse
+// @Router /rules [get]
+// @Tags rules
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetRulesHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	var userRules []models.Rule
+	scopes.GetUserRules(uint(userId), db).Find(&userRules)
+	response, err := serializers.NewRuleSerializer(userRules, true).Serialize()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetRuleHandler GetRule godoc
+// @Summary Get a rule
+// @Description Retrieve a rule
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Success 200 {object} responses.RuleResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /rules/{id} [get]
+// @Tags rules
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetRuleHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	id, _ := strconv.Atoi(c.Param("id"))
+	rule, err := getRule(id, uint(userId), db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	response, err := serializers.NewRuleSerializer(rule, false).Serialize()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateRuleHandler CreateRule godoc
+// @Summary Create a rule
+// @Description Create a rule that categorises, tags or cleans up transactions when they are created or imported
+// @Accept json
+// @Produce json
+// @Param rule body requests.CreateOrUpdateRuleRequest true "Rule"
+// @Success 201 {object} responses.RuleResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Router /rules/create [post]
+// @Tags rules
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func CreateRuleHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	var ruleRequest requests.CreateOrUpdateRuleRequest
+	if err := c.ShouldBindJSON(&ruleRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := ruleRequest.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rule, err := ruleRequest.Rule(uint(userId), db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	rule.UserID = uint(userId)
+	if err := db.Create(rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	response, err := serializers.NewRuleSerializer(*rule, false).Serialize()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, response)
+}
+
+// UpdateRuleHandler UpdateRule godoc
+// @Summary Update a rule
+// @Description Update a rule, its conditions and actions are replaced by the ones in the request
+// @Accept json
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Param rule body requests.CreateOrUpdateRuleRequest true "Rule"
+// @Success 200 {object} responses.RuleResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /rules/{id}/update [put]
+// @Tags rules
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func UpdateRuleHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	id, _ := strconv.Atoi(c.Param("id"))
+	var ruleRequest requests.CreateOrUpdateRuleRequest
+	if err := c.ShouldBindJSON(&ruleRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := ruleRequest.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rule, err := getRule(id, uint(userId), db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	update, err := ruleRequest.Rule(uint(userId), db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	rule, err = updateRule(rule, update, db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	response, err := serializers.NewRuleSerializer(rule, false).Serialize()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteRuleHandler DeleteRule godoc
+// @Summary Delete a rule
+// @Description Delete a rule
+// @Param id path int true "Rule ID"
+// @Success 204
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /rules/{id} [delete]
+// @Tags rules
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func DeleteRuleHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	id, _ := strconv.Atoi(c.Param("id"))
+	rule, err := getRule(id, uint(userId), db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err := db.Select("Conditions", "Actions").Delete(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RunRulesHandler RunRules godoc
+// @Summary Run rules over existing transactions
+// @Description Apply rules retroactively to the transaction history. With preview set the affected transactions and changes are returned without saving anything.
+// @Accept json
+// @Produce json
+// @Param run body requests.RunRulesRequest true "Run Rules Request"
+// @Success 200 {object} responses.RunRulesResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Router /rules/run [post]
+// @Tags rules
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func RunRulesHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	var runRequest requests.RunRulesRequest
+	if err := c.ShouldBindJSON(&runRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	from, to, err := runRequest.DateRange()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := scopes.GetAllTransactions(db).Preload("Tags").Scopes(scopes.UserTransactions(userId))
+	if runRequest.AccountID != 0 {
+		query = query.Where("account_id = ?", runRequest.AccountID)
+	}
+	if !from.IsZero() {
+		query = query.Where("date >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("date < ?", to.AddDate(0, 0, 1))
+	}
+	var transactions []models.Transaction
+	query.Order("date ASC").Find(&transactions)
+
+	engine := rules.NewEngine(uint(userId), db, runRequest.RuleIDs...)
+	response := responses.RunRulesResponse{
+		Preview: runRequest.Preview,
+		Scanned: len(transactions),
+		Results: make([]responses.RuleRunResult, 0),
+	}
+	for i := range transactions {
+		transaction := &transactions[i]
+		changes := engine.Apply(transaction)
+		if len(changes) == 0 {
+			continue
+		}
+		if !runRequest.Preview {
+			if err := engine.Save(transaction, changes); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+		}
+		response.Results = append(response.Results, responses.RuleRunResult{
+			TransactionID: transaction.ID,
+			Date:          transaction.Date.Unix(),
+			Description:   transaction.Description,
+			Amount:        transaction.Amount,
+			Changes:       changes,
+		})
+	}
+	response.Affected = len(response.Results)
+	c.JSON(http.StatusOK, response)
+}
+
+func getRule(id int, userId uint, db *gorm.DB) (models.Rule, error) {
+	var rule models.Rule
+	scopes.GetUserRuleById(id, userId, db).First(&rule)
+	if rule.ID == 0 {
+		return rule, errors.RuleNotFoundError()
+	}
+	return rule, nil
+}
+
+func updateRule(rule models.Rule, update *models.Rule, db *gorm.DB) (models.Rule, error) {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("rule_id = ?", rule.ID).Delete(&models.RuleCondition{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("rule_id = ?", rule.ID).Delete(&models.RuleAction{}).Error; err != nil {
+			return err
+		}
+		err := tx.Model(&rule).Omit("Conditions", "Actions").Updates(map[string]interface{}{
+			"name":            update.Name,
+			"priority":        update.Priority,
+			"enabled":         update.Enabled,
+			"stop_processing": update.StopProcessing,
+		}).Error
+		if err != nil {
+			return err
+		}
+		for i := range update.Conditions {
+			update.Conditions[i].RuleID = rule.ID
+		}
+		for i := range update.Actions {
+			update.Actions[i].RuleID = rule.ID
+		}
+		if err := tx.Create(&update.Conditions).Error; err != nil {
+			return err
+		}
+		return tx.Create(&update.Actions).Error
+	})
+	if err != nil {
+		return rule, err
+	}
+	return getRule(rule.ID, rule.UserID, db)
+}
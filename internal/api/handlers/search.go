@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/christo-andrew/haven/internal/api/serializers"
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/auth"
+	"github.com/christo-andrew/haven/pkg/database/scopes"
+	"github.com/christo-andrew/haven/pkg/errors"
+	"github.com/christo-andrew/haven/pkg/pagination"
+	"github.com/christo-andrew/haven/pkg/search"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// FullTextSearchHandler FullTextSearch godoc
+// @Summary Full-text search over transactions
+// @Description Search the description, payee, reference, notes and tag names of the current user's transactions.
+// @Description Results are ranked by relevance and include snippets with the matching words wrapped in <mark> tags.
+// @Produce json
+// @Param q query string true "Search text"
+// @Param page query int false "Page number"
+// @Param limit query int false "Limit"
+// @Success 200 {object} pagination.Response{results=[]responses.SearchResultResponse}
+// @Failure 400 {object} responses.ErrorResponse
+// @Router /search/transactions [get]
+// @Tags search
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func FullTextSearchHandler(c *gin.Context, db *gorm.DB, searcher search.Searcher) {
+	userId := auth.GetUserIdFromContext(c)
+	text := strings.TrimSpace(c.Query("q"))
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	if text == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.EmptySearchQueryError().Error()})
+		return
+	}
+
+	hits, total, err := searcher.Search(userId, text, (page-1)*limit, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	results, err := searchResults(hits, text, db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	serialized, err := serializers.NewSearchResultSerializer(results, true).Serialize()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	paginator := pagination.Pagination{Page: page, Limit: limit, TotalCount: total}
+	c.JSON(http.StatusOK, pagination.Response{
+		Results:    serialized,
+		NextPage:   paginator.NextPage(),
+		PrevPage:   paginator.PrevPage(),
+		TotalCount: paginator.TotalCount,
+		Limit:      paginator.Limit,
+		Page:       paginator.Page,
+		LastPage:   paginator.LastPage(),
+	})
+}
+
+// searchResults loads the transactions of a page of hits, keeping the order
+// of the hits.
+func searchResults(hits []search.Hit, text string, db *gorm.DB) ([]search.Result, error) {
+	results := make([]search.Result, 0, len(hits))
+	if len(hits) == 0 {
+		return results, nil
+	}
+	ids := make([]int, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.TransactionID)
+	}
+	var transactions []models.Transaction
+	if err := scopes.GetAllTransactions(db).Preload("Tags").Where("id IN ?", ids).Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+	byId := make(map[int]models.Transaction, len(transactions))
+	for _, transaction := range transactions {
+		byId[transaction.ID] = transaction
+	}
+	for _, hit := range hits {
+		transaction, ok := byId[hit.TransactionID]
+		if !ok {
+			continue
+		}
+		results = append(results, search.Result{
+			Transaction: transaction,
+			Score:       hit.Score,
+			Snippets:    search.Snippets(transaction, text),
+		})
+	}
+	return results, nil
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/api/requests"
+	"github.com/christo-andrew/haven/internal/api/responses"
+	"github.com/christo-andrew/haven/internal/api/serializers"
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/auth"
+	"github.com/christo-andrew/haven/pkg/database/scopes"
+	"github.com/christo-andrew/haven/pkg/errors"
+	"github.com/christo-andrew/haven/pkg/subscriptions"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetSubscriptionsHandler GetSubscriptions godoc
+// @Summary Detect subscriptions
+// @Description Scan the transaction history for periodic charges to the same payee with similar amounts. Each subscription has its cadence, next expected date and flags for price increases and charges that stopped.
+// @Produce json
+// @Param months query int false "Months of history to scan" default(24)
+// @Success 200 {array} responses.SubscriptionResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Router /data/subscriptions [get]
+// @Tags data
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetSubscriptionsHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	months, err := strconv.Atoi(c.DefaultQuery("months", "24"))
+	if err != nil || months < 1 || months > 120 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "months must be between 1 and 120"})
+		return
+	}
+	detected := detectSubscriptions(userId, months, db)
+
+	confirmed := make(map[string]int)
+	var recurringTransactions []models.RecurringTransaction
+	scopes.GetUserRecurringTransactions(uint(userId), db).Where("subscription_key <> ''").Find(&recurringTransactions)
+	for _, recurring := range recurringTransactions {
+		confirmed[recurring.SubscriptionKey] = recurring.ID
+	}
+
+	response := make([]responses.SubscriptionResponse, 0, len(detected))
+	for _, subscription := range detected {
+		subscriptionResponse := responses.SubscriptionResponse{
+			Key:            subscription.Key,
+			Name:           subscription.Name,
+			PayeeID:        subscription.PayeeID,
+			AccountID:      subscription.AccountID,
+			Currency:       subscription.Currency,
+			Cadence:        subscription.Cadence,
+			Recurrence:     subscription.Recurrence,
+			Amount:         subscription.Amount,
+			AverageAmount:  subscription.AverageAmount,
+			Occurrences:    subscription.Occurrences,
+			FirstDate:      subscription.FirstDate.Unix(),
+			LastDate:       subscription.LastDate.Unix(),
+			NextDate:       subscription.NextDate.Unix(),
+			PriceIncreased: subscription.PriceIncreased,
+			PreviousAmount: subscription.PreviousAmount,
+			Stopped:        subscription.Stopped,
+			TransactionIDs: subscription.TransactionIDs,
+		}
+		if recurringId, ok := confirmed[subscription.Key]; ok {
+			subscriptionResponse.RecurringID = &recurringId
+		}
+		response = append(response, subscriptionResponse)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// ConfirmSubscriptionHandler ConfirmSubscription godoc
+// @Summary Confirm a detected subscription
+// @Description Turn a detected subscription into a recurring transaction that starts at its next expected charge
+// @Accept json
+// @Produce json
+// @Param subscription body requests.ConfirmSubscriptionRequest true "Confirm Subscription Request"
+// @Success 201 {object} responses.RecurringTransactionResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /data/subscriptions/confirm [post]
+// @Tags data
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func ConfirmSubscriptionHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	var confirmRequest requests.ConfirmSubscriptionRequest
+	if err := c.ShouldBindJSON(&confirmRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var existing int64
+	scopes.GetUserRecurringTransactions(uint(userId), db).Model(&models.RecurringTransaction{}).
+		Where("subscription_key = ?", confirmRequest.Key).Count(&existing)
+	if existing > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.SubscriptionAlreadyConfirmedError().Error()})
+		return
+	}
+
+	var subscription *subscriptions.Subscription
+	for _, detected := range detectSubscriptions(userId, 24, db) {
+		if detected.Key == confirmRequest.Key {
+			subscription = &detected
+			break
+		}
+	}
+	if subscription == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": errors.SubscriptionNotFoundError().Error()})
+		return
+	}
+	if subscription.Stopped {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.SubscriptionStoppedError().Error()})
+		return
+	}
+
+	name := confirmRequest.Name
+	if name == "" {
+		name = subscription.Name
+	}
+	nextDate := subscription.NextDate
+	recurring := models.RecurringTransaction{
+		UserID:            uint(userId),
+		Name:              name,
+		AccountID:         subscription.AccountID,
+		Amount:            subscription.Amount,
+		Currency:          subscription.Currency,
+		Description:       subscription.Name,
+		Payee:             subscription.Name,
+		CategoryID:        subscription.CategoryID,
+		TransactionTypeID: subscription.TransactionTypeID,
+		Recurrence:        subscription.Recurrence,
+		StartDate:         nextDate,
+		NextDate:          &nextDate,
+		AutoPost:          confirmRequest.AutoPost,
+		Active:            true,
+		SubscriptionKey:   subscription.Key,
+	}
+	if err := db.Omit("Account", "Category", "TransactionType").Create(&recurring).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	recurring, _ = getRecurringTransaction(recurring.ID, uint(userId), db)
+	response, err := serializers.NewRecurringTransactionSerializer(recurring, false).Serialize()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, response)
+}
+
+func detectSubscriptions(userId int, months int, db *gorm.DB) []subscriptions.Subscription {
+	now := time.Now()
+	var transactions []models.Transaction
+	scopes.GetUserSubscriptionHistory(userId, now.AddDate(0, -months, 0), db).Find(&transactions)
+	return subscriptions.Detect(transactions, now)
+}
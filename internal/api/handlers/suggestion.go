@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/christo-andrew/haven/internal/api/requests"
+	"github.com/christo-andrew/haven/internal/api/responses"
+	"github.com/christo-andrew/haven/internal/api/serializers"
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/auth"
+	"github.com/christo-andrew/haven/pkg/budgets"
+	"github.com/christo-andrew/haven/pkg/classifier"
+	"github.com/christo-andrew/haven/pkg/database/scopes"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var categoryClassifiers = classifier.NewRegistry()
+
+// GetTransactionSuggestionsHandler GetTransactionSuggestions godoc
+// @Summary Suggest categories for a transaction
+// @Description Suggest categories for a transaction learned from how the user categorised similar transactions before
+// @Produce json
+// @Param id path string true "Transaction ID"
+// @Param limit query int false "Limit"
+// @Success 200 {array} responses.CategorySuggestionResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /transactions/{id}/suggestions [get]
+// @Tags transactions
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetTransactionSuggestionsHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	transactionId, _ := strconv.Atoi(c.Param("id"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "3"))
+	transaction, err := getUserTransaction(transactionId, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	predictions := userClassifier(userId, db).Predict(transactionFeatures(transaction), limit)
+	c.JSON(http.StatusOK, categorySuggestions(predictions, db))
+}
+
+// UpdateTransactionCategoryHandler UpdateTransactionCategory godoc
+// @Summary Recategorise a transaction
+// @Description Change the category of a transaction. The change is used to improve future category suggestions.
+// @Accept json
+// @Produce json
+// @Param id path string true "Transaction ID"
+// @Param category body requests.UpdateTransactionCategoryRequest true "Category"
+// @Success 200 {object} responses.TransactionResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /transactions/{id}/category [put]
+// @Tags transactions
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func UpdateTransactionCategoryHandler(c *gin.Context, db *gorm.DB, alerter *budgets.Alerter) {
+	userId := auth.GetUserIdFromContext(c)
+	transactionId, _ := strconv.Atoi(c.Param("id"))
+	var categoryRequest requests.UpdateTransactionCategoryRequest
+	if err := c.ShouldBindJSON(&categoryRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	transaction, err := getUserTransaction(transactionId, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	previous := transaction
+	category := categoryRequest.GetCategory(uint(userId), db)
+	err = db.Model(&models.Transaction{}).Where("id = ?", transaction.ID).Update("category_id", category.ID).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	transaction.CategoryID = category.ID
+	transaction.Category = *category
+	unlearnTransaction(userId, previous)
+	learnTransaction(userId, transaction)
+	alerter.CheckLater(uint(userId))
+	c.JSON(http.StatusOK, serializers.NewTransactionSerializer(transaction, false).Serialize())
+}
+
+func userClassifier(userId int, db *gorm.DB) *classifier.NaiveBayes {
+	return categoryClassifiers.Get(uint(userId), func(model *classifier.NaiveBayes) {
+		var transactions []models.Transaction
+		scopes.GetCategorisedTransactions(userId, models.DefaultCategoryName, db).Find(&transactions)
+		for _, transaction := range transactions {
+			model.Learn(transaction.CategoryID, transactionFeatures(transaction))
+		}
+	})
+}
+
+// learnTransaction adds a categorised transaction to the user's classifier if
+// it is already loaded. Unloaded classifiers pick it up from the database when
+// they are trained.
+func learnTransaction(userId int, transaction models.Transaction) {
+	model, ok := categoryClassifiers.Loaded(uint(userId))
+	if !ok || transaction.Category.Name == models.DefaultCategoryName {
+		return
+	}
+	model.Learn(transaction.CategoryID, transactionFeatures(transaction))
+}
+
+func unlearnTransaction(userId int, transaction models.Transaction) {
+	model, ok := categoryClassifiers.Loaded(uint(userId))
+	if !ok || transaction.Category.Name == models.DefaultCategoryName {
+		return
+	}
+	model.Unlearn(transaction.CategoryID, transactionFeatures(transaction))
+}
+
+func transactionFeatures(transaction models.Transaction) []string {
+	return classifier.Features(transaction.Description, transaction.Payee, transaction.Amount)
+}
+
+func categorySuggestions(predictions []classifier.Prediction, db *gorm.DB) []responses.CategorySuggestionResponse {
+	suggestions := make([]responses.CategorySuggestionResponse, 0, len(predictions))
+	if len(predictions) == 0 {
+		return suggestions
+	}
+	var ids []int
+	for _, prediction := range predictions {
+		ids = append(ids, prediction.CategoryID)
+	}
+	var categories []models.Category
+	db.Where("id IN ?", ids).Find(&categories)
+	names := make(map[int]string, len(categories))
+	for _, category := range categories {
+		names[category.ID] = category.Name
+	}
+	for _, prediction := range predictions {
+		suggestions = append(suggestions, responses.CategorySuggestionResponse{
+			CategoryID: prediction.CategoryID,
+			Category:   names[prediction.CategoryID],
+			Confidence: prediction.Confidence,
+		})
+	}
+	return suggestions
+}
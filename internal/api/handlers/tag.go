@@ -0,0 +1,400 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/api/requests"
+	"github.com/christo-andrew/haven/internal/api/responses"
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/audit"
+	"github.com/christo-andrew/haven/pkg/auth"
+	"github.com/christo-andrew/haven/pkg/database/scopes"
+	"github.com/christo-andrew/haven/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// tagLinks are the join tables that attach tags to records, with the query
+// selecting the records of a user.
+var tagLinks = []struct {
+	table  string
+	column string
+	owned  string
+}{
+	{"transaction_tags", "transaction_id", `SELECT transactions.id FROM transactions
+				INNER JOIN accounts ON accounts.id = transactions.account_id
+				WHERE accounts.user_id = ?`},
+	{"transaction_split_tags", "transaction_split_id", `SELECT transaction_splits.id FROM transaction_splits
+				INNER JOIN transactions ON transactions.id = transaction_splits.transaction_id
+				INNER JOIN accounts ON accounts.id = transactions.account_id
+				WHERE accounts.user_id = ?`},
+	{"account_tags", "account_id", "SELECT id FROM accounts WHERE user_id = ?"},
+	{"budget_tags", "budget_id", "SELECT id FROM budgets WHERE user_id = ?"},
+}
+
+type tagUsage struct {
+	ID           int
+	Name         string
+	Transactions int
+	Splits       int
+	Accounts     int
+	Budgets      int
+	Rules        int
+}
+
+// GetTagsHandler GetTags godoc
+// @Summary Get the current user's tags
+// @Description List the tags the current user created or uses and the tags nobody owns, with how many of their transactions, splits, accounts, budgets and rules carry each
+// @Produce json
+// @Success 200 {array} responses.TagUsageResponse
+// @Router /tags [get]
+// @Tags tags
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetTagsHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	var rows []tagUsage
+	if err := scopes.GetUserTagUsage(userId, db).Scan(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	response := make([]responses.TagUsageResponse, 0, len(rows))
+	for _, row := range rows {
+		response = append(response, responses.TagUsageResponse{
+			ID:           row.ID,
+			Name:         row.Name,
+			Transactions: row.Transactions,
+			Splits:       row.Splits,
+			Accounts:     row.Accounts,
+			Budgets:      row.Budgets,
+			Rules:        row.Rules,
+			Total:        row.Transactions + row.Splits + row.Accounts + row.Budgets + row.Rules,
+		})
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateTagHandler CreateTag godoc
+// @Summary Create a tag
+// @Description Create a tag. Users have one tag per name, so the current user's tag with the name, or one nobody owns, is returned when there is one.
+// @Accept json
+// @Produce json
+// @Param tag body requests.CreateTagRequest true "Create Tag Request"
+// @Success 201 {object} responses.TagResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Router /tags [post]
+// @Tags tags
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func CreateTagHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	tag, err := requestedTag(c, userId, db)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, responses.TagResponse{}.FromTag(*tag))
+}
+
+// MergeTagsHandler MergeTags godoc
+// @Summary Merge tags
+// @Description Merge tags into a target tag. The current user's transactions, splits, accounts, budgets and rules carrying a merged tag carry the target instead.
+// @Description Other users of a merged tag keep it; merged tags that nobody uses any more are deleted.
+// @Accept json
+// @Produce json
+// @Param merge body requests.MergeTagsRequest true "Merge Tags Request"
+// @Success 200 {object} responses.TagResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /tags/merge [post]
+// @Tags tags
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func MergeTagsHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	var mergeRequest requests.MergeTagsRequest
+	if err := c.ShouldBindJSON(&mergeRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	target, err := getUserTag(mergeRequest.TargetID, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	var sourceIds []int
+	for _, sourceId := range mergeRequest.SourceIDs {
+		if sourceId == target.ID {
+			continue
+		}
+		if _, err := getUserTag(sourceId, userId, db); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		sourceIds = append(sourceIds, sourceId)
+	}
+	if err := mergeTags(target, sourceIds, userId, db); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, responses.TagResponse{}.FromTag(target))
+}
+
+// RenameTagHandler RenameTag godoc
+// @Summary Rename a tag
+// @Description Rename a tag. A tag only the current user uses is renamed in place; a tag other users also use is replaced on the current user's records by a tag with the new name.
+// @Accept json
+// @Produce json
+// @Param id path int true "Tag ID"
+// @Param tag body requests.RenameRequest true "Rename Tag Request"
+// @Success 200 {object} responses.TagResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /tags/{id}/rename [put]
+// @Tags tags
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func RenameTagHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	id, _ := strconv.Atoi(c.Param("id"))
+	var renameRequest requests.RenameRequest
+	if err := c.ShouldBindJSON(&renameRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	name := renameRequest.GetName()
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.EmptyNameError().Error()})
+		return
+	}
+	tag, err := getUserTag(id, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	renamed, err := renameTag(tag, name, userId, db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, responses.TagResponse{}.FromTag(renamed))
+}
+
+// DeleteTagHandler DeleteTag godoc
+// @Summary Delete a tag
+// @Description Remove a tag from the current user's records, putting the reassign_to tag in its place. The tag is deleted once nobody uses it.
+// @Produce json
+// @Param id path int true "Tag ID"
+// @Param reassign_to query int true "Tag to put in its place"
+// @Success 200 {object} responses.TagResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /tags/{id} [delete]
+// @Tags tags
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func DeleteTagHandler(c *gin.Context, db *gorm.DB) {
+	userId := uint(auth.GetUserIdFromContext(c))
+	id, _ := strconv.Atoi(c.Param("id"))
+	targetId, _ := strconv.Atoi(c.Query("reassign_to"))
+	if targetId == 0 || targetId == id {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.ReassignmentRequiredError().Error()})
+		return
+	}
+	if _, err := getUserTag(id, userId, db); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	target, err := getUserTag(targetId, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err := mergeTags(target, []int{id}, userId, db); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, responses.TagResponse{}.FromTag(target))
+}
+
+// requestedTag finds or creates the tag named in a CreateTagRequest body.
+func requestedTag(c *gin.Context, userId uint, db *gorm.DB) (*models.Tag, error) {
+	var createTagRequest requests.CreateTagRequest
+	if err := c.ShouldBindJSON(&createTagRequest); err != nil {
+		return nil, err
+	}
+	name := createTagRequest.GetName()
+	if name == "" {
+		return nil, errors.EmptyNameError()
+	}
+	return scopes.GetOrCreateTransactionTag(name, userId, db)
+}
+
+// attachedTags lists the tags of a transaction, account or budget.
+func attachedTags(record interface{}, db *gorm.DB) ([]models.Tag, error) {
+	tags := make([]models.Tag, 0)
+	err := db.Model(record).Order("name").Association("Tags").Find(&tags)
+	return tags, err
+}
+
+// detachTag removes a tag from a transaction, account or budget and lists the
+// tags it still carries.
+func detachTag(record interface{}, tagIdParam string, db *gorm.DB) ([]models.Tag, error) {
+	tagId, _ := strconv.Atoi(tagIdParam)
+	var tag models.Tag
+	db.Model(record).Where("tags.id = ?", tagId).Association("Tags").Find(&tag)
+	if tag.ID == 0 {
+		return nil, errors.TagNotFoundError()
+	}
+	if err := db.Model(record).Association("Tags").Delete(&tag); err != nil {
+		return nil, err
+	}
+	return attachedTags(record, db)
+}
+
+// tagIdsQuery reads the comma separated tag_ids query parameter that data
+// endpoints filter on.
+func tagIdsQuery(c *gin.Context) ([]int, error) {
+	value := c.Query("tag_ids")
+	if value == "" {
+		return nil, nil
+	}
+	var tagIds []int
+	for _, part := range strings.Split(value, ",") {
+		tagId, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, errors.InvalidTagIdError(part)
+		}
+		tagIds = append(tagIds, tagId)
+	}
+	return tagIds, nil
+}
+
+func getTag(id int, db *gorm.DB) (models.Tag, error) {
+	var tag models.Tag
+	db.First(&tag, id)
+	if tag.ID == 0 {
+		return tag, errors.TagNotFoundError()
+	}
+	return tag, nil
+}
+
+// getUserTag returns a tag the user can see: one of their own, one nobody
+// owns or one already on their records.
+func getUserTag(id int, userId uint, db *gorm.DB) (models.Tag, error) {
+	tag, err := getTag(id, db)
+	if err != nil || tag.UserID == nil || *tag.UserID == userId {
+		return tag, err
+	}
+	var userIds []uint
+	if err := scopes.GetTagUsers(tag.ID, db).Scan(&userIds).Error; err != nil {
+		return models.Tag{}, err
+	}
+	for _, user := range userIds {
+		if user == userId {
+			return tag, nil
+		}
+	}
+	return models.Tag{}, errors.TagNotFoundError()
+}
+
+// renameTag renames a tag for a user. When the user already has a tag with
+// the new name the tag is merged into it. Renaming a tag in place would rename
+// it for every user of it, so a shared tag is swapped for one of the user's
+// with the new name on their records instead.
+func renameTag(tag models.Tag, name string, userId uint, db *gorm.DB) (models.Tag, error) {
+	var existing models.Tag
+	db.Where("name = ? AND user_id = ? AND id <> ?", name, userId, tag.ID).First(&existing)
+	if existing.ID != 0 {
+		return existing, mergeTags(existing, []int{tag.ID}, userId, db)
+	}
+	var userIds []uint
+	if err := scopes.GetTagUsers(tag.ID, db).Scan(&userIds).Error; err != nil {
+		return tag, err
+	}
+	if tag.UserID != nil {
+		userIds = append(userIds, *tag.UserID)
+	}
+	shared := false
+	for _, user := range userIds {
+		shared = shared || user != userId
+	}
+	if !shared {
+		err := db.Model(&tag).Update("name", name).Error
+		tag.Name = name
+		return tag, err
+	}
+	renamed := models.Tag{Name: name, UserID: &userId}
+	if err := db.Create(&renamed).Error; err != nil {
+		return tag, err
+	}
+	return renamed, mergeTags(renamed, []int{tag.ID}, userId, db)
+}
+
+// mergeTags moves a user's uses of the source tags to the target, without
+// attaching the target twice to anything, and deletes the sources that are no
+// longer used by anyone.
+func mergeTags(target models.Tag, sourceIds []int, userId uint, db *gorm.DB) error {
+	if len(sourceIds) == 0 {
+		return nil
+	}
+	var sourceValues []string
+	for _, sourceId := range sourceIds {
+		sourceValues = append(sourceValues, strconv.Itoa(sourceId))
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, link := range tagLinks {
+			err := tx.Exec(`INSERT INTO `+link.table+` (`+link.column+`, tag_id)
+				SELECT DISTINCT `+link.column+`, ? FROM `+link.table+`
+				WHERE tag_id IN ? AND `+link.column+` IN (`+link.owned+`)
+				  AND `+link.column+` NOT IN (SELECT `+link.column+` FROM `+link.table+` WHERE tag_id = ?)`,
+				target.ID, sourceIds, userId, target.ID).Error
+			if err != nil {
+				return err
+			}
+			err = tx.Exec(`DELETE FROM `+link.table+` WHERE tag_id IN ? AND `+link.column+` IN (`+link.owned+`)`,
+				sourceIds, userId).Error
+			if err != nil {
+				return err
+			}
+		}
+		err := tx.Unscoped().Model(&models.Goal{}).
+			Where("tag_id IN ? AND user_id = ?", sourceIds, userId).
+			Update("tag_id", target.ID).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&models.RuleAction{}).
+			Where("type = ? AND value IN ?", models.RuleActionAddTag, sourceValues).
+			Where("rule_id IN (?)", tx.Model(&models.Rule{}).Select("id").Where("user_id = ?", userId)).
+			Update("value", strconv.Itoa(target.ID)).Error
+		if err != nil {
+			return err
+		}
+		for _, sourceId := range sourceIds {
+			var userIds []uint
+			if err := scopes.GetTagUsers(sourceId, tx).Scan(&userIds).Error; err != nil {
+				return err
+			}
+			if len(userIds) > 0 {
+				continue
+			}
+			// Deleted for good, so that its owner can make a tag with its
+			// name again.
+			if err := tx.Unscoped().Delete(&models.Tag{}, sourceId).Error; err != nil {
+				return err
+			}
+		}
+		// Searches index tags by their last change, and relinking them does
+		// not touch the tag itself.
+		if err := tx.Model(&target).UpdateColumn("updated_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, "tag", target.ID, models.AuditActionMerge, map[string]audit.Change{
+			"merged": {Old: sourceIds, New: target.ID},
+		})
+	})
+}
@@ -5,35 +5,126 @@ import (
 	"github.com/christo-andrew/haven/internal/api/responses"
 	"github.com/christo-andrew/haven/internal/api/serializers"
 	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/auth"
+	"github.com/christo-andrew/haven/pkg/budgets"
 	"github.com/christo-andrew/haven/pkg/database/scopes"
+	"github.com/christo-andrew/haven/pkg/errors"
+	"github.com/christo-andrew/haven/pkg/ledger"
+	"github.com/christo-andrew/haven/pkg/pagination"
+	"github.com/christo-andrew/haven/pkg/query"
 	"github.com/gin-gonic/gin"
 	"gopkg.in/yaml.v3"
 	"gorm.io/gorm"
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // GetAllTransactionsHandler GetAllTransactions godoc
 // @Summary Get all transactions
 // @Description Retrieve all transactions
 // @Produce json
+// @Param status query string false "Comma separated statuses" Enums(pending, cleared, reconciled, void)
 // @Success 200 {array} responses.TransactionResponse
+// @Failure 400 {object} responses.ErrorResponse
 // @Router /transactions [get]
 // @Tags transactions
 // @Security AuthToken
 // @Param Authorization header string true "Authorization"
 func GetAllTransactionsHandler(c *gin.Context, db *gorm.DB) {
-	serializer := serializers.NewTransactionSerializer(getAllTransactions(db), true)
+	statuses, err := transactionStatusFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	serializer := serializers.NewTransactionSerializer(getAllTransactions(statuses, db), true)
 	c.JSON(http.StatusOK, serializer.Serialize())
 }
 
-func getAllTransactions(db *gorm.DB) []models.Transaction {
+func getAllTransactions(statuses []string, db *gorm.DB) []models.Transaction {
 	var transactions []models.Transaction
-	scopes.GetAllTransactions(db).Find(&transactions)
+	query := scopes.GetAllTransactions(db)
+	if len(statuses) > 0 {
+		query = query.Scopes(scopes.WithTransactionStatus(statuses))
+	}
+	query.Find(&transactions)
 	return transactions
 }
 
+// transactionStatusFilter reads the comma separated status query parameter
+// of the list endpoints.
+func transactionStatusFilter(c *gin.Context) ([]string, error) {
+	value := c.Query("status")
+	if value == "" {
+		return nil, nil
+	}
+	statuses := strings.Split(value, ",")
+	for i, status := range statuses {
+		status = strings.ToLower(strings.TrimSpace(status))
+		if !models.IsTransactionStatus(status) {
+			return nil, errors.InvalidTransactionStatusError(status)
+		}
+		statuses[i] = status
+	}
+	return statuses, nil
+}
+
+// SearchTransactionsHandler SearchTransactions godoc
+// @Summary Search transactions
+// @Description Filter the current user's transactions with a query such as "category:groceries amount>50 -tag:reimbursed".
+// @Description Filters are field:value, field=value (exact), field!=value and, for amount and date, >, >=, < and <=.
+// @Description Ranges are written amount:10..50 or date:2024-01-01..2024-03-31, and dates may be a day, a month (2024-03) or a year.
+// @Description Fields are amount, date, category, type, tag, payee, account, status, description, reference, is (transfer, split, pending, recurring, uncategorised) and has (attachment, tag, payee).
+// @Description Other words match the description, payee or reference. Terms are joined with AND; OR, NOT or a leading "-" and parentheses are supported.
+// @Description sort:field orders the results, with sort:-field for descending; the fields are date, amount, payee, description and created. The default is newest first.
+// @Produce json
+// @Param q query string false "Query"
+// @Param page query int false "Page number"
+// @Param limit query int false "Limit"
+// @Success 200 {object} pagination.Response{results=[]responses.TransactionResponse}
+// @Failure 400 {object} responses.ErrorResponse
+// @Router /transactions/search [get]
+// @Tags transactions
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func SearchTransactionsHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	compiled, err := query.Compile(c.Query("q"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	paginator := pagination.Pagination{Page: page, Limit: limit}
+	transactions := scopes.GetAllTransactions(db).Scopes(scopes.UserTransactions(userId), compiled.Scope)
+	var results []models.Transaction
+	if err := paginator.Paginate(transactions, models.Transaction{}).Find(&results).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	serializer := serializers.NewTransactionSerializer(results, true)
+	response := pagination.Response{
+		Results:    serializer.Serialize(),
+		NextPage:   paginator.NextPage(),
+		PrevPage:   paginator.PrevPage(),
+		TotalCount: paginator.TotalCount,
+		Limit:      paginator.Limit,
+		Page:       paginator.Page,
+		LastPage:   paginator.LastPage(),
+	}
+	c.JSON(http.StatusOK, response)
+}
+
 // GetTransactionHandler GetTransaction godoc
 // @Summary Get a transaction
 // @Description Retrieve a transaction
@@ -56,6 +147,15 @@ func getTransaction(transactionID int, db *gorm.DB) models.Transaction {
 	return transaction
 }
 
+func getUserTransaction(transactionID int, userID int, db *gorm.DB) (models.Transaction, error) {
+	var transaction models.Transaction
+	scopes.GetUserTransactionById(transactionID, userID, db).First(&transaction)
+	if transaction.ID == 0 {
+		return transaction, errors.TransactionNotFoundError()
+	}
+	return transaction, nil
+}
+
 // CreateAccountTransactionHandler CreateTransaction godoc
 // @Summary Create a transaction
 // @Description Create a transaction
@@ -68,10 +168,10 @@ func getTransaction(transactionID int, db *gorm.DB) models.Transaction {
 // @Tags transactions
 // @Security AuthToken
 // @Param Authorization header string true "Authorization"
-func CreateAccountTransactionHandler(c *gin.Context, db *gorm.DB) {
+func CreateAccountTransactionHandler(c *gin.Context, db *gorm.DB, alerter *budgets.Alerter) {
 	batchCreate := c.Query("batch_create")
 	if batchCreate == "true" {
-		createBatchTransactions(c, db)
+		createBatchTransactions(c, db, alerter)
 		return
 	}
 	var transactionRequest requests.CreateTransactionRequest
@@ -80,49 +180,112 @@ func CreateAccountTransactionHandler(c *gin.Context, db *gorm.DB) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	transaction, err := createTransaction(&transactionRequest, db)
+	userId := auth.GetUserIdFromContext(c)
+	transaction, replaced, err := createTransaction(&transactionRequest, uint(userId), ledger.NewPoster(uint(userId), db), db)
 	response := serializers.NewTransactionSerializer(transaction, false).Serialize()
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	TransactionPosted(uint(userId), *transaction, replaced)
+	alerter.CheckLater(uint(userId))
 	c.JSON(http.StatusCreated, response)
 }
 
 // AddTransactionTagHandler AddTransactionTag godoc
 // @Summary Add a tag to a transaction
-// @Description Add a tag to a transaction
+// @Description Add a tag to a transaction, creating the tag when no tag has that name yet
 // @Accept json
 // @Produce json
 // @Param id path string true "Transaction ID"
 // @Param tag body requests.CreateTagRequest true "Create Tag Request"
 // @Success 201 {object} responses.TagResponse
 // @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
 // @Router /transactions/{id}/tags [post]
 // @Tags transactions
 // @Security AuthToken
 // @Param Authorization header string true "Authorization"
-func AddTransactionTagHandler(c *gin.Context, db *gorm.DB) {
+func AddTransactionTagHandler(c *gin.Context, db *gorm.DB, alerter *budgets.Alerter) {
+	userId := auth.GetUserIdFromContext(c)
 	transactionId, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	var createTagRequest requests.CreateTagRequest
-	err = c.ShouldBindJSON(&createTagRequest)
+	tag, err := requestedTag(c, uint(userId), db)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	transaction := getTransaction(transactionId, db)
-	tag := scopes.GetOrCreateTransactionTag(createTagRequest.Name, db)
-	response := serializers.NewTagSerializer(*tag, false).Serialize()
+	transaction, err := getUserTransaction(transactionId, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
 	err = db.Model(&transaction).Association("Tags").Append(tag)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusCreated, response)
+	alerter.CheckLater(uint(userId))
+	c.JSON(http.StatusCreated, serializers.NewTagSerializer(*tag, false).Serialize())
+}
+
+// RemoveTransactionTagHandler RemoveTransactionTag godoc
+// @Summary Remove a tag from a transaction
+// @Description Remove a tag from a transaction. The tag itself is kept.
+// @Produce json
+// @Param id path int true "Transaction ID"
+// @Param tag_id path int true "Tag ID"
+// @Success 200 {array} responses.TagResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /transactions/{id}/tags/{tag_id} [delete]
+// @Tags transactions
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func RemoveTransactionTagHandler(c *gin.Context, db *gorm.DB, alerter *budgets.Alerter) {
+	userId := auth.GetUserIdFromContext(c)
+	transactionId, _ := strconv.Atoi(c.Param("id"))
+	transaction, err := getUserTransaction(transactionId, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	tags, err := detachTag(&transaction, c.Param("tag_id"), db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	alerter.CheckLater(uint(userId))
+	c.JSON(http.StatusOK, serializers.NewTagSerializer(tags, true).Serialize())
+}
+
+// GetTransactionTagsHandler GetTransactionTags godoc
+// @Summary Get all tags for a transaction
+// @Description Retrieve all tags for a transaction
+// @Produce json
+// @Param id path string true "Transaction ID"
+// @Success 200 {array} responses.TagResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /transactions/{id}/tags [get]
+// @Tags transactions
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func GetTransactionTagsHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	transactionId, _ := strconv.Atoi(c.Param("id"))
+	transaction, err := getUserTransaction(transactionId, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	tags, err := attachedTags(&transaction, db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, serializers.NewTagSerializer(tags, true).Serialize())
 }
 
 // GetTransactionSchemasHandler GetTransactionSchemas godoc
@@ -152,61 +315,328 @@ func GetTransactionSchemasHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, transactionSchema)
 }
 
-// GetTransactionTagsHandler GetTransactionTags godoc
-// @Summary Get all tags for a transaction
-// @Description Retrieve all tags for a transaction
+// createTransaction saves a transaction and returns the pending authorisation
+// it replaced, if any. A payee created for it is saved along with it.
+func createTransaction(transactionRequest *requests.CreateTransactionRequest, userId uint, poster *ledger.Poster, db *gorm.DB) (*models.Transaction, *models.Transaction, error) {
+	if err := transactionRequest.Validate(); err != nil {
+		return &models.Transaction{}, nil, err
+	}
+	transaction, err := transactionRequest.Transaction(userId, db)
+	if err != nil {
+		return &models.Transaction{}, nil, err
+	}
+	transaction.Category = *transactionRequest.GetCategory(userId, db)
+	transaction.TransactionType = *transactionRequest.GetTransactionType(db)
+	var replaced *models.Transaction
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		replaced, err = poster.Post(transaction, tx)
+		return err
+	})
+
+	return transaction, replaced, err
+}
+
+func createBatchTransactions(c *gin.Context, db *gorm.DB, alerter *budgets.Alerter) {
+	var transactionRequests []requests.CreateTransactionRequest
+	err := c.ShouldBindJSON(&transactionRequests)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := auth.GetUserIdFromContext(c)
+	transactions, replaced, err := createTransactions(transactionRequests, uint(userId), ledger.NewPoster(uint(userId), db), db)
+	response := serializers.NewTransactionSerializer(transactions, true).Serialize()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for i, transaction := range transactions {
+		TransactionPosted(uint(userId), transaction, replaced[i])
+	}
+	alerter.CheckLater(uint(userId))
+	c.JSON(http.StatusCreated, response)
+}
+
+// createTransactions saves a batch of transactions, and the payees created
+// for them, all or none. The pending authorisation each transaction replaced
+// is returned at its index, nil if there was none.
+func createTransactions(transactionRequests []requests.CreateTransactionRequest, userId uint, poster *ledger.Poster, db *gorm.DB) ([]models.Transaction, []*models.Transaction, error) {
+	var transactions []models.Transaction
+	var replaced []*models.Transaction
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, transactionRequest := range transactionRequests {
+			if err := transactionRequest.Validate(); err != nil {
+				return err
+			}
+			transaction, err := transactionRequest.Transaction(userId, tx)
+			if err != nil {
+				return err
+			}
+			transaction.Category = *transactionRequest.GetCategory(userId, tx)
+			transaction.TransactionType = *transactionRequest.GetTransactionType(tx)
+			authorisation, err := poster.Post(transaction, tx)
+			if err != nil {
+				return err
+			}
+			transactions = append(transactions, *transaction)
+			replaced = append(replaced, authorisation)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return transactions, replaced, nil
+}
+
+// TransactionPosted brings what is learnt from a user's transactions up to
+// date with a transaction that has just been saved, and with the pending
+// authorisation it replaced if there was one.
+func TransactionPosted(userId uint, transaction models.Transaction, replaced *models.Transaction) {
+	if replaced != nil {
+		unlearnTransaction(int(userId), *replaced)
+	}
+	learnTransaction(int(userId), transaction)
+}
+
+// GetTransactionSplitsHandler GetTransactionSplits godoc
+// @Summary Get the splits of a transaction
+// @Description Retrieve the category split lines of a transaction
 // @Produce json
 // @Param id path string true "Transaction ID"
-// @Success 200 {array} responses.TagResponse
-// @Router /transactions/{id}/tags [get]
+// @Success 200 {array} responses.TransactionSplitResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /transactions/{id}/splits [get]
 // @Tags transactions
 // @Security AuthToken
 // @Param Authorization header string true "Authorization"
-func GetTransactionTagsHandler(c *gin.Context, db *gorm.DB) {
-	var transaction models.Transaction
-	transactionId, _ := strconv.Atoi(c.Param("id"))
-	scopes.GetTransactionByIdWithTags(transactionId, db).Find(&transaction)
-	tags := transaction.Tags
-	response := serializers.NewTagSerializer(tags, true).Serialize()
+func GetTransactionSplitsHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	transactionId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	transaction, err := getUserTransaction(transactionId, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	response := serializers.NewTransactionSerializer(transaction.Splits, true).Serialize()
 	c.JSON(http.StatusOK, response)
 }
 
-func createTransaction(transactionRequest *requests.CreateTransactionRequest, db *gorm.DB) (*models.Transaction, error) {
-	transaction := transactionRequest.Transaction(db)
-	transaction.Category = *transactionRequest.GetCategory(db)
-	transaction.TransactionType = *transactionRequest.GetTransactionType(db)
-	result := db.Create(transaction)
+// UpdateTransactionSplitsHandler UpdateTransactionSplits godoc
+// @Summary Split a transaction across categories
+// @Description Replace the split lines of a transaction. The split amounts must add up to the transaction amount, an empty list removes the split.
+// @Accept json
+// @Produce json
+// @Param id path string true "Transaction ID"
+// @Param splits body requests.UpdateTransactionSplitsRequest true "Transaction Splits"
+// @Success 200 {object} responses.TransactionResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /transactions/{id}/splits [put]
+// @Tags transactions
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func UpdateTransactionSplitsHandler(c *gin.Context, db *gorm.DB, alerter *budgets.Alerter) {
+	userId := auth.GetUserIdFromContext(c)
+	transactionId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var splitsRequest requests.UpdateTransactionSplitsRequest
+	if err := c.ShouldBindJSON(&splitsRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	transaction, err := getUserTransaction(transactionId, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err := requests.ValidateTransactionSplits(transaction.Amount, splitsRequest.Splits); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	splits, err := requests.TransactionSplits(splitsRequest.Splits, uint(userId), db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := replaceTransactionSplits(&transaction, splits, db); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	alerter.CheckLater(uint(userId))
+	c.JSON(http.StatusOK, serializers.NewTransactionSerializer(transaction, false).Serialize())
+}
 
-	return transaction, result.Error
+func replaceTransactionSplits(transaction *models.Transaction, splits []models.TransactionSplit, db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if transaction.HasSplits() {
+			if err := tx.Select("Tags").Delete(&transaction.Splits).Error; err != nil {
+				return err
+			}
+		}
+		for i := range splits {
+			splits[i].TransactionID = transaction.ID
+		}
+		if len(splits) > 0 {
+			if err := tx.Create(&splits).Error; err != nil {
+				return err
+			}
+		}
+		transaction.Splits = splits
+		return nil
+	})
 }
 
-func createBatchTransactions(c *gin.Context, db *gorm.DB) {
-	var transactionRequests []requests.CreateTransactionRequest
-	err := c.ShouldBindJSON(&transactionRequests)
+// ConfirmTransactionHandler ConfirmTransaction godoc
+// @Summary Confirm a pending transaction
+// @Description Confirm a transaction posted as pending by a recurring transaction. The amount and date can be corrected while confirming.
+// @Accept json
+// @Produce json
+// @Param id path string true "Transaction ID"
+// @Param confirm body requests.ConfirmTransactionRequest false "Confirm Transaction Request"
+// @Success 200 {object} responses.TransactionResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /transactions/{id}/confirm [post]
+// @Tags transactions
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func ConfirmTransactionHandler(c *gin.Context, db *gorm.DB, alerter *budgets.Alerter) {
+	userId := auth.GetUserIdFromContext(c)
+	transactionId, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	transactions, err := createTransactions(transactionRequests, db)
-	response := serializers.NewTransactionSerializer(transactions, true).Serialize()
+	var confirmRequest requests.ConfirmTransactionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&confirmRequest); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	transaction, err := getUserTransaction(transactionId, userId, db)
 	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if !transaction.IsPending() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.TransactionNotPendingError().Error()})
+		return
+	}
+	updates := map[string]interface{}{"transaction_status": models.TransactionStatusCleared}
+	if confirmRequest.Amount != nil {
+		if transaction.HasSplits() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errors.SplitAmountMismatchError(*confirmRequest.Amount, transaction.Amount).Error()})
+			return
+		}
+		updates["amount"] = *confirmRequest.Amount
+	}
+	if confirmRequest.Date != "" {
+		date, err := time.Parse(time.DateOnly, confirmRequest.Date)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		updates["date"] = date
+	}
+	if err := db.Model(&models.Transaction{}).Where("id = ?", transaction.ID).Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	alerter.CheckLater(uint(userId))
+	transaction, _ = getUserTransaction(transaction.ID, userId, db)
+	response := serializers.NewTransactionSerializer(transaction, false).Serialize()
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateTransactionNotesHandler UpdateTransactionNotes godoc
+// @Summary Update the notes of a transaction
+// @Description Replace the free-form notes of a transaction. Notes are included in full-text search.
+// @Accept json
+// @Produce json
+// @Param id path string true "Transaction ID"
+// @Param notes body requests.UpdateTransactionNotesRequest true "Notes"
+// @Success 200 {object} responses.TransactionResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /transactions/{id}/notes [put]
+// @Tags transactions
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func UpdateTransactionNotesHandler(c *gin.Context, db *gorm.DB) {
+	userId := auth.GetUserIdFromContext(c)
+	transactionId, _ := strconv.Atoi(c.Param("id"))
+	var notesRequest requests.UpdateTransactionNotesRequest
+	if err := c.ShouldBindJSON(&notesRequest); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusCreated, response)
+	transaction, err := getUserTransaction(transactionId, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	err = db.Model(&models.Transaction{}).Where("id = ?", transaction.ID).Update("notes", notesRequest.Notes).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	transaction.Notes = notesRequest.Notes
+	c.JSON(http.StatusOK, serializers.NewTransactionSerializer(transaction, false).Serialize())
 }
 
-func createTransactions(transactionRequests []requests.CreateTransactionRequest, db *gorm.DB) ([]models.Transaction, error) {
-	var transactions []models.Transaction
-	for _, transactionRequest := range transactionRequests {
-		transaction := transactionRequest.Transaction(db)
-		transaction.Category = *transactionRequest.GetCategory(db)
-		transaction.TransactionType = *transactionRequest.GetTransactionType(db)
-		result := db.Create(transaction)
-		if result.Error != nil {
-			return transactions, result.Error
-		}
-		transactions = append(transactions, *transaction)
+// UpdateTransactionStatusHandler UpdateTransactionStatus godoc
+// @Summary Change the status of a transaction
+// @Description Move a transaction through its lifecycle: pending to cleared or void, cleared to reconciled or void, and reconciled back to cleared. Void is final.
+// @Accept json
+// @Produce json
+// @Param id path string true "Transaction ID"
+// @Param status body requests.UpdateTransactionStatusRequest true "Status"
+// @Success 200 {object} responses.TransactionResponse
+// @Failure 400 {object} responses.ErrorResponse
+// @Failure 404 {object} responses.ErrorResponse
+// @Router /transactions/{id}/status [put]
+// @Tags transactions
+// @Security AuthToken
+// @Param Authorization header string true "Authorization"
+func UpdateTransactionStatusHandler(c *gin.Context, db *gorm.DB, alerter *budgets.Alerter) {
+	userId := auth.GetUserIdFromContext(c)
+	transactionId, _ := strconv.Atoi(c.Param("id"))
+	var statusRequest requests.UpdateTransactionStatusRequest
+	if err := c.ShouldBindJSON(&statusRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	transaction, err := getUserTransaction(transactionId, userId, db)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	status := strings.ToLower(statusRequest.Status)
+	if !models.IsTransactionStatus(status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.InvalidTransactionStatusError(status).Error()})
+		return
+	}
+	if !transaction.CanTransitionTo(status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.InvalidStatusTransitionError(transaction.Status(), status).Error()})
+		return
+	}
+	err = db.Model(&models.Transaction{}).Where("id = ?", transaction.ID).Update("transaction_status", status).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
-	return transactions, nil
+	alerter.CheckLater(uint(userId))
+	transaction.TransactionStatus = status
+	c.JSON(http.StatusOK, serializers.NewTransactionSerializer(transaction, false).Serialize())
 }
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"github.com/christo-andrew/haven/pkg/audit"
 	"github.com/christo-andrew/haven/pkg/auth"
 	"github.com/gin-gonic/gin"
 )
@@ -20,6 +21,7 @@ func WithAuthUser() gin.HandlerFunc {
 		}
 
 		c.Set("user", claims)
+		c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), uint(auth.GetUserIdFromContext(c))))
 		c.Next()
 	}
 }
@@ -7,12 +7,33 @@ import (
 )
 
 type GenericCreateAccountRequest struct {
-	AccountName string  `json:"account_name"`
-	AccountType string  `json:"account_type"`
-	Currency    string  `json:"currency"`
-	UserID      uint    `json:"user_id"`
-	Balance     float64 `json:"balance"`
-	Category    string  `json:"category"`
+	AccountName    string  `json:"account_name"`
+	AccountType    string  `json:"account_type"`
+	Currency       string  `json:"currency"`
+	UserID         uint    `json:"user_id"`
+	Balance        float64 `json:"balance"`
+	Category       string  `json:"category"`
+	InterestRate   float64 `json:"interest_rate"`
+	MinimumPayment float64 `json:"minimum_payment"`
+}
+
+// UpdateAccountRequest changes the terms of what is owed on a credit card or
+// loan. Fields left out are kept.
+type UpdateAccountRequest struct {
+	InterestRate   *float64 `json:"interest_rate" binding:"omitempty,gte=0"`
+	MinimumPayment *float64 `json:"minimum_payment" binding:"omitempty,gte=0"`
+}
+
+// Updates returns the columns the request changes.
+func (updateAccountRequest *UpdateAccountRequest) Updates() map[string]interface{} {
+	updates := make(map[string]interface{})
+	if updateAccountRequest.InterestRate != nil {
+		updates["interest_rate"] = *updateAccountRequest.InterestRate
+	}
+	if updateAccountRequest.MinimumPayment != nil {
+		updates["minimum_payment"] = *updateAccountRequest.MinimumPayment
+	}
+	return updates
 }
 
 type CreateBankAccountRequest struct {
@@ -69,7 +90,7 @@ func GetAccountRequest(genericReq *GenericCreateAccountRequest) (CreateAccountRe
 	case "real_estate":
 		return &CreateRealEstateAccountRequest{GenericCreateAccountRequest: genericReq}, nil
 	case "loan":
-		return &CreateRealEstateAccountRequest{GenericCreateAccountRequest: genericReq}, nil
+		return &CreateLoanAccountRequest{GenericCreateAccountRequest: genericReq}, nil
 	case "investment":
 		return &CreateRealEstateAccountRequest{GenericCreateAccountRequest: genericReq}, nil
 	case "asset":
@@ -96,6 +117,12 @@ func (c CreateCreditCardAccountRequest) Account() models.IAccount {
 	}
 }
 
+func (c CreateLoanAccountRequest) Account() models.IAccount {
+	return &models.LoanAccount{
+		Account: c.createAccount(),
+	}
+}
+
 func (c CreateRealEstateAccountRequest) Account() models.IAccount {
 	return &models.RealEstateAccount{
 		Account: c.createAccount(),
@@ -105,10 +132,12 @@ func (c CreateRealEstateAccountRequest) Account() models.IAccount {
 // createAccount creates an account based on the request
 func (c *GenericCreateAccountRequest) createAccount() models.Account {
 	return models.Account{
-		AccountName: c.AccountName,
-		AccountType: c.AccountType,
-		Currency:    c.Currency,
-		UserID:      c.UserID,
-		Balance:     c.Balance,
+		AccountName:    c.AccountName,
+		AccountType:    c.AccountType,
+		Currency:       c.Currency,
+		UserID:         c.UserID,
+		Balance:        c.Balance,
+		InterestRate:   c.InterestRate,
+		MinimumPayment: c.MinimumPayment,
 	}
 }
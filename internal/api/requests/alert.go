@@ -0,0 +1,66 @@
+package requests
+
+import (
+	"context"
+	"strings"
+
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/errors"
+	"github.com/christo-andrew/haven/pkg/notifications"
+)
+
+// BudgetAlertRequest creates or updates a budget alert. Threshold is a
+// percentage of the limit, 100 unless given; with a category_id the alert
+// watches that line of the budget rather than the whole budget. Alerts are
+// delivered in-app unless other channels are given; webhooks are posted to
+// an https URL.
+type BudgetAlertRequest struct {
+	Type       string   `json:"type" binding:"required,oneof=percentage projected" enums:"percentage,projected"`
+	Threshold  float64  `json:"threshold" binding:"gte=0"`
+	CategoryID *int     `json:"category_id"`
+	Channels   []string `json:"channels" binding:"dive,oneof=in_app email webhook" enums:"in_app,email,webhook"`
+	WebhookURL string   `json:"webhook_url" binding:"omitempty,url"`
+	Active     *bool    `json:"active"`
+}
+
+// Validate checks that a webhook URL is given when webhooks are asked for,
+// and that it is an https URL on the internet rather than an address inside
+// the server's network.
+func (r BudgetAlertRequest) Validate() error {
+	for _, channel := range r.Channels {
+		if channel == notifications.ChannelWebhook && r.WebhookURL == "" {
+			return errors.WebhookURLRequiredError()
+		}
+	}
+	if r.WebhookURL != "" {
+		return notifications.CheckWebhookURL(context.Background(), r.WebhookURL)
+	}
+	return nil
+}
+
+// BudgetAlert returns the alert the request describes. The alert starts
+// afresh, so it can fire again in the current period.
+func (r BudgetAlertRequest) BudgetAlert(budgetId uint, userId uint) models.BudgetAlert {
+	threshold := r.Threshold
+	if threshold == 0 {
+		threshold = 100
+	}
+	channels := r.Channels
+	if len(channels) == 0 {
+		channels = []string{notifications.ChannelInApp}
+	}
+	active := true
+	if r.Active != nil {
+		active = *r.Active
+	}
+	return models.BudgetAlert{
+		UserID:     userId,
+		BudgetID:   budgetId,
+		CategoryID: r.CategoryID,
+		Type:       r.Type,
+		Threshold:  threshold,
+		Channels:   strings.Join(channels, ","),
+		WebhookURL: r.WebhookURL,
+		Active:     active,
+	}
+}
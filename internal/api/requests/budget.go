@@ -2,27 +2,214 @@ package requests
 
 import (
 	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/budgets"
+	"github.com/christo-andrew/haven/pkg/errors"
 	"time"
 )
 
+// CreateOrUpdateBudgetRequest creates or updates a budget. A budget either
+// covers one category with its amount, or has lines in Categories, each with
+// a limit of its own, and an amount that is the sum of the lines. A recurring
+// budget starts again every period; its end date defaults to the end of the
+// first period. An update keeps what it leaves out, see KeepUnset.
 type CreateOrUpdateBudgetRequest struct {
-	Name             string  `json:"name" binding:"required"`
-	Description      string  `json:"description"`
-	Amount           float64 `json:"amount" binding:"required"`
-	BudgetCategoryID uint    `json:"category_id" binding:"required"`
-	StartDate        string  `json:"start_date" binding:"required"`
-	EndDate          string  `json:"end_date" binding:"required"`
+	Name             string                  `json:"name" binding:"required"`
+	Description      string                  `json:"description"`
+	Amount           float64                 `json:"amount"`
+	BudgetCategoryID uint                    `json:"category_id"`
+	StartDate        string                  `json:"start_date" binding:"required"`
+	EndDate          string                  `json:"end_date"`
+	Categories       []BudgetCategoryRequest `json:"categories" binding:"dive"`
+	Recurring        *bool                   `json:"recurring"`
+	Period           string                  `json:"period" enums:"weekly,monthly,quarterly,yearly,custom"`
+	PeriodDays       int                     `json:"period_days"`
+	Rollover         string                  `json:"rollover" binding:"omitempty,oneof=none unspent overspent both" enums:"none,unspent,overspent,both"`
+}
+
+// BudgetCategoryRequest is a line of a budget. GoalID links the line to a
+// goal, what the line leaves unspent counting towards the goal.
+type BudgetCategoryRequest struct {
+	CategoryID int     `json:"category_id" binding:"required"`
+	Amount     float64 `json:"amount" binding:"gt=0"`
+	GoalID     *uint   `json:"goal_id"`
+}
+
+// Validate checks the request. A budget that already has lines keeps them
+// when the request leaves Categories out, so it needs no category_id.
+func (createOrUpdateBudgetRequest *CreateOrUpdateBudgetRequest) Validate(hasLines bool) error {
+	if createOrUpdateBudgetRequest.IsRecurring() {
+		if err := budgets.ValidatePeriod(createOrUpdateBudgetRequest.GetPeriod(), createOrUpdateBudgetRequest.PeriodDays); err != nil {
+			return err
+		}
+	} else if createOrUpdateBudgetRequest.EndDate == "" {
+		return errors.BudgetEndDateRequiredError()
+	}
+	if len(createOrUpdateBudgetRequest.Categories) == 0 {
+		if hasLines {
+			return nil
+		}
+		if createOrUpdateBudgetRequest.BudgetCategoryID == 0 {
+			return errors.BudgetCategoryRequiredError()
+		}
+		if createOrUpdateBudgetRequest.Amount <= 0 {
+			return errors.InvalidBudgetAmountError()
+		}
+		return nil
+	}
+	seen := make(map[int]bool)
+	for _, line := range createOrUpdateBudgetRequest.Categories {
+		if seen[line.CategoryID] {
+			return errors.DuplicateBudgetCategoryError()
+		}
+		seen[line.CategoryID] = true
+	}
+	return nil
 }
 
 func (createOrUpdateBudgetRequest *CreateOrUpdateBudgetRequest) Budget() *models.Budget {
 	startDate, _ := time.Parse("2006-01-02", createOrUpdateBudgetRequest.StartDate)
 	endDate, _ := time.Parse("2006-01-02", createOrUpdateBudgetRequest.EndDate)
+	amount := createOrUpdateBudgetRequest.Amount
+	if len(createOrUpdateBudgetRequest.Categories) > 0 {
+		amount = 0
+		for _, line := range createOrUpdateBudgetRequest.Categories {
+			amount += line.Amount
+		}
+	}
+	period := createOrUpdateBudgetRequest.GetPeriod()
+	if createOrUpdateBudgetRequest.IsRecurring() && createOrUpdateBudgetRequest.EndDate == "" {
+		endDate = budgets.PeriodEnd(period, createOrUpdateBudgetRequest.PeriodDays, startDate, 0)
+	}
+	rollover := createOrUpdateBudgetRequest.Rollover
+	if rollover == "" {
+		rollover = models.BudgetRolloverNone
+	}
 	return &models.Budget{
 		Name:        createOrUpdateBudgetRequest.Name,
 		Description: createOrUpdateBudgetRequest.Description,
-		Amount:      createOrUpdateBudgetRequest.Amount,
+		Amount:      amount,
 		CategoryID:  createOrUpdateBudgetRequest.BudgetCategoryID,
 		StartDate:   startDate,
 		EndDate:     endDate,
+		Recurring:   createOrUpdateBudgetRequest.IsRecurring(),
+		Period:      period,
+		PeriodDays:  createOrUpdateBudgetRequest.PeriodDays,
+		Rollover:    rollover,
+	}
+}
+
+// KeepUnset fills in what an update leaves out from the budget it updates,
+// so that a request without recurring or period does not reset them. The end
+// date of a recurring budget is not kept but worked out again from the start
+// date when left out.
+func (createOrUpdateBudgetRequest *CreateOrUpdateBudgetRequest) KeepUnset(budget models.Budget) {
+	if createOrUpdateBudgetRequest.Description == "" {
+		createOrUpdateBudgetRequest.Description = budget.Description
+	}
+	if createOrUpdateBudgetRequest.Amount == 0 {
+		createOrUpdateBudgetRequest.Amount = budget.Amount
+	}
+	if createOrUpdateBudgetRequest.BudgetCategoryID == 0 {
+		createOrUpdateBudgetRequest.BudgetCategoryID = budget.CategoryID
+	}
+	if createOrUpdateBudgetRequest.Recurring == nil {
+		recurring := budget.Recurring
+		createOrUpdateBudgetRequest.Recurring = &recurring
+	}
+	if createOrUpdateBudgetRequest.Period == "" {
+		createOrUpdateBudgetRequest.Period = budget.Period
+		if createOrUpdateBudgetRequest.PeriodDays == 0 {
+			createOrUpdateBudgetRequest.PeriodDays = budget.PeriodDays
+		}
+	}
+	if createOrUpdateBudgetRequest.Rollover == "" {
+		createOrUpdateBudgetRequest.Rollover = budget.Rollover
+	}
+	if createOrUpdateBudgetRequest.EndDate == "" && !createOrUpdateBudgetRequest.IsRecurring() {
+		createOrUpdateBudgetRequest.EndDate = budget.EndDate.Format(time.DateOnly)
+	}
+}
+
+// Updates returns the columns an update writes. They go in one map so that
+// a budget can be made not recurring, which a struct update would skip.
+func (createOrUpdateBudgetRequest *CreateOrUpdateBudgetRequest) Updates() map[string]interface{} {
+	budget := createOrUpdateBudgetRequest.Budget()
+	return map[string]interface{}{
+		"name":        budget.Name,
+		"description": budget.Description,
+		"amount":      budget.Amount,
+		"category_id": budget.CategoryID,
+		"start_date":  budget.StartDate,
+		"end_date":    budget.EndDate,
+		"recurring":   budget.Recurring,
+		"period":      budget.Period,
+		"period_days": budget.PeriodDays,
+		"rollover":    budget.Rollover,
+	}
+}
+
+// IsRecurring reports whether the budget starts again every period.
+func (createOrUpdateBudgetRequest *CreateOrUpdateBudgetRequest) IsRecurring() bool {
+	return createOrUpdateBudgetRequest.Recurring != nil && *createOrUpdateBudgetRequest.Recurring
+}
+
+// GetPeriod returns the period of the budget, monthly unless given.
+func (createOrUpdateBudgetRequest *CreateOrUpdateBudgetRequest) GetPeriod() string {
+	if createOrUpdateBudgetRequest.Period == "" {
+		return models.BudgetPeriodMonthly
+	}
+	return createOrUpdateBudgetRequest.Period
+}
+
+// Lines returns the lines of the budget, which are empty for a budget
+// covering a single category.
+func (createOrUpdateBudgetRequest *CreateOrUpdateBudgetRequest) Lines() []models.BudgetCategory {
+	var lines []models.BudgetCategory
+	for _, line := range createOrUpdateBudgetRequest.Categories {
+		lines = append(lines, line.BudgetCategory())
+	}
+	return lines
+}
+
+func (budgetCategoryRequest BudgetCategoryRequest) BudgetCategory() models.BudgetCategory {
+	return models.BudgetCategory{
+		CategoryID: budgetCategoryRequest.CategoryID,
+		Amount:     budgetCategoryRequest.Amount,
+		GoalID:     budgetCategoryRequest.GoalID,
+	}
+}
+
+// BudgetReportQuery parses the range and the period of a budget report. The
+// range runs from the start of the year to today unless given, both dates
+// included, and is split into monthly periods unless another is given. It
+// may not be split into more than budgets.MaxReportPeriods periods.
+func BudgetReportQuery(from string, to string, period string) (time.Time, time.Time, string, error) {
+	now := time.Now().UTC()
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	var err error
+	if to != "" {
+		if end, err = time.Parse(time.DateOnly, to); err != nil {
+			return end, end, period, err
+		}
+	}
+	start := time.Date(end.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	if from != "" {
+		if start, err = time.Parse(time.DateOnly, from); err != nil {
+			return start, end, period, err
+		}
+	}
+	if end.Before(start) {
+		return start, end, period, errors.InvalidDateRangeError()
+	}
+	switch period {
+	case "":
+		period = models.BudgetPeriodMonthly
+	case models.BudgetPeriodWeekly, models.BudgetPeriodMonthly, models.BudgetPeriodQuarterly, models.BudgetPeriodYearly:
+	default:
+		return start, end, period, errors.InvalidReportPeriodError(period)
+	}
+	if !budgets.PeriodStart(period, 0, start, budgets.MaxReportPeriods).After(end) {
+		return start, end, period, errors.ReportTooLongError(budgets.MaxReportPeriods)
 	}
+	return start, end, period, nil
 }
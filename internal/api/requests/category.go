@@ -7,6 +7,7 @@ type CreateCategoryRequest struct {
 	Description string `json:"description"`
 	Context     string `json:"context"`
 	ContextType string `json:"context_type"`
+	ParentID    *int   `json:"parent_id"`
 }
 
 func (c CreateCategoryRequest) Category() *models.Category {
@@ -15,5 +16,33 @@ func (c CreateCategoryRequest) Category() *models.Category {
 		Description: c.Description,
 		Context:     c.Context,
 		ContextType: c.ContextType,
+		ParentID:    c.ParentID,
 	}
 }
+
+// MoveCategoryRequest moves a category under another one. A null parent
+// makes it a top level category.
+type MoveCategoryRequest struct {
+	ParentID *int `json:"parent_id"`
+}
+
+type MergeCategoriesRequest struct {
+	SourceIDs []int `json:"source_ids" binding:"required,min=1"`
+	TargetID  int   `json:"target_id" binding:"required"`
+}
+
+// UpdateCategoryOverrideRequest personalises a category for one user. Empty
+// fields keep the category's own value.
+type UpdateCategoryOverrideRequest struct {
+	Name   string `json:"name" binding:"max=255"`
+	Hidden bool   `json:"hidden"`
+	Color  string `json:"color" binding:"omitempty,hexcolor"`
+	Icon   string `json:"icon" binding:"max=64"`
+}
+
+func (r UpdateCategoryOverrideRequest) Apply(override *models.CategoryOverride) {
+	override.Name = r.Name
+	override.Hidden = r.Hidden
+	override.Color = r.Color
+	override.Icon = r.Icon
+}
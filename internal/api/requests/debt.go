@@ -0,0 +1,35 @@
+package requests
+
+// DebtPlanRequest plans paying off credit cards and loans out of a monthly
+// budget, starting in StartMonth, this month unless given. The debts are the
+// credit card and loan accounts in AccountIDs, or all of the user's when left
+// out. Debts overrides the interest rate or the minimum payment of
+// an account for the plan. With CustomOrder given, a plan paying the debts
+// in that order is made along with the snowball and avalanche plans.
+type DebtPlanRequest struct {
+	MonthlyBudget float64            `json:"monthly_budget" binding:"gt=0"`
+	StartMonth    string             `json:"start_month" example:"2024-01"`
+	AccountIDs    []int              `json:"account_ids"`
+	Debts         []DebtTermsRequest `json:"debts" binding:"dive"`
+	CustomOrder   []int              `json:"custom_order"`
+}
+
+type DebtTermsRequest struct {
+	AccountID      int      `json:"account_id" binding:"required"`
+	InterestRate   *float64 `json:"interest_rate" binding:"omitempty,gte=0"`
+	MinimumPayment *float64 `json:"minimum_payment" binding:"omitempty,gte=0"`
+}
+
+// GetCustomOrder returns the custom order with any account given twice kept
+// in its first place.
+func (debtPlanRequest *DebtPlanRequest) GetCustomOrder() []int {
+	var accountIds []int
+	seen := make(map[int]bool)
+	for _, accountId := range debtPlanRequest.CustomOrder {
+		if !seen[accountId] {
+			seen[accountId] = true
+			accountIds = append(accountIds, accountId)
+		}
+	}
+	return accountIds
+}
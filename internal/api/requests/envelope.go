@@ -0,0 +1,36 @@
+package requests
+
+import "github.com/christo-andrew/haven/internal/models"
+
+// CreateEnvelopeRequest turns a category into an envelope. Cover decides
+// what happens when the envelope ends a month overspent, to_be_budgeted
+// unless given.
+type CreateEnvelopeRequest struct {
+	CategoryID int    `json:"category_id" binding:"required"`
+	Cover      string `json:"cover" binding:"omitempty,oneof=to_be_budgeted carry" enums:"to_be_budgeted,carry"`
+}
+
+func (r CreateEnvelopeRequest) GetCover() string {
+	if r.Cover == "" {
+		return models.EnvelopeCoverToBeBudgeted
+	}
+	return r.Cover
+}
+
+type UpdateEnvelopeRequest struct {
+	Cover string `json:"cover" binding:"required,oneof=to_be_budgeted carry" enums:"to_be_budgeted,carry"`
+}
+
+// AssignEnvelopeRequest sets what is assigned to an envelope for a month.
+type AssignEnvelopeRequest struct {
+	Amount float64 `json:"amount" binding:"gte=0"`
+}
+
+// MoveEnvelopeMoneyRequest moves money assigned for a month from one envelope
+// to another.
+type MoveEnvelopeMoneyRequest struct {
+	FromEnvelopeID uint    `json:"from_envelope_id" binding:"required"`
+	ToEnvelopeID   uint    `json:"to_envelope_id" binding:"required"`
+	Month          string  `json:"month" binding:"required" example:"2024-01"`
+	Amount         float64 `json:"amount" binding:"gt=0"`
+}
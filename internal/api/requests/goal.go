@@ -0,0 +1,68 @@
+package requests
+
+import (
+	"time"
+
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/errors"
+)
+
+// GoalRequest creates or updates a goal. The goal starts today unless given.
+// Progress towards it comes from the balances of the accounts in AccountIDs,
+// from transactions carrying the tag in TagID and from the budget lines
+// linked to it.
+type GoalRequest struct {
+	Name         string  `json:"name" binding:"required"`
+	Description  string  `json:"description"`
+	TargetAmount float64 `json:"target_amount" binding:"gt=0"`
+	StartDate    string  `json:"start_date" example:"2024-01-01"`
+	TargetDate   string  `json:"target_date" binding:"required" example:"2024-12-31"`
+	TagID        *int    `json:"tag_id"`
+	AccountIDs   []int   `json:"account_ids"`
+}
+
+func (goalRequest *GoalRequest) Validate() error {
+	startDate, err := goalRequest.GetStartDate()
+	if err != nil {
+		return err
+	}
+	targetDate, err := time.Parse(time.DateOnly, goalRequest.TargetDate)
+	if err != nil {
+		return err
+	}
+	if !targetDate.After(startDate) {
+		return errors.InvalidGoalDatesError()
+	}
+	return nil
+}
+
+// GetStartDate returns the first day of the goal, today unless given.
+func (goalRequest *GoalRequest) GetStartDate() (time.Time, error) {
+	if goalRequest.StartDate == "" {
+		now := time.Now().UTC()
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), nil
+	}
+	return time.Parse(time.DateOnly, goalRequest.StartDate)
+}
+
+func (goalRequest *GoalRequest) Goal(userId uint) *models.Goal {
+	startDate, _ := goalRequest.GetStartDate()
+	targetDate, _ := time.Parse(time.DateOnly, goalRequest.TargetDate)
+	goal := &models.Goal{
+		UserID:       userId,
+		Name:         goalRequest.Name,
+		Description:  goalRequest.Description,
+		TargetAmount: goalRequest.TargetAmount,
+		StartDate:    startDate,
+		TargetDate:   targetDate,
+		TagID:        goalRequest.TagID,
+	}
+	seen := make(map[int]bool)
+	for _, accountId := range goalRequest.AccountIDs {
+		if !seen[accountId] {
+			seen[accountId] = true
+			goal.Accounts = append(goal.Accounts, models.GoalAccount{AccountID: accountId})
+		}
+	}
+	return goal
+}
@@ -0,0 +1,71 @@
+package requests
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/database/scopes"
+	"gorm.io/gorm"
+)
+
+// CreateOrUpdatePayeeRequest describes a payee. Aliases are other names the
+// payee appears under, patterns are regular expressions matched against the
+// raw description of imported transactions.
+type CreateOrUpdatePayeeRequest struct {
+	Name            string   `json:"name" binding:"required"`
+	DefaultCategory string   `json:"default_category"`
+	Aliases         []string `json:"aliases"`
+	Patterns        []string `json:"patterns"`
+}
+
+func (r *CreateOrUpdatePayeeRequest) Validate() error {
+	for _, pattern := range r.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+func (r *CreateOrUpdatePayeeRequest) Payee(userId uint, db *gorm.DB) *models.Payee {
+	payee := &models.Payee{Name: r.Name}
+	if r.DefaultCategory != "" {
+		category := scopes.GetOrCreateTransactionCategory(r.DefaultCategory, userId, db)
+		payee.DefaultCategoryID = &category.ID
+		payee.DefaultCategory = category
+	}
+	for _, alias := range r.Aliases {
+		payee.Aliases = append(payee.Aliases, models.PayeeAlias{Name: alias})
+	}
+	for _, pattern := range r.Patterns {
+		payee.Aliases = append(payee.Aliases, models.PayeeAlias{Name: pattern, IsPattern: true})
+	}
+	return payee
+}
+
+type MergePayeesRequest struct {
+	SourceIDs []int `json:"source_ids" binding:"required,min=1"`
+	TargetID  int   `json:"target_id" binding:"required"`
+}
+
+// DateRangeQuery parses optional from and to dates in YYYY-MM-DD form. A
+// missing from means the beginning of time and a missing to means today.
+// The returned end is exclusive.
+func DateRangeQuery(from string, to string) (time.Time, time.Time, error) {
+	start := time.Unix(0, 0)
+	end := time.Now()
+	var err error
+	if from != "" {
+		if start, err = time.Parse(time.DateOnly, from); err != nil {
+			return start, end, err
+		}
+	}
+	if to != "" {
+		if end, err = time.Parse(time.DateOnly, to); err != nil {
+			return start, end, err
+		}
+	}
+	return start, end.Truncate(24*time.Hour).AddDate(0, 0, 1), nil
+}
@@ -0,0 +1,134 @@
+package requests
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/database/scopes"
+	"github.com/christo-andrew/haven/pkg/recurrence"
+	"gorm.io/gorm"
+)
+
+// CreateOrUpdateRecurringTransactionRequest describes a recurring transaction.
+// Recurrence is an RRULE such as "FREQ=MONTHLY;BYMONTHDAY=1". EndDate and
+// Count are shorthands for UNTIL and COUNT in the rule.
+type CreateOrUpdateRecurringTransactionRequest struct {
+	Name            string  `json:"name" binding:"required"`
+	AccountID       int     `json:"account_id" binding:"required"`
+	Amount          float64 `json:"amount" binding:"required"`
+	Currency        string  `json:"currency"`
+	Description     string  `json:"description"`
+	Payee           string  `json:"payee"`
+	Category        string  `json:"category"`
+	TransactionType string  `json:"transaction_type"`
+	Recurrence      string  `json:"recurrence" binding:"required"`
+	StartDate       string  `json:"start_date" binding:"required"`
+	EndDate         string  `json:"end_date"`
+	Count           int     `json:"count"`
+	AutoPost        bool    `json:"auto_post"`
+	Active          *bool   `json:"active"`
+}
+
+func (r *CreateOrUpdateRecurringTransactionRequest) Rule() (recurrence.Rule, error) {
+	rule, err := recurrence.Parse(r.Recurrence)
+	if err != nil {
+		return rule, err
+	}
+	if r.EndDate != "" {
+		until, err := time.Parse(time.DateOnly, r.EndDate)
+		if err != nil {
+			return rule, fmt.Errorf("invalid end date %q", r.EndDate)
+		}
+		rule.Until = until
+	}
+	if r.Count < 0 {
+		return rule, fmt.Errorf("count cannot be negative")
+	}
+	if r.Count > 0 {
+		rule.Count = r.Count
+	}
+	return rule, nil
+}
+
+func (r *CreateOrUpdateRecurringTransactionRequest) GetStartDate() (time.Time, error) {
+	startDate, err := time.Parse(time.DateOnly, r.StartDate)
+	if err != nil {
+		return startDate, fmt.Errorf("invalid start date %q", r.StartDate)
+	}
+	return startDate, nil
+}
+
+func (r *CreateOrUpdateRecurringTransactionRequest) Validate() error {
+	if _, err := r.Rule(); err != nil {
+		return err
+	}
+	_, err := r.GetStartDate()
+	return err
+}
+
+// RecurringTransaction builds the template. NextDate is the first occurrence
+// of the rule, callers updating a template that has already posted should move
+// it past the occurrences already posted.
+func (r *CreateOrUpdateRecurringTransactionRequest) RecurringTransaction(userId uint, db *gorm.DB) (*models.RecurringTransaction, error) {
+	rule, err := r.Rule()
+	if err != nil {
+		return nil, err
+	}
+	startDate, err := r.GetStartDate()
+	if err != nil {
+		return nil, err
+	}
+	categoryName := r.Category
+	if categoryName == "" {
+		categoryName = models.DefaultCategoryName
+	}
+	typeName := r.TransactionType
+	if typeName == "" {
+		typeName = "Unknown"
+	}
+	category := scopes.GetOrCreateTransactionCategory(categoryName, userId, db)
+	transactionType := scopes.GetOrCreateTransactionType(typeName, db)
+	active := true
+	if r.Active != nil {
+		active = *r.Active
+	}
+
+	recurring := &models.RecurringTransaction{
+		Name:              r.Name,
+		AccountID:         r.AccountID,
+		Amount:            r.Amount,
+		Currency:          r.Currency,
+		Description:       r.Description,
+		Payee:             r.Payee,
+		CategoryID:        category.ID,
+		Category:          *category,
+		TransactionTypeID: transactionType.ID,
+		TransactionType:   *transactionType,
+		Recurrence:        rule.String(),
+		StartDate:         startDate,
+		AutoPost:          r.AutoPost,
+		Active:            active,
+	}
+	if first, ok := rule.First(startDate); ok {
+		recurring.NextDate = &first
+	} else {
+		recurring.Active = false
+	}
+	return recurring, nil
+}
+
+// ConfirmTransactionRequest optionally corrects a pending transaction before
+// it is confirmed, bills often differ slightly from the amount scheduled.
+type ConfirmTransactionRequest struct {
+	Amount *float64 `json:"amount"`
+	Date   string   `json:"date"`
+}
+
+// ConfirmSubscriptionRequest turns a detected subscription into a recurring
+// transaction. Name defaults to the detected name.
+type ConfirmSubscriptionRequest struct {
+	Key      string `json:"key" binding:"required"`
+	Name     string `json:"name"`
+	AutoPost bool   `json:"auto_post"`
+}
@@ -0,0 +1,126 @@
+package requests
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/database/scopes"
+	"github.com/christo-andrew/haven/pkg/rules"
+	"gorm.io/gorm"
+)
+
+type RuleConditionRequest struct {
+	Field    string `json:"field" binding:"required,oneof=description payee amount account direction"`
+	Operator string `json:"operator" binding:"required"`
+	Value    string `json:"value" binding:"required"`
+	ValueTo  string `json:"value_to"`
+}
+
+// RuleActionRequest describes what a rule does. For set_category, set_type and
+// add_tag the value is the name of the category, type or tag, which is created
+// if it does not exist yet.
+type RuleActionRequest struct {
+	Type  string `json:"type" binding:"required,oneof=set_category set_type set_payee add_tag mark_transfer"`
+	Value string `json:"value"`
+}
+
+type CreateOrUpdateRuleRequest struct {
+	Name           string                 `json:"name" binding:"required"`
+	Priority       int                    `json:"priority"`
+	Enabled        *bool                  `json:"enabled"`
+	StopProcessing bool                   `json:"stop_processing"`
+	Conditions     []RuleConditionRequest `json:"conditions" binding:"required,min=1,dive"`
+	Actions        []RuleActionRequest    `json:"actions" binding:"required,min=1,dive"`
+}
+
+func (r *CreateOrUpdateRuleRequest) Validate() error {
+	for _, condition := range r.Conditions {
+		if err := rules.ValidateCondition(condition.Condition()); err != nil {
+			return err
+		}
+	}
+	for _, action := range r.Actions {
+		if action.Type != models.RuleActionMarkTransfer && action.Value == "" {
+			return fmt.Errorf("action %s requires a value", action.Type)
+		}
+	}
+	return nil
+}
+
+func (r *CreateOrUpdateRuleRequest) Rule(userId uint, db *gorm.DB) (*models.Rule, error) {
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+	rule := &models.Rule{
+		Name:           r.Name,
+		Priority:       r.Priority,
+		Enabled:        enabled,
+		StopProcessing: r.StopProcessing,
+	}
+	for _, condition := range r.Conditions {
+		rule.Conditions = append(rule.Conditions, condition.Condition())
+	}
+	for _, action := range r.Actions {
+		ruleAction, err := action.Action(userId, db)
+		if err != nil {
+			return nil, err
+		}
+		rule.Actions = append(rule.Actions, ruleAction)
+	}
+	return rule, nil
+}
+
+func (c RuleConditionRequest) Condition() models.RuleCondition {
+	return models.RuleCondition{
+		Field:    c.Field,
+		Operator: c.Operator,
+		Value:    c.Value,
+		ValueTo:  c.ValueTo,
+	}
+}
+
+func (a RuleActionRequest) Action(userId uint, db *gorm.DB) (models.RuleAction, error) {
+	value := a.Value
+	switch a.Type {
+	case models.RuleActionSetCategory:
+		value = strconv.Itoa(scopes.GetOrCreateTransactionCategory(a.Value, userId, db).ID)
+	case models.RuleActionSetType:
+		value = strconv.Itoa(scopes.GetOrCreateTransactionType(a.Value, db).ID)
+	case models.RuleActionAddTag:
+		tag, err := scopes.GetOrCreateTransactionTag(a.Value, userId, db)
+		if err != nil {
+			return models.RuleAction{}, err
+		}
+		value = strconv.Itoa(tag.ID)
+	}
+	return models.RuleAction{Type: a.Type, Value: value}, nil
+}
+
+// RunRulesRequest selects the transactions rules are re-run against. With
+// Preview set nothing is saved and the response lists what would change.
+type RunRulesRequest struct {
+	RuleIDs   []int  `json:"rule_ids"`
+	AccountID int    `json:"account_id"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Preview   bool   `json:"preview"`
+}
+
+func (r *RunRulesRequest) DateRange() (time.Time, time.Time, error) {
+	var from, to time.Time
+	var err error
+	if r.From != "" {
+		if from, err = time.Parse(time.DateOnly, r.From); err != nil {
+			return from, to, err
+		}
+	}
+	if r.To != "" {
+		if to, err = time.Parse(time.DateOnly, r.To); err != nil {
+			return from, to, err
+		}
+	}
+	return from, to, nil
+}
@@ -1,5 +1,26 @@
 package requests
 
+import "strings"
+
 type CreateTagRequest struct {
-	Name string `json:"name"`
+	Name string `json:"name" binding:"required,max=255"`
+}
+
+func (r CreateTagRequest) GetName() string {
+	return strings.TrimSpace(r.Name)
+}
+
+// RenameRequest renames a category or a tag. Surrounding whitespace is
+// dropped from the name.
+type RenameRequest struct {
+	Name string `json:"name" binding:"required,max=255"`
+}
+
+func (r RenameRequest) GetName() string {
+	return strings.TrimSpace(r.Name)
+}
+
+type MergeTagsRequest struct {
+	SourceIDs []int `json:"source_ids" binding:"required,min=1"`
+	TargetID  int   `json:"target_id" binding:"required"`
 }
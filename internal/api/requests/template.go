@@ -0,0 +1,152 @@
+package requests
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/budgets"
+	"github.com/christo-andrew/haven/pkg/errors"
+	"github.com/christo-andrew/haven/pkg/utils"
+)
+
+// BudgetTemplateRequest saves a template to start budgets from. Each
+// category has either a fixed amount or a percentage of the income a budget
+// is drafted for.
+type BudgetTemplateRequest struct {
+	Name        string                      `json:"name" binding:"required"`
+	Description string                      `json:"description"`
+	Period      string                      `json:"period" binding:"omitempty,oneof=weekly monthly quarterly yearly" enums:"weekly,monthly,quarterly,yearly"`
+	Rollover    string                      `json:"rollover" binding:"omitempty,oneof=none unspent overspent both" enums:"none,unspent,overspent,both"`
+	Categories  []BudgetTemplateLineRequest `json:"categories" binding:"required,min=1,dive"`
+}
+
+type BudgetTemplateLineRequest struct {
+	CategoryID int     `json:"category_id" binding:"required"`
+	Amount     float64 `json:"amount" binding:"gte=0"`
+	Percentage float64 `json:"percentage" binding:"gte=0,lte=100"`
+}
+
+func (budgetTemplateRequest *BudgetTemplateRequest) Validate() error {
+	seen := make(map[int]bool)
+	percentage := 0.0
+	for _, line := range budgetTemplateRequest.Categories {
+		if (line.Amount > 0) == (line.Percentage > 0) {
+			return errors.BudgetTemplateLineAmountError()
+		}
+		if seen[line.CategoryID] {
+			return errors.DuplicateBudgetCategoryError()
+		}
+		seen[line.CategoryID] = true
+		percentage += line.Percentage
+	}
+	if percentage > 100 {
+		return errors.BudgetTemplatePercentageError()
+	}
+	return nil
+}
+
+func (budgetTemplateRequest *BudgetTemplateRequest) BudgetTemplate(userId uint) *models.BudgetTemplate {
+	template := &models.BudgetTemplate{
+		UserID:      userId,
+		Name:        budgetTemplateRequest.Name,
+		Description: budgetTemplateRequest.Description,
+		Period:      budgetTemplateRequest.Period,
+		Rollover:    budgetTemplateRequest.Rollover,
+	}
+	if template.Period == "" {
+		template.Period = models.BudgetPeriodMonthly
+	}
+	if template.Rollover == "" {
+		template.Rollover = models.BudgetRolloverNone
+	}
+	for _, line := range budgetTemplateRequest.Categories {
+		template.Lines = append(template.Lines, models.BudgetTemplateLine{
+			CategoryID: line.CategoryID,
+			Amount:     line.Amount,
+			Percentage: line.Percentage,
+		})
+	}
+	return template
+}
+
+// BudgetDraftRequest drafts a budget from a template. Income is what the
+// percentages of the template are taken of. The 50/30/20 preset needs the
+// categories of each of its groups, needs, wants and savings.
+type BudgetDraftRequest struct {
+	Name      string           `json:"name"`
+	StartDate string           `json:"start_date" example:"2024-01-01"`
+	Income    float64          `json:"income" binding:"gte=0"`
+	Groups    map[string][]int `json:"groups"`
+}
+
+// GetStartDate returns the first day of the budget, the first of this month
+// unless given.
+func (budgetDraftRequest *BudgetDraftRequest) GetStartDate() (time.Time, error) {
+	if budgetDraftRequest.StartDate == "" {
+		now := time.Now().UTC()
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC), nil
+	}
+	return time.Parse(time.DateOnly, budgetDraftRequest.StartDate)
+}
+
+// ValidateGroups checks that the groups are those of the preset.
+func (budgetDraftRequest *BudgetDraftRequest) ValidateGroups(preset budgets.Preset) error {
+	for name := range budgetDraftRequest.Groups {
+		known := false
+		for _, group := range preset.Groups {
+			known = known || group.Name == name
+		}
+		if !known {
+			return errors.UnknownPresetGroupError(name)
+		}
+	}
+	return nil
+}
+
+// SuggestBudgetQuery parses how many months back a suggested budget looks
+// and how it derives limits from them: 3 months and the median unless given.
+func SuggestBudgetQuery(months string, method string) (int, string, error) {
+	count := 3
+	if months != "" {
+		var err error
+		if count, err = strconv.Atoi(months); err != nil || count < 1 || count > 24 {
+			return count, method, errors.InvalidSuggestionMonthsError()
+		}
+	}
+	switch method {
+	case "":
+		method = budgets.SuggestMedian
+	case budgets.SuggestAverage, budgets.SuggestMedian:
+	default:
+		return count, method, errors.InvalidSuggestionMethodError(method)
+	}
+	return count, method, nil
+}
+
+// NewBudgetDraft drafts a request creating a recurring budget with lines,
+// leaving out the lines with nothing to spend. The draft can be changed and
+// then sent to create the budget.
+func NewBudgetDraft(name string, startDate time.Time, period string, rollover string, lines []models.BudgetCategory) (CreateOrUpdateBudgetRequest, error) {
+	recurring := true
+	draft := CreateOrUpdateBudgetRequest{
+		Name:       name,
+		StartDate:  startDate.Format(time.DateOnly),
+		Categories: []BudgetCategoryRequest{},
+		Recurring:  &recurring,
+		Period:     period,
+		Rollover:   rollover,
+	}
+	for _, line := range lines {
+		if line.Amount > 0 {
+			draft.Categories = append(draft.Categories, BudgetCategoryRequest{CategoryID: line.CategoryID, Amount: line.Amount})
+			draft.Amount += line.Amount
+		}
+	}
+	if len(draft.Categories) == 0 {
+		return draft, errors.EmptyBudgetDraftError()
+	}
+	draft.Amount = utils.RoundToCents(draft.Amount)
+	draft.EndDate = budgets.PeriodEnd(period, 0, startDate, 0).Format(time.DateOnly)
+	return draft, nil
+}
@@ -6,25 +6,62 @@ import (
 
 	"github.com/christo-andrew/haven/internal/models"
 	"github.com/christo-andrew/haven/pkg/database/scopes"
+	"github.com/christo-andrew/haven/pkg/errors"
+	"github.com/christo-andrew/haven/pkg/utils"
 	"gorm.io/gorm"
 )
 
 type CreateTransactionRequest struct {
-	AccountID         int     `json:"account_id"`
-	Amount            float64 `json:"amount"`
-	Currency          string  `json:"currency"`
-	Date              string  `json:"date"`
-	Description       string  `json:"description"`
-	CategoryID        string  `json:"category_id"`
-	TransactionTypeID string  `json:"transaction_type_id"`
-	TransactionType   string  `json:"transaction_type"`
-	Category          string  `json:"category"`
-	DateFormat        string  `json:"date_format"`
-}
-
-func (c *CreateTransactionRequest) Transaction(db *gorm.DB) *models.Transaction {
-	category := c.GetCategory(db)
+	AccountID         int                       `json:"account_id"`
+	Amount            float64                   `json:"amount"`
+	Currency          string                    `json:"currency"`
+	Date              string                    `json:"date"`
+	Description       string                    `json:"description"`
+	Notes             string                    `json:"notes"`
+	Status            string                    `json:"status" enums:"pending,cleared"`
+	Payee             string                    `json:"payee"`
+	CategoryID        string                    `json:"category_id"`
+	TransactionTypeID string                    `json:"transaction_type_id"`
+	TransactionType   string                    `json:"transaction_type"`
+	Category          string                    `json:"category"`
+	DateFormat        string                    `json:"date_format"`
+	Splits            []TransactionSplitRequest `json:"splits"`
+}
+
+type TransactionSplitRequest struct {
+	Amount   float64  `json:"amount" binding:"required"`
+	Category string   `json:"category"`
+	Memo     string   `json:"memo"`
+	Tags     []string `json:"tags"`
+}
+
+type UpdateTransactionCategoryRequest struct {
+	Category string `json:"category" binding:"required"`
+}
+
+func (r *UpdateTransactionCategoryRequest) GetCategory(userId uint, db *gorm.DB) *models.Category {
+	return scopes.GetOrCreateTransactionCategory(r.Category, userId, db)
+}
+
+type UpdateTransactionStatusRequest struct {
+	Status string `json:"status" binding:"required" enums:"pending,cleared,reconciled,void"`
+}
+
+type UpdateTransactionNotesRequest struct {
+	Notes string `json:"notes" binding:"max=10000"`
+}
+
+type UpdateTransactionSplitsRequest struct {
+	Splits []TransactionSplitRequest `json:"splits"`
+}
+
+func (c *CreateTransactionRequest) Transaction(userId uint, db *gorm.DB) (*models.Transaction, error) {
+	category := c.GetCategory(userId, db)
 	transactionType := c.GetTransactionType(db)
+	splits, err := TransactionSplits(c.Splits, userId, db)
+	if err != nil {
+		return nil, err
+	}
 
 	return &models.Transaction{
 		AccountID:         c.AccountID,
@@ -32,17 +69,85 @@ func (c *CreateTransactionRequest) Transaction(db *gorm.DB) *models.Transaction
 		Currency:          c.Currency,
 		Date:              c.FormatDate(),
 		Description:       c.Description,
+		Notes:             c.Notes,
+		TransactionStatus: c.Status,
+		Payee:             c.Payee,
 		CategoryID:        category.ID,
 		TransactionTypeID: transactionType.ID,
+		Splits:            splits,
+	}, nil
+}
+
+// Validate checks the splits and the status. New transactions are either
+// pending or cleared, defaulting to cleared.
+func (c *CreateTransactionRequest) Validate() error {
+	if c.Status != "" && c.Status != models.TransactionStatusPending && c.Status != models.TransactionStatusCleared {
+		return errors.InvalidTransactionStatusError(c.Status)
+	}
+	return ValidateTransactionSplits(c.Amount, c.Splits)
+}
+
+func (s *TransactionSplitRequest) TransactionSplit(userId uint, db *gorm.DB) (models.TransactionSplit, error) {
+	categoryName := s.Category
+	if categoryName == "" {
+		categoryName = models.DefaultCategoryName
+	}
+	category := scopes.GetOrCreateTransactionCategory(categoryName, userId, db)
+	var tags []models.Tag
+	for _, name := range s.Tags {
+		tag, err := scopes.GetOrCreateTransactionTag(name, userId, db)
+		if err != nil {
+			return models.TransactionSplit{}, err
+		}
+		tags = append(tags, *tag)
 	}
 
+	return models.TransactionSplit{
+		Amount:     s.Amount,
+		CategoryID: category.ID,
+		Category:   *category,
+		Memo:       s.Memo,
+		Tags:       tags,
+	}, nil
+}
+
+func TransactionSplits(splitRequests []TransactionSplitRequest, userId uint, db *gorm.DB) ([]models.TransactionSplit, error) {
+	var splits []models.TransactionSplit
+	for _, splitRequest := range splitRequests {
+		split, err := splitRequest.TransactionSplit(userId, db)
+		if err != nil {
+			return nil, err
+		}
+		splits = append(splits, split)
+	}
+	return splits, nil
+}
+
+// ValidateTransactionSplits checks that every split line has an amount and that
+// the lines add up to the amount of the parent transaction. An empty list is
+// valid and means the transaction is not split.
+func ValidateTransactionSplits(amount float64, splits []TransactionSplitRequest) error {
+	if len(splits) == 0 {
+		return nil
+	}
+	var total float64
+	for _, split := range splits {
+		if utils.RoundToCents(split.Amount) == 0 {
+			return errors.InvalidSplitAmountError()
+		}
+		total += split.Amount
+	}
+	if utils.RoundToCents(total) != utils.RoundToCents(amount) {
+		return errors.SplitAmountMismatchError(amount, total)
+	}
+	return nil
 }
 
-func (c *CreateTransactionRequest) GetCategory(db *gorm.DB) *models.Category {
+func (c *CreateTransactionRequest) GetCategory(userId uint, db *gorm.DB) *models.Category {
 	if c.Category == "" {
-		return scopes.GetOrCreateTransactionCategory("General", db)
+		return scopes.GetOrCreateTransactionCategory(models.DefaultCategoryName, userId, db)
 	}
-	return scopes.GetOrCreateTransactionCategory(c.Category, db)
+	return scopes.GetOrCreateTransactionCategory(c.Category, userId, db)
 }
 
 func (c *CreateTransactionRequest) GetTransactionType(db *gorm.DB) *models.Category {
@@ -1,7 +1,13 @@
 package responses
 
 import (
+	"github.com/christo-andrew/haven/internal/api/requests"
 	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/budgets"
+	"github.com/christo-andrew/haven/pkg/debts"
+	"github.com/christo-andrew/haven/pkg/rules"
+	"github.com/christo-andrew/haven/pkg/utils"
+	"time"
 )
 
 type ErrorResponse struct {
@@ -32,12 +38,14 @@ type LoginResponse struct {
 }
 
 type AccountResponse struct {
-	ID          int     `json:"id"`
-	AccountName string  `json:"name"`
-	Currency    string  `json:"currency"`
-	Balance     float64 `json:"balance"`
-	AccountType string  `json:"account_type"`
-	Category    string  `json:"category"`
+	ID             int     `json:"id"`
+	AccountName    string  `json:"name"`
+	Currency       string  `json:"currency"`
+	Balance        float64 `json:"balance"`
+	AccountType    string  `json:"account_type"`
+	Category       string  `json:"category"`
+	InterestRate   float64 `json:"interest_rate"`
+	MinimumPayment float64 `json:"minimum_payment"`
 }
 
 type CategoryResponse struct {
@@ -45,20 +53,57 @@ type CategoryResponse struct {
 	Name        string `json:"name"`
 	Context     string `json:"context"`
 	ContextType string `json:"context_type"`
+	ParentID    *int   `json:"parent_id"`
+	IsSystem    bool   `json:"is_system"`
+	Color       string `json:"color"`
+	Icon        string `json:"icon"`
+	Hidden      bool   `json:"hidden"`
+}
+
+// CategoryTreeResponse is a category with the categories below it.
+type CategoryTreeResponse struct {
+	ID       int                     `json:"id"`
+	Name     string                  `json:"name"`
+	ParentID *int                    `json:"parent_id"`
+	Path     string                  `json:"path"`
+	IsSystem bool                    `json:"is_system"`
+	Color    string                  `json:"color"`
+	Icon     string                  `json:"icon"`
+	Hidden   bool                    `json:"hidden"`
+	Children []*CategoryTreeResponse `json:"children"`
 }
 
 type TransactionResponse struct {
-	TransactionID     int     `json:"id"`
-	Amount            float64 `json:"amount"`
-	Currency          string  `json:"currency"`
-	Date              int64   `json:"date"`
-	Description       string  `json:"description"`
-	AccountID         int     `json:"account_id"`
-	TransactionType   string  `json:"transaction_type"`
-	Category          string  `json:"category"`
-	TransactionStatus string  `json:"transaction_status"`
-	Reference         string  `json:"reference"`
-	Payee             string  `json:"payee"`
+	TransactionID     int                        `json:"id"`
+	Amount            float64                    `json:"amount"`
+	Currency          string                     `json:"currency"`
+	Date              int64                      `json:"date"`
+	Description       string                     `json:"description"`
+	Notes             string                     `json:"notes"`
+	AccountID         int                        `json:"account_id"`
+	TransactionType   string                     `json:"transaction_type"`
+	Category          string                     `json:"category"`
+	TransactionStatus string                     `json:"transaction_status"`
+	Reference         string                     `json:"reference"`
+	Payee             string                     `json:"payee"`
+	PayeeID           *int                       `json:"payee_id"`
+	RecurringID       *int                       `json:"recurring_id,omitempty"`
+	Splits            []TransactionSplitResponse `json:"splits,omitempty"`
+}
+
+type TransactionSplitResponse struct {
+	ID         int           `json:"id"`
+	Amount     float64       `json:"amount"`
+	CategoryID int           `json:"category_id"`
+	Category   string        `json:"category"`
+	Memo       string        `json:"memo"`
+	Tags       []TagResponse `json:"tags,omitempty"`
+}
+
+type CategorySuggestionResponse struct {
+	CategoryID int     `json:"category_id"`
+	Category   string  `json:"category"`
+	Confidence float64 `json:"confidence"`
 }
 
 type TagResponse struct {
@@ -66,6 +111,19 @@ type TagResponse struct {
 	Name string `json:"name"`
 }
 
+// TagUsageResponse is a tag with how many of the current user's records
+// carry it.
+type TagUsageResponse struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	Transactions int    `json:"transactions"`
+	Splits       int    `json:"splits"`
+	Accounts     int    `json:"accounts"`
+	Budgets      int    `json:"budgets"`
+	Rules        int    `json:"rules"`
+	Total        int    `json:"total"`
+}
+
 func (tagResponse TagResponse) FromTag(tag models.Tag) TagResponse {
 	tagResponse.ID = tag.ID
 	tagResponse.Name = tag.Name
@@ -88,13 +146,126 @@ type TransactionSchema struct {
 }
 
 type BudgetResponse struct {
-	Id          int     `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Amount      float64 `json:"amount"`
-	StartDate   string  `json:"start_date"`
-	EndDate     string  `json:"end_date"`
-	Category    string  `json:"category"`
+	Id                 int     `json:"id"`
+	Name               string  `json:"name"`
+	Description        string  `json:"description"`
+	Amount             float64 `json:"amount"`
+	StartDate          string  `json:"start_date"`
+	EndDate            string  `json:"end_date"`
+	Category           string  `json:"category"`
+	SpentAmount        float64 `json:"spent_amount"`
+	RemainingAmount    float64 `json:"remaining_amount"`
+	ProgressPercentage float64 `json:"progress_percentage"`
+	DailyTarget        float64 `json:"daily_target"`
+	DaysRemaining      int     `json:"days_remaining"`
+	IsOverBudget       bool    `json:"is_over_budget"`
+	Recurring          bool    `json:"recurring"`
+	Period             string  `json:"period"`
+	PeriodDays         int     `json:"period_days"`
+	Rollover           string  `json:"rollover"`
+	Carryover          float64 `json:"carryover"`
+	Limit              float64 `json:"limit"`
+
+	Categories []*BudgetCategoryResponse `json:"categories"`
+	Forecast   *BudgetForecastResponse   `json:"forecast,omitempty"`
+}
+
+// BudgetForecastResponse is where a budget is heading by its end date.
+// OverspendDate is when the limit is likely to be reached, left out when it
+// is not expected to be.
+type BudgetForecastResponse struct {
+	Basis                     string  `json:"basis"`
+	RunRate                   float64 `json:"run_rate"`
+	ProjectedSpend            float64 `json:"projected_spend"`
+	ProjectedVariance         float64 `json:"projected_variance"`
+	WillExceed                bool    `json:"will_exceed"`
+	OverspendDate             string  `json:"overspend_date,omitempty"`
+	RecommendedDailyAllowance float64 `json:"recommended_daily_allowance"`
+}
+
+func (budgetForecastResponse BudgetForecastResponse) FromForecast(forecast budgets.Forecast) *BudgetForecastResponse {
+	budgetForecastResponse.Basis = forecast.Basis
+	budgetForecastResponse.RunRate = forecast.RunRate
+	budgetForecastResponse.ProjectedSpend = forecast.ProjectedSpend
+	budgetForecastResponse.ProjectedVariance = forecast.ProjectedVariance
+	budgetForecastResponse.WillExceed = forecast.WillExceed
+	if forecast.OverspendDate != nil {
+		budgetForecastResponse.OverspendDate = forecast.OverspendDate.Format("2006-01-02")
+	}
+	budgetForecastResponse.RecommendedDailyAllowance = forecast.RecommendedDailyAllowance
+	return &budgetForecastResponse
+}
+
+// BudgetForecastSummaryResponse lists the forecasts of the budgets running
+// today, those heading over their limit first.
+type BudgetForecastSummaryResponse struct {
+	AtRisk  int                           `json:"at_risk"`
+	Budgets []BudgetForecastEntryResponse `json:"budgets"`
+}
+
+type BudgetForecastEntryResponse struct {
+	BudgetID      uint                   `json:"budget_id"`
+	Name          string                 `json:"name"`
+	EndDate       string                 `json:"end_date"`
+	Limit         float64                `json:"limit"`
+	SpentAmount   float64                `json:"spent_amount"`
+	DaysRemaining int                    `json:"days_remaining"`
+	Forecast      BudgetForecastResponse `json:"forecast"`
+	Warning       string                 `json:"warning,omitempty"`
+}
+
+// BudgetPeriodResponse is one period of a recurring budget. Limit is the
+// amount plus whatever was carried over from the period before.
+type BudgetPeriodResponse struct {
+	ID              uint    `json:"id"`
+	StartDate       string  `json:"start_date"`
+	EndDate         string  `json:"end_date"`
+	Amount          float64 `json:"amount"`
+	Carryover       float64 `json:"carryover"`
+	Limit           float64 `json:"limit"`
+	SpentAmount     float64 `json:"spent_amount"`
+	RemainingAmount float64 `json:"remaining_amount"`
+	Closed          bool    `json:"closed"`
+}
+
+func (budgetPeriodResponse BudgetPeriodResponse) FromBudgetPeriod(period models.BudgetPeriod) *BudgetPeriodResponse {
+	budgetPeriodResponse.ID = period.ID
+	budgetPeriodResponse.StartDate = period.StartDate.Format("2006-01-02")
+	budgetPeriodResponse.EndDate = period.EndDate.Format("2006-01-02")
+	budgetPeriodResponse.Amount = period.Amount
+	budgetPeriodResponse.Carryover = period.Carryover
+	budgetPeriodResponse.Limit = utils.RoundToCents(period.Limit())
+	budgetPeriodResponse.SpentAmount = period.SpentAmount
+	budgetPeriodResponse.RemainingAmount = utils.RoundToCents(period.Limit() - period.SpentAmount)
+	budgetPeriodResponse.Closed = period.Closed
+	return &budgetPeriodResponse
+}
+
+type BudgetCategoryResponse struct {
+	ID                 uint    `json:"id"`
+	CategoryID         int     `json:"category_id"`
+	Category           string  `json:"category"`
+	Amount             float64 `json:"amount"`
+	SpentAmount        float64 `json:"spent_amount"`
+	RemainingAmount    float64 `json:"remaining_amount"`
+	ProgressPercentage float64 `json:"progress_percentage"`
+	IsOverBudget       bool    `json:"is_over_budget"`
+	GoalID             *uint   `json:"goal_id"`
+}
+
+func (budgetCategoryResponse BudgetCategoryResponse) FromBudgetCategory(line models.BudgetCategory) *BudgetCategoryResponse {
+	budgetCategoryResponse.ID = line.ID
+	budgetCategoryResponse.CategoryID = line.CategoryID
+	if line.Category != nil {
+		budgetCategoryResponse.Category = line.Category.Name
+	}
+	budgetCategoryResponse.Amount = line.Amount
+	budgetCategoryResponse.SpentAmount = line.SpentAmount
+	budgetCategoryResponse.RemainingAmount = utils.RoundToCents(line.RemainingAmount())
+	budgetCategoryResponse.ProgressPercentage = utils.RoundToCents(line.ProgressPercentage())
+	budgetCategoryResponse.IsOverBudget = line.IsOverBudget()
+	budgetCategoryResponse.GoalID = line.GoalID
+	return &budgetCategoryResponse
 }
 
 func (budgetResponse BudgetResponse) FromBudget(budget models.Budget) *BudgetResponse {
@@ -105,21 +276,486 @@ func (budgetResponse BudgetResponse) FromBudget(budget models.Budget) *BudgetRes
 	budgetResponse.StartDate = budget.StartDate.Format("2006-01-02")
 	budgetResponse.EndDate = budget.EndDate.Format("2006-01-02")
 	budgetResponse.Category = budget.Category.Name
+	budgetResponse.SpentAmount = budget.SpentAmount
+	budgetResponse.RemainingAmount = utils.RoundToCents(budget.RemainingAmount())
+	budgetResponse.ProgressPercentage = utils.RoundToCents(budget.ProgressPercentage())
+	budgetResponse.DailyTarget = utils.RoundToCents(budget.DailyTarget())
+	budgetResponse.DaysRemaining = budget.DaysRemaining()
+	budgetResponse.IsOverBudget = budget.IsOverBudget()
+	budgetResponse.Recurring = budget.Recurring
+	budgetResponse.Period = budget.Period
+	budgetResponse.PeriodDays = budget.PeriodDays
+	budgetResponse.Rollover = budget.Rollover
+	budgetResponse.Carryover = budget.Carryover
+	budgetResponse.Limit = utils.RoundToCents(budget.Limit())
+	budgetResponse.Categories = []*BudgetCategoryResponse{}
+	for _, line := range budget.Categories {
+		budgetResponse.Categories = append(budgetResponse.Categories, BudgetCategoryResponse{}.FromBudgetCategory(line))
+	}
 	return &budgetResponse
 }
 
+// BudgetTemplatesResponse lists the built-in presets and the templates the
+// user saved.
+type BudgetTemplatesResponse struct {
+	Presets   []BudgetPresetResponse    `json:"presets"`
+	Templates []*BudgetTemplateResponse `json:"templates"`
+}
+
+type BudgetPresetResponse struct {
+	Key         string                      `json:"key"`
+	Name        string                      `json:"name"`
+	Description string                      `json:"description"`
+	Groups      []BudgetPresetGroupResponse `json:"groups"`
+}
+
+type BudgetPresetGroupResponse struct {
+	Name       string  `json:"name"`
+	Percentage float64 `json:"percentage"`
+}
+
+func (budgetPresetResponse BudgetPresetResponse) FromPreset(preset budgets.Preset) BudgetPresetResponse {
+	budgetPresetResponse.Key = preset.Key
+	budgetPresetResponse.Name = preset.Name
+	budgetPresetResponse.Description = preset.Description
+	budgetPresetResponse.Groups = []BudgetPresetGroupResponse{}
+	for _, group := range preset.Groups {
+		budgetPresetResponse.Groups = append(budgetPresetResponse.Groups, BudgetPresetGroupResponse{Name: group.Name, Percentage: group.Percentage})
+	}
+	return budgetPresetResponse
+}
+
+type BudgetTemplateResponse struct {
+	ID          uint                         `json:"id"`
+	Name        string                       `json:"name"`
+	Description string                       `json:"description"`
+	Period      string                       `json:"period"`
+	Rollover    string                       `json:"rollover"`
+	Categories  []BudgetTemplateLineResponse `json:"categories"`
+}
+
+// BudgetTemplateLineResponse is a category of a template with either a fixed
+// amount or a percentage of income.
+type BudgetTemplateLineResponse struct {
+	CategoryID int     `json:"category_id"`
+	Amount     float64 `json:"amount"`
+	Percentage float64 `json:"percentage"`
+}
+
+func (budgetTemplateResponse BudgetTemplateResponse) FromBudgetTemplate(template models.BudgetTemplate) *BudgetTemplateResponse {
+	budgetTemplateResponse.ID = template.ID
+	budgetTemplateResponse.Name = template.Name
+	budgetTemplateResponse.Description = template.Description
+	budgetTemplateResponse.Period = template.Period
+	budgetTemplateResponse.Rollover = template.Rollover
+	budgetTemplateResponse.Categories = []BudgetTemplateLineResponse{}
+	for _, line := range template.Lines {
+		budgetTemplateResponse.Categories = append(budgetTemplateResponse.Categories, BudgetTemplateLineResponse{
+			CategoryID: line.CategoryID,
+			Amount:     line.Amount,
+			Percentage: line.Percentage,
+		})
+	}
+	return &budgetTemplateResponse
+}
+
+// BudgetSuggestionResponse suggests a limit for each category from what was
+// spent in it over the last months. Draft is a budget with the suggested
+// limits that can be changed and then sent to create the budget.
+type BudgetSuggestionResponse struct {
+	Months     int                                   `json:"months"`
+	Method     string                                `json:"method"`
+	Categories []BudgetSuggestionLineResponse        `json:"categories"`
+	Draft      *requests.CreateOrUpdateBudgetRequest `json:"draft,omitempty"`
+}
+
+type BudgetSuggestionLineResponse struct {
+	CategoryID int     `json:"category_id"`
+	Category   string  `json:"category"`
+	Average    float64 `json:"average"`
+	Median     float64 `json:"median"`
+	Amount     float64 `json:"amount"`
+	Months     int     `json:"months"`
+}
+
+func (budgetSuggestionLineResponse BudgetSuggestionLineResponse) FromSuggestion(suggestion budgets.Suggestion) BudgetSuggestionLineResponse {
+	budgetSuggestionLineResponse.CategoryID = suggestion.CategoryID
+	budgetSuggestionLineResponse.Category = suggestion.Category
+	budgetSuggestionLineResponse.Average = suggestion.Average
+	budgetSuggestionLineResponse.Median = suggestion.Median
+	budgetSuggestionLineResponse.Amount = suggestion.Amount
+	budgetSuggestionLineResponse.Months = suggestion.Months
+	return budgetSuggestionLineResponse
+}
+
+// GoalResponse is a goal with how far it has come. Progress saved is made of
+// what is in the linked accounts, the tagged contributions and what the
+// linked budget lines left unspent.
+type GoalResponse struct {
+	ID           uint                     `json:"id"`
+	Name         string                   `json:"name"`
+	Description  string                   `json:"description"`
+	TargetAmount float64                  `json:"target_amount"`
+	StartDate    string                   `json:"start_date"`
+	TargetDate   string                   `json:"target_date"`
+	TagID        *int                     `json:"tag_id"`
+	AccountIDs   []int                    `json:"account_ids"`
+	BudgetLines  []GoalBudgetLineResponse `json:"budget_lines"`
+	Progress     GoalProgressResponse     `json:"progress"`
+}
+
+type GoalBudgetLineResponse struct {
+	BudgetID   int    `json:"budget_id"`
+	LineID     uint   `json:"line_id"`
+	CategoryID int    `json:"category_id"`
+	Category   string `json:"category"`
+}
+
+type GoalProgressResponse struct {
+	Saved             float64 `json:"saved"`
+	FromAccounts      float64 `json:"from_accounts"`
+	FromContributions float64 `json:"from_contributions"`
+	FromBudgets       float64 `json:"from_budgets"`
+	Remaining         float64 `json:"remaining"`
+	Percentage        float64 `json:"percentage"`
+	Expected          float64 `json:"expected"`
+	MonthsRemaining   int     `json:"months_remaining"`
+	RequiredMonthly   float64 `json:"required_monthly"`
+	Status            string  `json:"status" enums:"achieved,on_track,behind,missed"`
+}
+
+func (goalResponse GoalResponse) FromGoal(goal models.Goal, lines []models.BudgetCategory, progress budgets.GoalProgress) *GoalResponse {
+	goalResponse.ID = goal.ID
+	goalResponse.Name = goal.Name
+	goalResponse.Description = goal.Description
+	goalResponse.TargetAmount = goal.TargetAmount
+	goalResponse.StartDate = goal.StartDate.Format("2006-01-02")
+	goalResponse.TargetDate = goal.TargetDate.Format("2006-01-02")
+	goalResponse.TagID = goal.TagID
+	goalResponse.AccountIDs = []int{}
+	for _, link := range goal.Accounts {
+		goalResponse.AccountIDs = append(goalResponse.AccountIDs, link.AccountID)
+	}
+	goalResponse.BudgetLines = []GoalBudgetLineResponse{}
+	for _, line := range lines {
+		budgetLine := GoalBudgetLineResponse{BudgetID: line.BudgetID, LineID: line.ID, CategoryID: line.CategoryID}
+		if line.Category != nil {
+			budgetLine.Category = line.Category.Name
+		}
+		goalResponse.BudgetLines = append(goalResponse.BudgetLines, budgetLine)
+	}
+	goalResponse.Progress = GoalProgressResponse{
+		Saved:             progress.Saved,
+		FromAccounts:      progress.FromAccounts,
+		FromContributions: progress.FromContributions,
+		FromBudgets:       progress.FromBudgets,
+		Remaining:         progress.Remaining,
+		Percentage:        progress.Percentage,
+		Expected:          progress.Expected,
+		MonthsRemaining:   progress.MonthsRemaining,
+		RequiredMonthly:   progress.RequiredMonthly,
+		Status:            progress.Status,
+	}
+	return &goalResponse
+}
+
+type EnvelopeResponse struct {
+	ID         uint    `json:"id"`
+	CategoryID int     `json:"category_id"`
+	Category   string  `json:"category"`
+	Cover      string  `json:"cover"`
+	Carried    float64 `json:"carried"`
+	Assigned   float64 `json:"assigned"`
+	Activity   float64 `json:"activity"`
+	Available  float64 `json:"available"`
+}
+
+func (envelopeResponse EnvelopeResponse) FromEnvelopeBalance(balance budgets.EnvelopeBalance) *EnvelopeResponse {
+	envelopeResponse.ID = balance.Envelope.ID
+	envelopeResponse.CategoryID = balance.Envelope.CategoryID
+	if balance.Envelope.Category != nil {
+		envelopeResponse.Category = balance.Envelope.Category.Name
+	}
+	envelopeResponse.Cover = balance.Envelope.Cover
+	envelopeResponse.Carried = balance.Carried
+	envelopeResponse.Assigned = balance.Assigned
+	envelopeResponse.Activity = balance.Activity
+	envelopeResponse.Available = balance.Available
+	return &envelopeResponse
+}
+
+// EnvelopeMonthResponse is a month of envelope budgeting. to_be_budgeted is
+// the money still waiting to be assigned; overspent is what last month's
+// overspent envelopes took out of it.
+type EnvelopeMonthResponse struct {
+	Month        string              `json:"month"`
+	Income       float64             `json:"income"`
+	Unbudgeted   float64             `json:"unbudgeted"`
+	Assigned     float64             `json:"assigned"`
+	Overspent    float64             `json:"overspent"`
+	ToBeBudgeted float64             `json:"to_be_budgeted"`
+	Envelopes    []*EnvelopeResponse `json:"envelopes"`
+}
+
+func (envelopeMonthResponse EnvelopeMonthResponse) FromEnvelopeMonth(month budgets.EnvelopeMonth) *EnvelopeMonthResponse {
+	envelopeMonthResponse.Month = month.Month
+	envelopeMonthResponse.Income = month.Income
+	envelopeMonthResponse.Unbudgeted = month.Unbudgeted
+	envelopeMonthResponse.Assigned = month.Assigned
+	envelopeMonthResponse.Overspent = month.Overspent
+	envelopeMonthResponse.ToBeBudgeted = month.ToBeBudgeted
+	envelopeMonthResponse.Envelopes = []*EnvelopeResponse{}
+	for _, balance := range month.Envelopes {
+		envelopeMonthResponse.Envelopes = append(envelopeMonthResponse.Envelopes, EnvelopeResponse{}.FromEnvelopeBalance(balance))
+	}
+	return &envelopeMonthResponse
+}
+
+type BudgetAlertResponse struct {
+	ID          uint     `json:"id"`
+	BudgetID    uint     `json:"budget_id"`
+	CategoryID  *int     `json:"category_id"`
+	Type        string   `json:"type"`
+	Threshold   float64  `json:"threshold"`
+	Channels    []string `json:"channels"`
+	WebhookURL  string   `json:"webhook_url"`
+	Active      bool     `json:"active"`
+	FiredPeriod string   `json:"fired_period"`
+	// PendingChannels are the channels the alert could not be delivered
+	// through yet in the period it fired in.
+	PendingChannels []string `json:"pending_channels"`
+}
+
+func (budgetAlertResponse BudgetAlertResponse) FromBudgetAlert(alert models.BudgetAlert) *BudgetAlertResponse {
+	budgetAlertResponse.ID = alert.ID
+	budgetAlertResponse.BudgetID = alert.BudgetID
+	budgetAlertResponse.CategoryID = alert.CategoryID
+	budgetAlertResponse.Type = alert.Type
+	budgetAlertResponse.Threshold = alert.Threshold
+	budgetAlertResponse.Channels = alert.ChannelList()
+	budgetAlertResponse.WebhookURL = alert.WebhookURL
+	budgetAlertResponse.Active = alert.Active
+	if alert.FiredPeriod != nil {
+		budgetAlertResponse.FiredPeriod = alert.FiredPeriod.Format("2006-01-02")
+	}
+	budgetAlertResponse.PendingChannels = append([]string{}, alert.PendingChannelList()...)
+	return &budgetAlertResponse
+}
+
+type NotificationResponse struct {
+	ID        uint       `json:"id"`
+	Kind      string     `json:"kind"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	BudgetID  *uint      `json:"budget_id"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadAt    *time.Time `json:"read_at"`
+}
+
+func (notificationResponse NotificationResponse) FromNotification(notification models.Notification) *NotificationResponse {
+	notificationResponse.ID = notification.ID
+	notificationResponse.Kind = notification.Kind
+	notificationResponse.Title = notification.Title
+	notificationResponse.Body = notification.Body
+	notificationResponse.BudgetID = notification.BudgetID
+	notificationResponse.CreatedAt = notification.CreatedAt
+	notificationResponse.ReadAt = notification.ReadAt
+	return &notificationResponse
+}
+
+// BudgetReportResponse is how budgets performed period by period. Variance
+// is what was left of the limit, negative when it was overspent.
+type BudgetReportResponse struct {
+	From    string                      `json:"from"`
+	To      string                      `json:"to"`
+	Period  string                      `json:"period"`
+	Budgets []BudgetReportEntryResponse `json:"budgets"`
+}
+
+type BudgetReportEntryResponse struct {
+	BudgetID   uint                       `json:"budget_id"`
+	Name       string                     `json:"name"`
+	Periods    []BudgetReportRowResponse  `json:"periods"`
+	Categories []BudgetReportLineResponse `json:"categories"`
+}
+
+type BudgetReportLineResponse struct {
+	LineID     uint                      `json:"line_id"`
+	CategoryID int                       `json:"category_id"`
+	Category   string                    `json:"category"`
+	Periods    []BudgetReportRowResponse `json:"periods"`
+}
+
+type BudgetReportRowResponse struct {
+	StartDate  string  `json:"start_date"`
+	EndDate    string  `json:"end_date"`
+	Limit      float64 `json:"limit"`
+	Actual     float64 `json:"actual"`
+	Variance   float64 `json:"variance"`
+	Percentage float64 `json:"percentage"`
+}
+
+func (budgetReportResponse BudgetReportResponse) FromReport(report budgets.Report) *BudgetReportResponse {
+	rows := func(reportRows []budgets.ReportRow) []BudgetReportRowResponse {
+		result := []BudgetReportRowResponse{}
+		for _, row := range reportRows {
+			result = append(result, BudgetReportRowResponse{
+				StartDate:  row.Start.Format("2006-01-02"),
+				EndDate:    row.End.Format("2006-01-02"),
+				Limit:      row.Limit,
+				Actual:     row.Actual,
+				Variance:   row.Variance,
+				Percentage: row.Percentage,
+			})
+		}
+		return result
+	}
+	budgetReportResponse.From = report.From.Format("2006-01-02")
+	budgetReportResponse.To = report.To.Format("2006-01-02")
+	budgetReportResponse.Period = report.Period
+	budgetReportResponse.Budgets = []BudgetReportEntryResponse{}
+	for _, budget := range report.Budgets {
+		entry := BudgetReportEntryResponse{
+			BudgetID:   budget.BudgetID,
+			Name:       budget.Name,
+			Periods:    rows(budget.Rows),
+			Categories: []BudgetReportLineResponse{},
+		}
+		for _, line := range budget.Lines {
+			entry.Categories = append(entry.Categories, BudgetReportLineResponse{
+				LineID:     line.LineID,
+				CategoryID: line.CategoryID,
+				Category:   line.Category,
+				Periods:    rows(line.Rows),
+			})
+		}
+		budgetReportResponse.Budgets = append(budgetReportResponse.Budgets, entry)
+	}
+	return &budgetReportResponse
+}
+
+// DebtPlanResponse compares ways of paying off debts out of a monthly
+// budget. Months are written as 2006-01.
+type DebtPlanResponse struct {
+	MonthlyBudget   float64                `json:"monthly_budget"`
+	MinimumPayments float64                `json:"minimum_payments"`
+	StartMonth      string                 `json:"start_month"`
+	Debts           []DebtResponse         `json:"debts"`
+	Strategies      []DebtStrategyResponse `json:"strategies"`
+}
+
+type DebtResponse struct {
+	AccountID      int     `json:"account_id"`
+	Name           string  `json:"name"`
+	Balance        float64 `json:"balance"`
+	InterestRate   float64 `json:"interest_rate"`
+	MinimumPayment float64 `json:"minimum_payment"`
+}
+
+// DebtStrategyResponse is the plan of one strategy. DebtFreeDate is left out
+// when the debts are not paid off within 50 years.
+type DebtStrategyResponse struct {
+	Strategy      string                  `json:"strategy" enums:"snowball,avalanche,custom"`
+	Order         []int                   `json:"order"`
+	Months        int                     `json:"months"`
+	PaidOff       bool                    `json:"paid_off"`
+	DebtFreeDate  string                  `json:"debt_free_date,omitempty"`
+	TotalInterest float64                 `json:"total_interest"`
+	TotalPaid     float64                 `json:"total_paid"`
+	Debts         []DebtPayoffResponse    `json:"debts"`
+	Schedule      []DebtPlanMonthResponse `json:"schedule"`
+}
+
+type DebtPayoffResponse struct {
+	AccountID int     `json:"account_id"`
+	Name      string  `json:"name"`
+	Interest  float64 `json:"interest"`
+	Paid      float64 `json:"paid"`
+	PaidOffIn string  `json:"paid_off_in,omitempty"`
+}
+
+type DebtPlanMonthResponse struct {
+	Month    string                `json:"month"`
+	Paid     float64               `json:"paid"`
+	Interest float64               `json:"interest"`
+	Balance  float64               `json:"balance"`
+	Payments []DebtPaymentResponse `json:"payments"`
+}
+
+type DebtPaymentResponse struct {
+	AccountID int     `json:"account_id"`
+	Payment   float64 `json:"payment"`
+	Interest  float64 `json:"interest"`
+	Balance   float64 `json:"balance"`
+}
+
+func (debtStrategyResponse DebtStrategyResponse) FromPlan(plan debts.Plan) DebtStrategyResponse {
+	debtStrategyResponse.Strategy = plan.Strategy
+	debtStrategyResponse.Order = plan.Order
+	debtStrategyResponse.Months = plan.Months
+	debtStrategyResponse.PaidOff = plan.PaidOff
+	if plan.DebtFree != nil {
+		debtStrategyResponse.DebtFreeDate = plan.DebtFree.Format(budgets.MonthLayout)
+	}
+	debtStrategyResponse.TotalInterest = plan.TotalInterest
+	debtStrategyResponse.TotalPaid = plan.TotalPaid
+	debtStrategyResponse.Debts = []DebtPayoffResponse{}
+	for _, debt := range plan.Debts {
+		payoff := DebtPayoffResponse{AccountID: debt.AccountID, Name: debt.Name, Interest: debt.Interest, Paid: debt.Paid}
+		if debt.PaidOffIn != nil {
+			payoff.PaidOffIn = debt.PaidOffIn.Format(budgets.MonthLayout)
+		}
+		debtStrategyResponse.Debts = append(debtStrategyResponse.Debts, payoff)
+	}
+	debtStrategyResponse.Schedule = []DebtPlanMonthResponse{}
+	for _, month := range plan.Schedule {
+		monthResponse := DebtPlanMonthResponse{
+			Month:    month.Month.Format(budgets.MonthLayout),
+			Paid:     month.Paid,
+			Interest: month.Interest,
+			Balance:  month.Balance,
+			Payments: []DebtPaymentResponse{},
+		}
+		for _, payment := range month.Payments {
+			monthResponse.Payments = append(monthResponse.Payments, DebtPaymentResponse{
+				AccountID: payment.AccountID,
+				Payment:   payment.Payment,
+				Interest:  payment.Interest,
+				Balance:   payment.Balance,
+			})
+		}
+		debtStrategyResponse.Schedule = append(debtStrategyResponse.Schedule, monthResponse)
+	}
+	return debtStrategyResponse
+}
+
 type PercentageOfTotalAmountByTransactionResponse struct {
+	CategoryID int     `json:"category_id"`
 	Category   string  `json:"category"`
+	Path       string  `json:"path"`
 	Amount     float64 `json:"amount"`
 	Percentage float64 `json:"percentage"`
 }
 
 // AccountStatisticsResponse holds the overall statistics for an account.
 type AccountStatisticsResponse struct {
-	TotalBalance float64               `json:"total_balance"`
-	TotalIncome  float64               `json:"total_income"`
-	TotalExpense float64               `json:"total_expense"`
-	Transactions TransactionStatistics `json:"transactions"`
+	TotalBalance   float64               `json:"total_balance"`
+	ClearedBalance float64               `json:"cleared_balance"`
+	TotalIncome    float64               `json:"total_income"`
+	TotalExpense   float64               `json:"total_expense"`
+	Transactions   TransactionStatistics `json:"transactions"`
+}
+
+// AccountBalanceResponse breaks the balance of an account down by status.
+type AccountBalanceResponse struct {
+	AccountID         int     `json:"account_id"`
+	Currency          string  `json:"currency"`
+	OpeningBalance    float64 `json:"opening_balance"`
+	ReconciledBalance float64 `json:"reconciled_balance"`
+	ClearedBalance    float64 `json:"cleared_balance"`
+	PendingAmount     float64 `json:"pending_amount"`
+	AvailableBalance  float64 `json:"available_balance"`
+	CurrentBalance    float64 `json:"current_balance"`
 }
 
 // TransactionStatistics holds detailed statistics about transactions.
@@ -138,3 +774,143 @@ type WeekComparison struct {
 func (weekComparison *WeekComparison) CalculateChange() {
 	weekComparison.Change = weekComparison.ThisWeek - weekComparison.LastWeek
 }
+
+type RuleConditionResponse struct {
+	Field    string `json:"field"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+	ValueTo  string `json:"value_to,omitempty"`
+}
+
+type RuleActionResponse struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type RuleResponse struct {
+	ID             int                     `json:"id"`
+	Name           string                  `json:"name"`
+	Priority       int                     `json:"priority"`
+	Enabled        bool                    `json:"enabled"`
+	StopProcessing bool                    `json:"stop_processing"`
+	Conditions     []RuleConditionResponse `json:"conditions"`
+	Actions        []RuleActionResponse    `json:"actions"`
+}
+
+type RuleRunResult struct {
+	TransactionID int            `json:"transaction_id"`
+	Date          int64          `json:"date"`
+	Description   string         `json:"description"`
+	Amount        float64        `json:"amount"`
+	Changes       []rules.Change `json:"changes"`
+}
+
+type RunRulesResponse struct {
+	Preview  bool            `json:"preview"`
+	Scanned  int             `json:"scanned"`
+	Affected int             `json:"affected"`
+	Results  []RuleRunResult `json:"results"`
+}
+
+type PayeeResponse struct {
+	ID                int      `json:"id"`
+	Name              string   `json:"name"`
+	DefaultCategoryID *int     `json:"default_category_id"`
+	DefaultCategory   string   `json:"default_category"`
+	Aliases           []string `json:"aliases"`
+	Patterns          []string `json:"patterns"`
+}
+
+type PayeeSpendResponse struct {
+	PayeeID      int     `json:"payee_id"`
+	Payee        string  `json:"payee"`
+	Amount       float64 `json:"amount"`
+	Transactions int     `json:"transactions"`
+}
+
+type RecurringTransactionResponse struct {
+	ID              int     `json:"id"`
+	Name            string  `json:"name"`
+	AccountID       int     `json:"account_id"`
+	Amount          float64 `json:"amount"`
+	Currency        string  `json:"currency"`
+	Description     string  `json:"description"`
+	Payee           string  `json:"payee"`
+	Category        string  `json:"category"`
+	TransactionType string  `json:"transaction_type"`
+	Recurrence      string  `json:"recurrence"`
+	StartDate       int64   `json:"start_date"`
+	NextDate        *int64  `json:"next_date"`
+	AutoPost        bool    `json:"auto_post"`
+	Active          bool    `json:"active"`
+}
+
+type UpcomingTransactionResponse struct {
+	RecurringID int     `json:"recurring_id"`
+	Name        string  `json:"name"`
+	Date        int64   `json:"date"`
+	AccountID   int     `json:"account_id"`
+	Amount      float64 `json:"amount"`
+	Currency    string  `json:"currency"`
+	Payee       string  `json:"payee"`
+	Category    string  `json:"category"`
+	AutoPost    bool    `json:"auto_post"`
+}
+
+type SubscriptionResponse struct {
+	Key            string  `json:"key"`
+	Name           string  `json:"name"`
+	PayeeID        *int    `json:"payee_id"`
+	AccountID      int     `json:"account_id"`
+	Currency       string  `json:"currency"`
+	Cadence        string  `json:"cadence"`
+	Recurrence     string  `json:"recurrence"`
+	Amount         float64 `json:"amount"`
+	AverageAmount  float64 `json:"average_amount"`
+	Occurrences    int     `json:"occurrences"`
+	FirstDate      int64   `json:"first_date"`
+	LastDate       int64   `json:"last_date"`
+	NextDate       int64   `json:"next_date"`
+	PriceIncreased bool    `json:"price_increased"`
+	PreviousAmount float64 `json:"previous_amount,omitempty"`
+	Stopped        bool    `json:"stopped"`
+	RecurringID    *int    `json:"recurring_id"`
+	TransactionIDs []int   `json:"transaction_ids"`
+}
+
+type AttachmentResponse struct {
+	ID            int    `json:"id"`
+	TransactionID int    `json:"transaction_id"`
+	FileName      string `json:"file_name"`
+	ContentType   string `json:"content_type"`
+	Size          int64  `json:"size"`
+	Checksum      string `json:"checksum"`
+	CreatedAt     int64  `json:"created_at"`
+}
+
+type SearchResultResponse struct {
+	Transaction TransactionResponse `json:"transaction"`
+	Score       float64             `json:"score"`
+	Snippets    []SnippetResponse   `json:"snippets"`
+}
+
+type SnippetResponse struct {
+	Field string `json:"field"`
+	Text  string `json:"text"`
+}
+
+type AuditLogResponse struct {
+	ID        int                            `json:"id"`
+	Entity    string                         `json:"entity"`
+	EntityID  int                            `json:"entity_id"`
+	Action    string                         `json:"action"`
+	ActorID   *uint                          `json:"actor_id"`
+	RequestID string                         `json:"request_id"`
+	Changes   map[string]AuditChangeResponse `json:"changes"`
+	CreatedAt int64                          `json:"created_at"`
+}
+
+type AuditChangeResponse struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
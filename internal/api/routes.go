@@ -3,71 +3,156 @@ package api
 import (
 	"github.com/christo-andrew/haven/internal/api/handlers"
 	"github.com/christo-andrew/haven/internal/api/middleware"
+	"github.com/christo-andrew/haven/pkg/budgets"
+	"github.com/christo-andrew/haven/pkg/search"
+	"github.com/christo-andrew/haven/pkg/storage"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-func AccountsRouterV1(router *gin.RouterGroup, db *gorm.DB) {
+// requestDB binds the database to the request, so that the changes a handler
+// makes are attributed to the user and request in the audit log.
+func requestDB(ctx *gin.Context, db *gorm.DB) *gorm.DB {
+	return db.WithContext(ctx.Request.Context())
+}
+
+func AccountsRouterV1(router *gin.RouterGroup, db *gorm.DB, alerter *budgets.Alerter) {
 	router.GET("", func(ctx *gin.Context) {
-		handlers.GetAllAccountsHandler(ctx, db)
+		handlers.GetAllAccountsHandler(ctx, requestDB(ctx, db))
 	})
 
 	router.GET("/:id", func(ctx *gin.Context) {
-		handlers.GetAccountHandler(ctx, db)
+		handlers.GetAccountHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.GET("/:id/balance", func(ctx *gin.Context) {
+		handlers.GetAccountBalanceHandler(ctx, requestDB(ctx, db))
 	})
 
 	router.GET("/:id/statistics", func(ctx *gin.Context) {
-		handlers.AccountStatisticsHandler(ctx, db)
+		handlers.AccountStatisticsHandler(ctx, requestDB(ctx, db))
 	})
 
 	router.GET("/:id/transactions", func(ctx *gin.Context) {
-		handlers.GetAccountTransactionsHandler(ctx, db)
+		handlers.GetAccountTransactionsHandler(ctx, requestDB(ctx, db))
 	})
 
 	router.POST("/:id/transactions/create", func(ctx *gin.Context) {
-		handlers.CreateAccountTransactionHandler(ctx, db)
+		handlers.CreateAccountTransactionHandler(ctx, requestDB(ctx, db), alerter)
 	})
 
 	router.POST("/create", func(ctx *gin.Context) {
-		handlers.CreateAccountHandler(ctx, db)
+		handlers.CreateAccountHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.PUT("/:id/update", func(ctx *gin.Context) {
+		handlers.UpdateAccountHandler(ctx, requestDB(ctx, db))
 	})
 
 	router.GET("/:id/transactions/recent", func(ctx *gin.Context) {
-		handlers.GetRecentTransactionsHandler(ctx, db)
+		handlers.GetRecentTransactionsHandler(ctx, requestDB(ctx, db))
 	})
 
 	router.POST("/:id/transactions/upload", func(ctx *gin.Context) {
-		handlers.UploadAccountTransactionsHandler(ctx, db)
+		handlers.UploadAccountTransactionsHandler(ctx, requestDB(ctx, db), alerter)
 	})
 
 	router.GET("/:id/transactions/percentage", func(ctx *gin.Context) {
-		handlers.PercentageOfTotalAmountByTransactionHandler(ctx, db)
+		handlers.PercentageOfTotalAmountByTransactionHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.GET("/:id/tags", func(ctx *gin.Context) {
+		handlers.GetAccountTagsHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.POST("/:id/tags", func(ctx *gin.Context) {
+		handlers.AddAccountTagHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.DELETE("/:id/tags/:tag_id", func(ctx *gin.Context) {
+		handlers.RemoveAccountTagHandler(ctx, requestDB(ctx, db))
 	})
 }
 
-func TransactionsRouterV1(router *gin.RouterGroup, db *gorm.DB) {
+func TransactionsRouterV1(router *gin.RouterGroup, db *gorm.DB, store storage.Storage, maxUploadSize int64, alerter *budgets.Alerter) {
 	router.GET("", func(ctx *gin.Context) {
-		handlers.GetAllTransactionsHandler(ctx, db)
+		handlers.GetAllTransactionsHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.GET("/search", func(ctx *gin.Context) {
+		handlers.SearchTransactionsHandler(ctx, requestDB(ctx, db))
 	})
 
 	router.GET("/:id", func(ctx *gin.Context) {
-		handlers.GetTransactionHandler(ctx, db)
+		handlers.GetTransactionHandler(ctx, requestDB(ctx, db))
 	})
 
 	router.POST("/create", func(ctx *gin.Context) {
-		handlers.CreateAccountTransactionHandler(ctx, db)
+		handlers.CreateAccountTransactionHandler(ctx, requestDB(ctx, db), alerter)
 	})
 
 	router.GET("/recent", func(ctx *gin.Context) {
-		handlers.GetRecentTransactionsHandler(ctx, db)
+		handlers.GetRecentTransactionsHandler(ctx, requestDB(ctx, db))
 	})
 
 	router.POST("/:id/tags", func(ctx *gin.Context) {
-		handlers.AddTransactionTagHandler(ctx, db)
+		handlers.AddTransactionTagHandler(ctx, requestDB(ctx, db), alerter)
 	})
 
 	router.GET("/:id/tags", func(ctx *gin.Context) {
-		handlers.GetTransactionTagsHandler(ctx, db)
+		handlers.GetTransactionTagsHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.DELETE("/:id/tags/:tag_id", func(ctx *gin.Context) {
+		handlers.RemoveTransactionTagHandler(ctx, requestDB(ctx, db), alerter)
+	})
+
+	router.GET("/:id/splits", func(ctx *gin.Context) {
+		handlers.GetTransactionSplitsHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.PUT("/:id/splits", func(ctx *gin.Context) {
+		handlers.UpdateTransactionSplitsHandler(ctx, requestDB(ctx, db), alerter)
+	})
+
+	router.POST("/:id/confirm", func(ctx *gin.Context) {
+		handlers.ConfirmTransactionHandler(ctx, requestDB(ctx, db), alerter)
+	})
+
+	router.GET("/:id/attachments", func(ctx *gin.Context) {
+		handlers.GetTransactionAttachmentsHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.POST("/:id/attachments", func(ctx *gin.Context) {
+		handlers.UploadTransactionAttachmentHandler(ctx, requestDB(ctx, db), store, maxUploadSize)
+	})
+
+	router.GET("/:id/attachments/:attachment_id", func(ctx *gin.Context) {
+		handlers.DownloadTransactionAttachmentHandler(ctx, requestDB(ctx, db), store)
+	})
+
+	router.DELETE("/:id/attachments/:attachment_id", func(ctx *gin.Context) {
+		handlers.DeleteTransactionAttachmentHandler(ctx, requestDB(ctx, db), store)
+	})
+
+	router.GET("/:id/suggestions", func(ctx *gin.Context) {
+		handlers.GetTransactionSuggestionsHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.PUT("/:id/category", func(ctx *gin.Context) {
+		handlers.UpdateTransactionCategoryHandler(ctx, requestDB(ctx, db), alerter)
+	})
+
+	router.PUT("/:id/status", func(ctx *gin.Context) {
+		handlers.UpdateTransactionStatusHandler(ctx, requestDB(ctx, db), alerter)
+	})
+
+	router.PUT("/:id/notes", func(ctx *gin.Context) {
+		handlers.UpdateTransactionNotesHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.GET("/:id/history", func(ctx *gin.Context) {
+		handlers.GetTransactionHistoryHandler(ctx, requestDB(ctx, db))
 	})
 
 	router.GET("/schemas", func(ctx *gin.Context) {
@@ -77,69 +162,358 @@ func TransactionsRouterV1(router *gin.RouterGroup, db *gorm.DB) {
 
 func CategoriesRouterV1(router *gin.RouterGroup, db *gorm.DB) {
 	router.GET("", func(ctx *gin.Context) {
-		handlers.GetAllCategoriesHandler(ctx, db)
+		handlers.GetAllCategoriesHandler(ctx, requestDB(ctx, db))
 	})
 
 	router.GET("/:id", func(ctx *gin.Context) {
-		handlers.GetCategoryHandler(ctx, db)
+		handlers.GetCategoryHandler(ctx, requestDB(ctx, db))
 	})
 
 	router.POST("/create", func(ctx *gin.Context) {
-		handlers.CreateCategoryHandler(ctx, db)
+		handlers.CreateCategoryHandler(ctx, requestDB(ctx, db))
 	})
 
 	router.GET("/types", func(ctx *gin.Context) {
-		handlers.GetCategoryByContextAndContextTypeHandler(ctx, db)
+		handlers.GetCategoryByContextAndContextTypeHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.GET("/tree", func(ctx *gin.Context) {
+		handlers.GetCategoryTreeHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.PUT("/:id/move", func(ctx *gin.Context) {
+		handlers.MoveCategoryHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.POST("/merge", func(ctx *gin.Context) {
+		handlers.MergeCategoriesHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.PUT("/:id/override", func(ctx *gin.Context) {
+		handlers.UpdateCategoryOverrideHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.DELETE("/:id/override", func(ctx *gin.Context) {
+		handlers.DeleteCategoryOverrideHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.PUT("/:id/rename", func(ctx *gin.Context) {
+		handlers.RenameCategoryHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.DELETE("/:id", func(ctx *gin.Context) {
+		handlers.DeleteCategoryHandler(ctx, requestDB(ctx, db))
 	})
 }
 
 func UsersRouterV1(router *gin.RouterGroup, db *gorm.DB) {
 	router.GET("/", middleware.WithAuthUser(), func(ctx *gin.Context) {
-		handlers.GetAllUsersHandler(ctx, db)
+		handlers.GetAllUsersHandler(ctx, requestDB(ctx, db))
 	})
 
 	router.POST("/create", func(ctx *gin.Context) {
-		handlers.CreateUserHandler(ctx, db)
+		handlers.CreateUserHandler(ctx, requestDB(ctx, db))
 	})
 
 	router.GET("/:id", middleware.WithAuthUser(), func(ctx *gin.Context) {
-		handlers.GetUserHandler(ctx, db)
+		handlers.GetUserHandler(ctx, requestDB(ctx, db))
 	})
 }
 
 func AuthRouterV1(router *gin.RouterGroup, db *gorm.DB) {
 	router.POST("/login", func(ctx *gin.Context) {
-		handlers.LoginHandler(ctx, db)
+		handlers.LoginHandler(ctx, requestDB(ctx, db))
 	})
 }
 
 func DataRouterV1(router *gin.RouterGroup, db *gorm.DB) {
 	router.GET("/:account_id/transactions/histogram", func(ctx *gin.Context) {
-		handlers.TransactionsHistogramHandler(ctx, db)
+		handlers.TransactionsHistogramHandler(ctx, requestDB(ctx, db))
 	})
 	router.GET("/:account_id/transactions/summary", func(ctx *gin.Context) {
-		handlers.TransactionsSummaryHandler(ctx, db)
+		handlers.TransactionsSummaryHandler(ctx, requestDB(ctx, db))
+	})
+	router.GET("/subscriptions", func(ctx *gin.Context) {
+		handlers.GetSubscriptionsHandler(ctx, requestDB(ctx, db))
+	})
+	router.POST("/subscriptions/confirm", func(ctx *gin.Context) {
+		handlers.ConfirmSubscriptionHandler(ctx, requestDB(ctx, db))
+	})
+	router.POST("/debt-plan", func(ctx *gin.Context) {
+		handlers.DebtPlanHandler(ctx, requestDB(ctx, db))
 	})
 }
 
 func BudgetsRouterV1(router *gin.RouterGroup, db *gorm.DB) {
+	tracker := budgets.NewTracker(db)
+
 	router.POST("/create", func(ctx *gin.Context) {
-		handlers.CreateBudgetHandler(ctx, db)
+		handlers.CreateBudgetHandler(ctx, requestDB(ctx, db), tracker)
 	})
 
 	router.GET("/:id", func(ctx *gin.Context) {
-		handlers.GetBudgetHandler(ctx, db)
+		handlers.GetBudgetHandler(ctx, requestDB(ctx, db), tracker)
 	})
 
 	router.PUT("/:id/update", func(ctx *gin.Context) {
-		handlers.UpdateBudgetHandler(ctx, db)
+		handlers.UpdateBudgetHandler(ctx, requestDB(ctx, db), tracker)
 	})
 
 	router.GET("", func(ctx *gin.Context) {
-		handlers.GetBudgetsHandler(ctx, db)
+		handlers.GetBudgetsHandler(ctx, requestDB(ctx, db), tracker)
+	})
+
+	router.GET("/reports", func(ctx *gin.Context) {
+		handlers.GetBudgetReportHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.GET("/forecast", func(ctx *gin.Context) {
+		handlers.GetBudgetForecastsHandler(ctx, requestDB(ctx, db), tracker)
+	})
+
+	router.GET("/suggest", func(ctx *gin.Context) {
+		handlers.SuggestBudgetHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.GET("/templates", func(ctx *gin.Context) {
+		handlers.GetBudgetTemplatesHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.POST("/templates", func(ctx *gin.Context) {
+		handlers.CreateBudgetTemplateHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.DELETE("/templates/:template_id", func(ctx *gin.Context) {
+		handlers.DeleteBudgetTemplateHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.POST("/templates/:template_id/draft", func(ctx *gin.Context) {
+		handlers.DraftBudgetFromTemplateHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.GET("/:id/tags", func(ctx *gin.Context) {
+		handlers.GetBudgetTagsHandler(ctx, requestDB(ctx, db))
 	})
 
-	//router.POST(":id/tags", func(ctx *gin.Context) {
-	//	handlers.AddBudgetTagHandler(ctx, db)
-	//})
+	router.GET("/:id/periods", func(ctx *gin.Context) {
+		handlers.GetBudgetPeriodsHandler(ctx, requestDB(ctx, db), tracker)
+	})
+
+	router.GET("/:id/alerts", func(ctx *gin.Context) {
+		handlers.GetBudgetAlertsHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.POST("/:id/alerts", func(ctx *gin.Context) {
+		handlers.CreateBudgetAlertHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.PUT("/:id/alerts/:alert_id", func(ctx *gin.Context) {
+		handlers.UpdateBudgetAlertHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.DELETE("/:id/alerts/:alert_id", func(ctx *gin.Context) {
+		handlers.DeleteBudgetAlertHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.POST("/:id/tags", func(ctx *gin.Context) {
+		handlers.AddBudgetTagHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.DELETE("/:id/tags/:tag_id", func(ctx *gin.Context) {
+		handlers.RemoveBudgetTagHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.GET("/:id/categories", func(ctx *gin.Context) {
+		handlers.GetBudgetCategoriesHandler(ctx, requestDB(ctx, db), tracker)
+	})
+
+	router.POST("/:id/categories", func(ctx *gin.Context) {
+		handlers.AddBudgetCategoryHandler(ctx, requestDB(ctx, db), tracker)
+	})
+
+	router.PUT("/:id/categories/:line_id", func(ctx *gin.Context) {
+		handlers.UpdateBudgetCategoryHandler(ctx, requestDB(ctx, db), tracker)
+	})
+
+	router.DELETE("/:id/categories/:line_id", func(ctx *gin.Context) {
+		handlers.DeleteBudgetCategoryHandler(ctx, requestDB(ctx, db), tracker)
+	})
+}
+
+func GoalsRouterV1(router *gin.RouterGroup, db *gorm.DB) {
+	tracker := budgets.NewTracker(db)
+
+	router.GET("", func(ctx *gin.Context) {
+		handlers.GetGoalsHandler(ctx, requestDB(ctx, db), tracker)
+	})
+
+	router.POST("", func(ctx *gin.Context) {
+		handlers.CreateGoalHandler(ctx, requestDB(ctx, db), tracker)
+	})
+
+	router.GET("/:id", func(ctx *gin.Context) {
+		handlers.GetGoalHandler(ctx, requestDB(ctx, db), tracker)
+	})
+
+	router.PUT("/:id", func(ctx *gin.Context) {
+		handlers.UpdateGoalHandler(ctx, requestDB(ctx, db), tracker)
+	})
+
+	router.DELETE("/:id", func(ctx *gin.Context) {
+		handlers.DeleteGoalHandler(ctx, requestDB(ctx, db))
+	})
+}
+
+func RulesRouterV1(router *gin.RouterGroup, db *gorm.DB) {
+	router.GET("", func(ctx *gin.Context) {
+		handlers.GetRulesHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.GET("/:id", func(ctx *gin.Context) {
+		handlers.GetRuleHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.POST("/create", func(ctx *gin.Context) {
+		handlers.CreateRuleHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.PUT("/:id/update", func(ctx *gin.Context) {
+		handlers.UpdateRuleHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.DELETE("/:id", func(ctx *gin.Context) {
+		handlers.DeleteRuleHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.POST("/run", func(ctx *gin.Context) {
+		handlers.RunRulesHandler(ctx, requestDB(ctx, db))
+	})
+}
+
+func PayeesRouterV1(router *gin.RouterGroup, db *gorm.DB) {
+	router.GET("", func(ctx *gin.Context) {
+		handlers.GetPayeesHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.GET("/spend", func(ctx *gin.Context) {
+		handlers.GetPayeeSpendHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.GET("/:id", func(ctx *gin.Context) {
+		handlers.GetPayeeHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.POST("/create", func(ctx *gin.Context) {
+		handlers.CreatePayeeHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.PUT("/:id/update", func(ctx *gin.Context) {
+		handlers.UpdatePayeeHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.POST("/merge", func(ctx *gin.Context) {
+		handlers.MergePayeesHandler(ctx, requestDB(ctx, db))
+	})
+}
+
+func RecurringRouterV1(router *gin.RouterGroup, db *gorm.DB) {
+	router.GET("", func(ctx *gin.Context) {
+		handlers.GetRecurringTransactionsHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.GET("/upcoming", func(ctx *gin.Context) {
+		handlers.GetUpcomingTransactionsHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.GET("/:id", func(ctx *gin.Context) {
+		handlers.GetRecurringTransactionHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.POST("/create", func(ctx *gin.Context) {
+		handlers.CreateRecurringTransactionHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.PUT("/:id/update", func(ctx *gin.Context) {
+		handlers.UpdateRecurringTransactionHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.DELETE("/:id", func(ctx *gin.Context) {
+		handlers.DeleteRecurringTransactionHandler(ctx, requestDB(ctx, db))
+	})
+}
+
+func SearchRouterV1(router *gin.RouterGroup, db *gorm.DB) {
+	searcher := search.New(db)
+
+	router.GET("/transactions", func(ctx *gin.Context) {
+		handlers.FullTextSearchHandler(ctx, requestDB(ctx, db), searcher)
+	})
+}
+
+func AuditRouterV1(router *gin.RouterGroup, db *gorm.DB) {
+	router.GET("", func(ctx *gin.Context) {
+		handlers.GetAuditLogHandler(ctx, requestDB(ctx, db))
+	})
+}
+
+func EnvelopesRouterV1(router *gin.RouterGroup, db *gorm.DB) {
+	router.GET("", func(ctx *gin.Context) {
+		handlers.GetEnvelopesHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.POST("", func(ctx *gin.Context) {
+		handlers.CreateEnvelopeHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.POST("/move", func(ctx *gin.Context) {
+		handlers.MoveEnvelopeMoneyHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.PUT("/:id", func(ctx *gin.Context) {
+		handlers.UpdateEnvelopeHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.DELETE("/:id", func(ctx *gin.Context) {
+		handlers.DeleteEnvelopeHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.PUT("/:id/months/:month", func(ctx *gin.Context) {
+		handlers.AssignEnvelopeHandler(ctx, requestDB(ctx, db))
+	})
+}
+
+func NotificationsRouterV1(router *gin.RouterGroup, db *gorm.DB) {
+	router.GET("", func(ctx *gin.Context) {
+		handlers.GetNotificationsHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.PUT("/read", func(ctx *gin.Context) {
+		handlers.MarkAllNotificationsReadHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.PUT("/:id/read", func(ctx *gin.Context) {
+		handlers.MarkNotificationReadHandler(ctx, requestDB(ctx, db))
+	})
+}
+
+func TagsRouterV1(router *gin.RouterGroup, db *gorm.DB) {
+	router.GET("", func(ctx *gin.Context) {
+		handlers.GetTagsHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.POST("", func(ctx *gin.Context) {
+		handlers.CreateTagHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.POST("/merge", func(ctx *gin.Context) {
+		handlers.MergeTagsHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.PUT("/:id/rename", func(ctx *gin.Context) {
+		handlers.RenameTagHandler(ctx, requestDB(ctx, db))
+	})
+
+	router.DELETE("/:id", func(ctx *gin.Context) {
+		handlers.DeleteTagHandler(ctx, requestDB(ctx, db))
+	})
 }
@@ -50,7 +50,7 @@ func (schema *StanbicTransactionSchema) Transaction(data map[string]interface{})
 	}
 
 	transactionType := scopes.GetOrCreateTransactionType(transactionTypeName, schema.db)
-	category := scopes.GetOrCreateTransactionCategory("General", schema.db)
+	category := scopes.GetOrCreateTransactionCategory(models.DefaultCategoryName, schema.Account.UserID, schema.db)
 
 	return &models.Transaction{
 		Amount:          amount,
@@ -58,12 +58,14 @@ func (as AccountSerializer) serializeSingle(obj interface{}) (*responses.Account
 	}
 
 	return &responses.AccountResponse{
-		ID:          account.ID,
-		AccountName: account.AccountName,
-		Currency:    account.Currency,
-		Balance:     account.Balance,
-		AccountType: account.AccountType,
-		Category:    account.BaseAccountType,
+		ID:             account.ID,
+		AccountName:    account.AccountName,
+		Currency:       account.Currency,
+		Balance:        account.Balance,
+		AccountType:    account.AccountType,
+		Category:       account.BaseAccountType,
+		InterestRate:   account.InterestRate,
+		MinimumPayment: account.MinimumPayment,
 	}, nil
 }
 
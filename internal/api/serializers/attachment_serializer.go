@@ -0,0 +1,64 @@
+package serializers
+
+import (
+	"github.com/christo-andrew/haven/internal/api/responses"
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/errors"
+)
+
+type AttachmentSerializer struct {
+	Data interface{}
+	many bool
+}
+
+func NewAttachmentSerializer(data interface{}, many bool) *AttachmentSerializer {
+	return &AttachmentSerializer{
+		Data: data,
+		many: many,
+	}
+}
+
+func (as AttachmentSerializer) Serialize() (interface{}, error) {
+	switch as.Data.(type) {
+	case []models.Attachment:
+		return as.serializeMany(as.Data)
+	case models.Attachment:
+		return as.serializeSingle(as.Data)
+	default:
+		return nil, errors.InvalidDataError()
+	}
+}
+
+func (as AttachmentSerializer) serializeSingle(obj interface{}) (*responses.AttachmentResponse, error) {
+	attachment, ok := obj.(models.Attachment)
+	if !ok {
+		return nil, errors.InvalidDataError()
+	}
+
+	return &responses.AttachmentResponse{
+		ID:            attachment.ID,
+		TransactionID: attachment.TransactionID,
+		FileName:      attachment.FileName,
+		ContentType:   attachment.ContentType,
+		Size:          attachment.Size,
+		Checksum:      attachment.Checksum,
+		CreatedAt:     attachment.CreatedAt.Unix(),
+	}, nil
+}
+
+func (as AttachmentSerializer) serializeMany(obj interface{}) (interface{}, error) {
+	attachments, ok := obj.([]models.Attachment)
+	if !ok {
+		return nil, errors.InvalidDataError()
+	}
+
+	response := make([]*responses.AttachmentResponse, 0, len(attachments))
+	for _, attachment := range attachments {
+		data, err := as.serializeSingle(attachment)
+		if err != nil {
+			return nil, err
+		}
+		response = append(response, data)
+	}
+	return response, nil
+}
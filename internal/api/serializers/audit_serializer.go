@@ -0,0 +1,73 @@
+package serializers
+
+import (
+	"encoding/json"
+
+	"github.com/christo-andrew/haven/internal/api/responses"
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/errors"
+)
+
+type AuditLogSerializer struct {
+	Data interface{}
+	many bool
+}
+
+func NewAuditLogSerializer(data interface{}, many bool) *AuditLogSerializer {
+	return &AuditLogSerializer{
+		Data: data,
+		many: many,
+	}
+}
+
+func (as AuditLogSerializer) Serialize() (interface{}, error) {
+	switch as.Data.(type) {
+	case []models.AuditLog:
+		return as.serializeMany(as.Data)
+	case models.AuditLog:
+		return as.serializeSingle(as.Data)
+	default:
+		return nil, errors.InvalidDataError()
+	}
+}
+
+func (as AuditLogSerializer) serializeSingle(obj interface{}) (*responses.AuditLogResponse, error) {
+	entry, ok := obj.(models.AuditLog)
+	if !ok {
+		return nil, errors.InvalidDataError()
+	}
+
+	changes := make(map[string]responses.AuditChangeResponse)
+	if entry.Changes != "" {
+		if err := json.Unmarshal([]byte(entry.Changes), &changes); err != nil {
+			return nil, err
+		}
+	}
+	return &responses.AuditLogResponse{
+		ID:        entry.ID,
+		Entity:    entry.Entity,
+		EntityID:  entry.EntityID,
+		Action:    entry.Action,
+		ActorID:   entry.ActorID,
+		RequestID: entry.RequestID,
+		Changes:   changes,
+		CreatedAt: entry.CreatedAt.Unix(),
+	}, nil
+}
+
+func (as AuditLogSerializer) serializeMany(obj interface{}) (interface{}, error) {
+	entries, ok := obj.([]models.AuditLog)
+	if !ok {
+		return nil, errors.InvalidDataError()
+	}
+
+	response := make([]*responses.AuditLogResponse, 0, len(entries))
+	for _, entry := range entries {
+		data, err := as.serializeSingle(entry)
+		if err != nil {
+			return nil, err
+		}
+		response = append(response, data)
+	}
+	return response, nil
+}
@@ -40,6 +40,11 @@ func (cs CategorySerializer) serializeSingle(obj interface{}) (interface{}, erro
 		Name:        category.Name,
 		Context:     category.Context,
 		ContextType: category.ContextType,
+		ParentID:    category.ParentID,
+		IsSystem:    category.IsSystem,
+		Color:       category.Color,
+		Icon:        category.Icon,
+		Hidden:      category.Hidden,
 	}, nil
 
 }
@@ -56,6 +61,11 @@ func (cs CategorySerializer) serializeMany() (interface{}, error) {
 			Name:        category.Name,
 			Context:     category.Context,
 			ContextType: category.ContextType,
+			ParentID:    category.ParentID,
+			IsSystem:    category.IsSystem,
+			Color:       category.Color,
+			Icon:        category.Icon,
+			Hidden:      category.Hidden,
 		})
 	}
 	return response, nil
@@ -0,0 +1,73 @@
+package serializers
+
+import (
+	"github.com/christo-andrew/haven/internal/api/responses"
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/errors"
+)
+
+type PayeeSerializer struct {
+	Data interface{}
+	many bool
+}
+
+func NewPayeeSerializer(data interface{}, many bool) *PayeeSerializer {
+	return &PayeeSerializer{
+		Data: data,
+		many: many,
+	}
+}
+
+func (ps PayeeSerializer) Serialize() (interface{}, error) {
+	switch ps.Data.(type) {
+	case []models.Payee:
+		return ps.serializeMany(ps.Data)
+	case models.Payee:
+		return ps.serializeSingle(ps.Data)
+	default:
+		return nil, errors.InvalidDataError()
+	}
+}
+
+func (ps PayeeSerializer) serializeSingle(obj interface{}) (*responses.PayeeResponse, error) {
+	payee, ok := obj.(models.Payee)
+	if !ok {
+		return nil, errors.InvalidDataError()
+	}
+
+	response := &responses.PayeeResponse{
+		ID:                payee.ID,
+		Name:              payee.Name,
+		DefaultCategoryID: payee.DefaultCategoryID,
+		Aliases:           make([]string, 0),
+		Patterns:          make([]string, 0),
+	}
+	if payee.DefaultCategory != nil {
+		response.DefaultCategory = payee.DefaultCategory.Name
+	}
+	for _, alias := range payee.Aliases {
+		if alias.IsPattern {
+			response.Patterns = append(response.Patterns, alias.Name)
+		} else {
+			response.Aliases = append(response.Aliases, alias.Name)
+		}
+	}
+	return response, nil
+}
+
+func (ps PayeeSerializer) serializeMany(obj interface{}) (interface{}, error) {
+	payees, ok := obj.([]models.Payee)
+	if !ok {
+		return nil, errors.InvalidDataError()
+	}
+
+	response := make([]*responses.PayeeResponse, 0, len(payees))
+	for _, payee := range payees {
+		data, err := ps.serializeSingle(payee)
+		if err != nil {
+			return nil, err
+		}
+		response = append(response, data)
+	}
+	return response, nil
+}
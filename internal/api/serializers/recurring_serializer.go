@@ -0,0 +1,75 @@
+package serializers
+
+import (
+	"github.com/christo-andrew/haven/internal/api/responses"
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/errors"
+)
+
+type RecurringTransactionSerializer struct {
+	Data interface{}
+	many bool
+}
+
+func NewRecurringTransactionSerializer(data interface{}, many bool) *RecurringTransactionSerializer {
+	return &RecurringTransactionSerializer{
+		Data: data,
+		many: many,
+	}
+}
+
+func (rs RecurringTransactionSerializer) Serialize() (interface{}, error) {
+	switch rs.Data.(type) {
+	case []models.RecurringTransaction:
+		return rs.serializeMany(rs.Data)
+	case models.RecurringTransaction:
+		return rs.serializeSingle(rs.Data)
+	default:
+		return nil, errors.InvalidDataError()
+	}
+}
+
+func (rs RecurringTransactionSerializer) serializeSingle(obj interface{}) (*responses.RecurringTransactionResponse, error) {
+	recurring, ok := obj.(models.RecurringTransaction)
+	if !ok {
+		return nil, errors.InvalidDataError()
+	}
+
+	response := &responses.RecurringTransactionResponse{
+		ID:              recurring.ID,
+		Name:            recurring.Name,
+		AccountID:       recurring.AccountID,
+		Amount:          recurring.Amount,
+		Currency:        recurring.Currency,
+		Description:     recurring.Description,
+		Payee:           recurring.Payee,
+		Category:        recurring.Category.Name,
+		TransactionType: recurring.TransactionType.Name,
+		Recurrence:      recurring.Recurrence,
+		StartDate:       recurring.StartDate.Unix(),
+		AutoPost:        recurring.AutoPost,
+		Active:          recurring.Active,
+	}
+	if recurring.NextDate != nil {
+		nextDate := recurring.NextDate.Unix()
+		response.NextDate = &nextDate
+	}
+	return response, nil
+}
+
+func (rs RecurringTransactionSerializer) serializeMany(obj interface{}) (interface{}, error) {
+	recurringTransactions, ok := obj.([]models.RecurringTransaction)
+	if !ok {
+		return nil, errors.InvalidDataError()
+	}
+
+	response := make([]*responses.RecurringTransactionResponse, 0, len(recurringTransactions))
+	for _, recurring := range recurringTransactions {
+		data, err := rs.serializeSingle(recurring)
+		if err != nil {
+			return nil, err
+		}
+		response = append(response, data)
+	}
+	return response, nil
+}
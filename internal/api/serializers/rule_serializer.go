@@ -0,0 +1,79 @@
+package serializers
+
+import (
+	"github.com/christo-andrew/haven/internal/api/responses"
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/errors"
+)
+
+type RuleSerializer struct {
+	Data interface{}
+	many bool
+}
+
+func NewRuleSerializer(data interface{}, many bool) *RuleSerializer {
+	return &RuleSerializer{
+		Data: data,
+		many: many,
+	}
+}
+
+func (rs RuleSerializer) Serialize() (interface{}, error) {
+	switch rs.Data.(type) {
+	case []models.Rule:
+		return rs.serializeMany(rs.Data)
+	case models.Rule:
+		return rs.serializeSingle(rs.Data)
+	default:
+		return nil, errors.InvalidDataError()
+	}
+}
+
+func (rs RuleSerializer) serializeSingle(obj interface{}) (*responses.RuleResponse, error) {
+	rule, ok := obj.(models.Rule)
+	if !ok {
+		return nil, errors.InvalidDataError()
+	}
+
+	response := &responses.RuleResponse{
+		ID:             rule.ID,
+		Name:           rule.Name,
+		Priority:       rule.Priority,
+		Enabled:        rule.Enabled,
+		StopProcessing: rule.StopProcessing,
+		Conditions:     make([]responses.RuleConditionResponse, 0, len(rule.Conditions)),
+		Actions:        make([]responses.RuleActionResponse, 0, len(rule.Actions)),
+	}
+	for _, condition := range rule.Conditions {
+		response.Conditions = append(response.Conditions, responses.RuleConditionResponse{
+			Field:    condition.Field,
+			Operator: condition.Operator,
+			Value:    condition.Value,
+			ValueTo:  condition.ValueTo,
+		})
+	}
+	for _, action := range rule.Actions {
+		response.Actions = append(response.Actions, responses.RuleActionResponse{
+			Type:  action.Type,
+			Value: action.Value,
+		})
+	}
+	return response, nil
+}
+
+func (rs RuleSerializer) serializeMany(obj interface{}) (interface{}, error) {
+	rules, ok := obj.([]models.Rule)
+	if !ok {
+		return nil, errors.InvalidDataError()
+	}
+
+	response := make([]*responses.RuleResponse, 0, len(rules))
+	for _, rule := range rules {
+		data, err := rs.serializeSingle(rule)
+		if err != nil {
+			return nil, err
+		}
+		response = append(response, data)
+	}
+	return response, nil
+}
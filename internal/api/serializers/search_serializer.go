@@ -0,0 +1,64 @@
+package serializers
+
+import (
+	"github.com/christo-andrew/haven/internal/api/responses"
+	"github.com/christo-andrew/haven/pkg/errors"
+	"github.com/christo-andrew/haven/pkg/search"
+)
+
+type SearchResultSerializer struct {
+	Data interface{}
+	many bool
+}
+
+func NewSearchResultSerializer(data interface{}, many bool) *SearchResultSerializer {
+	return &SearchResultSerializer{
+		Data: data,
+		many: many,
+	}
+}
+
+func (ss SearchResultSerializer) Serialize() (interface{}, error) {
+	switch ss.Data.(type) {
+	case []search.Result:
+		return ss.serializeMany(ss.Data)
+	case search.Result:
+		return ss.serializeSingle(ss.Data)
+	default:
+		return nil, errors.InvalidDataError()
+	}
+}
+
+func (ss SearchResultSerializer) serializeSingle(obj interface{}) (*responses.SearchResultResponse, error) {
+	result, ok := obj.(search.Result)
+	if !ok {
+		return nil, errors.InvalidDataError()
+	}
+
+	snippets := make([]responses.SnippetResponse, 0, len(result.Snippets))
+	for _, snippet := range result.Snippets {
+		snippets = append(snippets, responses.SnippetResponse{Field: snippet.Field, Text: snippet.Text})
+	}
+	return &responses.SearchResultResponse{
+		Transaction: TransactionSerializer{}.serializeSingleTransaction(result.Transaction),
+		Score:       result.Score,
+		Snippets:    snippets,
+	}, nil
+}
+
+func (ss SearchResultSerializer) serializeMany(obj interface{}) (interface{}, error) {
+	results, ok := obj.([]search.Result)
+	if !ok {
+		return nil, errors.InvalidDataError()
+	}
+
+	response := make([]*responses.SearchResultResponse, 0, len(results))
+	for _, result := range results {
+		data, err := ss.serializeSingle(result)
+		if err != nil {
+			return nil, err
+		}
+		response = append(response, data)
+	}
+	return response, nil
+}
@@ -23,6 +23,8 @@ func (ts TransactionSerializer) Serialize() interface{} {
 		return ts.serializeTransactions()
 	case models.Transaction:
 		return ts.serializeTransaction()
+	case []models.TransactionSplit:
+		return ts.serializeSplits(ts.Data.([]models.TransactionSplit))
 	default:
 		return nil
 	}
@@ -45,6 +47,7 @@ func (ts TransactionSerializer) serializeSingleTransaction(tx models.Transaction
 		TransactionID:     tx.ID,
 		Amount:            tx.Amount,
 		Description:       tx.Description,
+		Notes:             tx.Notes,
 		Date:              tx.Date.Unix(),
 		AccountID:         tx.AccountID,
 		TransactionType:   tx.TransactionType.Name,
@@ -52,6 +55,28 @@ func (ts TransactionSerializer) serializeSingleTransaction(tx models.Transaction
 		Currency:          tx.Currency,
 		Reference:         tx.Reference,
 		Payee:             tx.Payee,
+		PayeeID:           tx.PayeeID,
+		RecurringID:       tx.RecurringID,
 		TransactionStatus: tx.TransactionStatus,
+		Splits:            ts.serializeSplits(tx.Splits),
 	}
 }
+
+func (ts TransactionSerializer) serializeSplits(splits []models.TransactionSplit) []responses.TransactionSplitResponse {
+	var response []responses.TransactionSplitResponse
+	for _, split := range splits {
+		var tags []responses.TagResponse
+		for _, tag := range split.Tags {
+			tags = append(tags, responses.TagResponse{}.FromTag(tag))
+		}
+		response = append(response, responses.TransactionSplitResponse{
+			ID:         split.ID,
+			Amount:     split.Amount,
+			CategoryID: split.CategoryID,
+			Category:   split.Category.Name,
+			Memo:       split.Memo,
+			Tags:       tags,
+		})
+	}
+	return response
+}
@@ -3,7 +3,9 @@ package api
 import (
 	"github.com/christo-andrew/haven/docs"
 	"github.com/christo-andrew/haven/internal/api/middleware"
+	"github.com/christo-andrew/haven/pkg/budgets"
 	"github.com/christo-andrew/haven/pkg/config"
+	"github.com/christo-andrew/haven/pkg/storage"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -11,17 +13,19 @@ import (
 )
 
 type Server struct {
-	app    *gin.Engine
-	Config *config.ServerConfig
+	app           *gin.Engine
+	Config        *config.ServerConfig
+	StorageConfig *config.StorageConfig
 }
 
 func NewApiServer(config *config.Config) *Server {
 	app := gin.Default()
-	return &Server{app: app, Config: &config.Server}
+	return &Server{app: app, Config: &config.Server, StorageConfig: &config.Storage}
 }
 
-func (s *Server) SetupRouter(db *gorm.DB) *gin.Engine {
+func (s *Server) SetupRouter(db *gorm.DB, store storage.Storage, alerter *budgets.Alerter) *gin.Engine {
 	s.app.Use(middleware.CorsMiddleware())
+	s.app.Use(middleware.RequestID())
 
 	api := s.app.Group("/api")
 	v1 := api.Group("/v1")
@@ -45,11 +49,20 @@ func (s *Server) SetupRouter(db *gorm.DB) *gin.Engine {
 
 	UsersRouterV1(v1.Group("/users"), db)
 	AuthRouterV1(v1.Group("/auth"), db)
-	AccountsRouterV1(v1.Group("/accounts", middleware.WithAuthUser()), db)
-	TransactionsRouterV1(v1.Group("/transactions", middleware.WithAuthUser()), db)
+	AccountsRouterV1(v1.Group("/accounts", middleware.WithAuthUser()), db, alerter)
+	TransactionsRouterV1(v1.Group("/transactions", middleware.WithAuthUser()), db, store, s.StorageConfig.MaxUploadSize, alerter)
 	CategoriesRouterV1(v1.Group("/categories", middleware.WithAuthUser()), db)
 	DataRouterV1(v1.Group("/data", middleware.WithAuthUser()), db)
 	BudgetsRouterV1(v1.Group("/budgets", middleware.WithAuthUser()), db)
+	EnvelopesRouterV1(v1.Group("/envelopes", middleware.WithAuthUser()), db)
+	GoalsRouterV1(v1.Group("/goals", middleware.WithAuthUser()), db)
+	RulesRouterV1(v1.Group("/rules", middleware.WithAuthUser()), db)
+	PayeesRouterV1(v1.Group("/payees", middleware.WithAuthUser()), db)
+	RecurringRouterV1(v1.Group("/recurring", middleware.WithAuthUser()), db)
+	SearchRouterV1(v1.Group("/search", middleware.WithAuthUser()), db)
+	TagsRouterV1(v1.Group("/tags", middleware.WithAuthUser()), db)
+	AuditRouterV1(v1.Group("/audit", middleware.WithAuthUser()), db)
+	NotificationsRouterV1(v1.Group("/notifications", middleware.WithAuthUser()), db)
 
 	return s.app
 }
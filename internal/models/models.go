@@ -4,6 +4,7 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"gorm.io/gorm"
 	"os"
+	"strings"
 	"time"
 )
 
@@ -23,6 +24,11 @@ type Account struct {
 	BaseAccountID   int           `json:"base_account_id"`
 	Transactions    []Transaction `gorm:"foreignKey:AccountID"`
 	Tags            []Tag         `gorm:"many2many:account_tags;"`
+	// InterestRate is the yearly rate charged on what is owed on a credit
+	// card or loan, in percent, and MinimumPayment what has to be paid on
+	// it each month.
+	InterestRate   float64 `json:"interest_rate"`
+	MinimumPayment float64 `json:"minimum_payment"`
 }
 
 type BankAccount struct {
@@ -35,59 +41,291 @@ type CreditCardAccount struct {
 	Account Account `gorm:"polymorphic:BaseAccount;"`
 }
 
+type LoanAccount struct {
+	gorm.Model
+	Account Account `gorm:"polymorphic:BaseAccount;"`
+}
+
 // Budget
 type Budget struct {
 	gorm.Model
-	Id           uint          `json:"id"`
-	Name         string        `json:"name"`
-	Description  string        `json:"description"`
-	Amount       float64       `json:"amount"`
-	UserId       uint          `json:"user_id"`
-	User         User          `json:"user"`
-	Category     Category      `json:"category"`
-	Transactions []Transaction `gorm:"foreignKey:BudgetID"`
-	SpentAmount  float64       `json:"spent_amount"`
-	StartDate    time.Time     `json:"start_date"`
-	EndDate      time.Time     `json:"end_date"`
-	CategoryID   uint          `json:"category_id"`
-	Tags         []Tag         `gorm:"many2many:budget_tags;"`
-	Status       string        `gorm:"default:'active'"`
-	Recurring    bool          `gorm:"default:false"`
-	Period       string        `gorm:"default:'monthly'"`
+	Id          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Amount      float64   `json:"amount"`
+	UserId      uint      `json:"user_id"`
+	User        User      `json:"user"`
+	Category    Category  `json:"category"`
+	SpentAmount float64   `json:"spent_amount" gorm:"-"`
+	StartDate   time.Time `json:"start_date"`
+	EndDate     time.Time `json:"end_date"`
+	CategoryID  uint      `json:"category_id"`
+	Tags        []Tag     `gorm:"many2many:budget_tags;"`
+	Status      string    `gorm:"default:'active'"`
+	Recurring   bool      `gorm:"default:false"`
+	Period      string    `gorm:"default:'monthly'"`
+	// PeriodDays is the length of a custom period.
+	PeriodDays int `json:"period_days"`
+	// Rollover is what a recurring budget carries from one period into the
+	// next: nothing, what was left, what was overspent, or both.
+	Rollover string `json:"rollover" gorm:"default:'none'"`
+	// Carryover is what the current period of a recurring budget carried
+	// over from the one before, loaded along with the spend.
+	Carryover float64 `json:"carryover" gorm:"-"`
+	// Categories are the lines of the budget. They are loaded along with
+	// the spend rather than by gorm.
+	Categories []BudgetCategory `json:"categories" gorm:"-"`
+}
+
+const (
+	BudgetPeriodWeekly    = "weekly"
+	BudgetPeriodMonthly   = "monthly"
+	BudgetPeriodQuarterly = "quarterly"
+	BudgetPeriodYearly    = "yearly"
+	BudgetPeriodCustom    = "custom"
+)
+
+const (
+	BudgetRolloverNone      = "none"
+	BudgetRolloverUnspent   = "unspent"
+	BudgetRolloverOverspent = "overspent"
+	BudgetRolloverBoth      = "both"
+)
+
+// Limit is what can be spent in the current period, the amount of the budget
+// adjusted by what was carried over.
+func (budget *Budget) Limit() float64 {
+	return budget.Amount + budget.Carryover
 }
 
 func (budget *Budget) RemainingAmount() float64 {
-	return budget.Amount - budget.SpentAmount
+	return budget.Limit() - budget.SpentAmount
 }
 
 func (budget *Budget) IsOverBudget() bool {
-	return budget.SpentAmount > budget.Amount
+	return budget.SpentAmount > budget.Limit()
 }
 
 func (budget *Budget) ProgressPercentage() float64 {
-	return (budget.SpentAmount / budget.Amount) * 100
+	if budget.Limit() <= 0 {
+		return 0
+	}
+	return (budget.SpentAmount / budget.Limit()) * 100
 }
 
 func (budget *Budget) IsOverDue() bool {
 	return budget.EndDate.Before(time.Now())
 }
 
+// DaysRemaining counts the days left in the budget, today and the end date
+// included. A budget that has not started yet has all of its days left.
 func (budget *Budget) DaysRemaining() int {
-	return int(time.Until(budget.EndDate).Hours() / 24)
+	from := time.Now().UTC().Truncate(24 * time.Hour)
+	if budget.StartDate.After(from) {
+		from = budget.StartDate
+	}
+	days := int(budget.EndDate.Sub(from).Hours()/24) + 1
+	if days < 0 {
+		return 0
+	}
+	return days
 }
 
+// DailyTarget is how much can be spent each remaining day to stay within the
+// budget.
 func (budget *Budget) DailyTarget() float64 {
-	return budget.Amount / (budget.EndDate.Sub(budget.StartDate).Hours() / 24)
+	days := budget.DaysRemaining()
+	if days == 0 || budget.RemainingAmount() <= 0 {
+		return 0
+	}
+	return budget.RemainingAmount() / float64(days)
 }
 
+// BudgetPeriod is one period of a recurring budget. The budget's own dates
+// are those of its open period; closed periods keep what was spent in them.
+type BudgetPeriod struct {
+	gorm.Model
+	BudgetID    uint      `json:"budget_id" gorm:"uniqueIndex:idx_budget_periods_budget_start"`
+	StartDate   time.Time `json:"start_date" gorm:"uniqueIndex:idx_budget_periods_budget_start"`
+	EndDate     time.Time `json:"end_date"`
+	Amount      float64   `json:"amount"`
+	Carryover   float64   `json:"carryover"`
+	SpentAmount float64   `json:"spent_amount"`
+	Closed      bool      `json:"closed"`
+}
+
+func (period *BudgetPeriod) Limit() float64 {
+	return period.Amount + period.Carryover
+}
+
+// BudgetCategory is a line of a budget with a limit of its own. The amount of
+// a budget with lines is the sum of its lines.
 type BudgetCategory struct {
 	gorm.Model
-	BudgetID   int       `json:"budget_id"`
-	Budget     *Budget   `json:"budget"`
-	CategoryID int       `json:"category_id"`
-	Category   *Category `json:"category"`
+	BudgetID    int       `json:"budget_id"`
+	Budget      *Budget   `json:"budget"`
+	CategoryID  int       `json:"category_id"`
+	Category    *Category `json:"category"`
+	Amount      float64   `json:"amount"`
+	SpentAmount float64   `json:"spent_amount" gorm:"-"`
+	// GoalID links the line to a goal, what is left of the line at the end
+	// of each period counting towards the goal.
+	GoalID *uint `json:"goal_id" gorm:"index"`
+}
+
+func (line *BudgetCategory) RemainingAmount() float64 {
+	return line.Amount - line.SpentAmount
+}
+
+func (line *BudgetCategory) IsOverBudget() bool {
+	return line.SpentAmount > line.Amount
+}
+
+func (line *BudgetCategory) ProgressPercentage() float64 {
+	if line.Amount == 0 {
+		return 0
+	}
+	return (line.SpentAmount / line.Amount) * 100
+}
+
+// Goal is an amount a user saves towards by a target date. The balances of
+// the linked accounts, the transactions carrying the goal's tag and what the
+// budget lines linked to the goal leave unspent all count towards it.
+type Goal struct {
+	gorm.Model
+	UserID       uint          `json:"user_id" gorm:"index"`
+	Name         string        `json:"name"`
+	Description  string        `json:"description"`
+	TargetAmount float64       `json:"target_amount"`
+	StartDate    time.Time     `json:"start_date"`
+	TargetDate   time.Time     `json:"target_date"`
+	TagID        *int          `json:"tag_id"`
+	Accounts     []GoalAccount `json:"accounts"`
+}
+
+// GoalAccount links a savings account to a goal.
+type GoalAccount struct {
+	gorm.Model
+	GoalID    uint `json:"goal_id" gorm:"uniqueIndex:idx_goal_accounts_goal_account"`
+	AccountID int  `json:"account_id" gorm:"uniqueIndex:idx_goal_accounts_goal_account"`
+}
+
+// BudgetTemplate is a set of budget lines a user saved to start budgets
+// from. A line has either a fixed amount or a percentage of the income the
+// budget is drafted for.
+type BudgetTemplate struct {
+	gorm.Model
+	UserID      uint                 `json:"user_id" gorm:"index"`
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	Period      string               `json:"period" gorm:"default:'monthly'"`
+	Rollover    string               `json:"rollover" gorm:"default:'none'"`
+	Lines       []BudgetTemplateLine `json:"lines"`
+}
+
+type BudgetTemplateLine struct {
+	gorm.Model
+	BudgetTemplateID uint    `json:"budget_template_id" gorm:"index"`
+	CategoryID       int     `json:"category_id"`
+	Amount           float64 `json:"amount"`
+	Percentage       float64 `json:"percentage"`
+}
+
+// Envelope is a category a user budgets zero-based. Each month income is
+// assigned to envelopes until there is nothing left to budget, and spending
+// in the category, or in the categories below it that are not envelopes of
+// their own, comes out of the envelope.
+type Envelope struct {
+	gorm.Model
+	UserID     uint      `json:"user_id" gorm:"uniqueIndex:idx_envelopes_user_category"`
+	CategoryID int       `json:"category_id" gorm:"uniqueIndex:idx_envelopes_user_category"`
+	Category   *Category `json:"category" gorm:"-"`
+	// Cover is what happens to an envelope that ends a month overspent:
+	// either the next month's money to budget covers it, or the envelope
+	// starts the next month in the red.
+	Cover string `json:"cover" gorm:"default:'to_be_budgeted'"`
+}
+
+const (
+	EnvelopeCoverToBeBudgeted = "to_be_budgeted"
+	EnvelopeCoverCarry        = "carry"
+)
+
+// EnvelopeAssignment is what a user assigned to an envelope for a month,
+// written as 2006-01.
+type EnvelopeAssignment struct {
+	gorm.Model
+	EnvelopeID uint    `json:"envelope_id" gorm:"uniqueIndex:idx_envelope_assignments_envelope_month"`
+	Month      string  `json:"month" gorm:"size:7;uniqueIndex:idx_envelope_assignments_envelope_month"`
+	Amount     float64 `json:"amount"`
+}
+
+// BudgetAlert notifies a user when a budget, or one of its lines, reaches a
+// threshold. A percentage alert fires once spending reaches Threshold percent
+// of the limit; a projected alert fires once spending, carried on at its
+// current daily rate to the end of the budget, would reach it. Each alert
+// fires at most once per period, FiredPeriod being the start of the period
+// it last fired in. PendingChannels are the channels it could not be
+// delivered through when it fired, tried again at the next check.
+type BudgetAlert struct {
+	gorm.Model
+	UserID          uint       `json:"user_id" gorm:"index"`
+	BudgetID        uint       `json:"budget_id" gorm:"index"`
+	CategoryID      *int       `json:"category_id"`
+	Type            string     `json:"type"`
+	Threshold       float64    `json:"threshold"`
+	Channels        string     `json:"channels"`
+	WebhookURL      string     `json:"webhook_url"`
+	Active          bool       `json:"active" gorm:"default:true"`
+	FiredPeriod     *time.Time `json:"fired_period"`
+	PendingChannels string     `json:"pending_channels"`
+}
+
+const (
+	BudgetAlertPercentage = "percentage"
+	BudgetAlertProjected  = "projected"
+)
+
+// ChannelList returns the channels an alert is delivered through.
+func (alert *BudgetAlert) ChannelList() []string {
+	if alert.Channels == "" {
+		return nil
+	}
+	return strings.Split(alert.Channels, ",")
 }
 
+// PendingChannelList returns the channels an alert is still to be delivered
+// through.
+func (alert *BudgetAlert) PendingChannelList() []string {
+	if alert.PendingChannels == "" {
+		return nil
+	}
+	return strings.Split(alert.PendingChannels, ",")
+}
+
+// Notification is an entry in a user's in-app notification feed.
+type Notification struct {
+	gorm.Model
+	UserID   uint       `json:"user_id" gorm:"index"`
+	Kind     string     `json:"kind"`
+	Title    string     `json:"title"`
+	Body     string     `json:"body"`
+	BudgetID *uint      `json:"budget_id"`
+	ReadAt   *time.Time `json:"read_at"`
+}
+
+// DefaultCategoryName is the category transactions get when nothing better is
+// known about them.
+const DefaultCategoryName = "General"
+
+// Category groups transactions. Categories form a tree through ParentID, so
+// that "Groceries" and "Restaurants" can both sit under "Food"; top level
+// categories have no parent.
+//
+// System categories are the seeded defaults every user sees and personalises
+// through CategoryOverride. Other categories belong to the user that created
+// them and are only visible to that user. Hidden is not stored, it is set
+// when a user's overrides are applied.
 type Category struct {
 	gorm.Model
 	ID          int    `json:"id"`
@@ -95,6 +333,25 @@ type Category struct {
 	Description string `json:"description"`
 	Context     string `json:"context"`
 	ContextType string `json:"context_type"`
+	ParentID    *int   `json:"parent_id" gorm:"index"`
+	UserID      *uint  `json:"user_id" gorm:"index"`
+	IsSystem    bool   `json:"is_system" gorm:"default:false"`
+	Color       string `json:"color"`
+	Icon        string `json:"icon"`
+	Hidden      bool   `json:"hidden" gorm:"-"`
+}
+
+// CategoryOverride is how a user personalises a category without changing
+// it for anyone else. Empty fields keep the category's own value.
+type CategoryOverride struct {
+	gorm.Model
+	ID         int    `json:"id"`
+	UserID     uint   `json:"user_id" gorm:"uniqueIndex:idx_category_overrides_user_category"`
+	CategoryID int    `json:"category_id" gorm:"uniqueIndex:idx_category_overrides_user_category"`
+	Name       string `json:"name"`
+	Hidden     bool   `json:"hidden"`
+	Color      string `json:"color"`
+	Icon       string `json:"icon"`
 }
 
 type RealEstateAccount struct {
@@ -102,31 +359,172 @@ type RealEstateAccount struct {
 	Account Account `gorm:"polymorphic:BaseAccount;"`
 }
 
+// Tag belongs to the user who created it, who has one tag per name. Tags
+// without a user date from before tags had owners and are visible to all.
 type Tag struct {
 	gorm.Model
-	ID   int    `json:"id" gorm:"primaryKey"`
-	Name string `json:"name"`
+	ID     int    `json:"id" gorm:"primaryKey"`
+	Name   string `json:"name" gorm:"size:191;uniqueIndex:idx_tags_user_name,priority:2"`
+	UserID *uint  `json:"user_id" gorm:"uniqueIndex:idx_tags_user_name,priority:1"`
 }
 
 type Transaction struct {
 	gorm.Model
-	ID                int       `json:"id"`
-	Amount            float64   `json:"amount"`
-	Currency          string    `json:"currency"`
-	Payee             string    `json:"payee"`
-	Reference         string    `json:"reference"`
-	Date              time.Time `json:"date"`
-	Description       string    `json:"description"`
-	AccountID         int       `json:"account_id"`
-	Account           Account   `gorm:"foreignKey:AccountID"`
-	CategoryID        int       `json:"category_id"`
-	Category          Category  `gorm:"foreignKey:CategoryID"`
-	TransactionTypeID int       `json:"transaction_type_id"`
-	TransactionType   Category  `gorm:"foreignKey:TransactionTypeID"`
-	TransactionStatus string    `json:"transaction_status"`
-	Tags              []Tag     `gorm:"many2many:transaction_tags;"`
+	ID                int                `json:"id"`
+	Amount            float64            `json:"amount"`
+	Currency          string             `json:"currency"`
+	Payee             string             `json:"payee"`
+	Reference         string             `json:"reference"`
+	Date              time.Time          `json:"date"`
+	Description       string             `json:"description"`
+	Notes             string             `json:"notes" gorm:"type:text"`
+	AccountID         int                `json:"account_id"`
+	Account           Account            `gorm:"foreignKey:AccountID"`
+	CategoryID        int                `json:"category_id"`
+	Category          Category           `gorm:"foreignKey:CategoryID"`
+	TransactionTypeID int                `json:"transaction_type_id"`
+	TransactionType   Category           `gorm:"foreignKey:TransactionTypeID"`
+	TransactionStatus string             `json:"transaction_status"`
+	Tags              []Tag              `gorm:"many2many:transaction_tags;"`
+	Splits            []TransactionSplit `gorm:"foreignKey:TransactionID"`
+	IsTransfer        bool               `json:"is_transfer"`
+	PayeeID           *int               `json:"payee_id"`
+	RecurringID       *int               `json:"recurring_id"`
+}
+
+const (
+	TransactionStatusPending    = "pending"
+	TransactionStatusCleared    = "cleared"
+	TransactionStatusReconciled = "reconciled"
+	TransactionStatusVoid       = "void"
+)
+
+// TransactionStatuses are the valid statuses of a transaction.
+var TransactionStatuses = []string{TransactionStatusPending, TransactionStatusCleared, TransactionStatusReconciled, TransactionStatusVoid}
+
+// transactionTransitions lists the statuses a transaction can move to. Pending
+// transactions clear once the bank posts them and cleared ones are reconciled
+// against a statement. Anything that is not yet reconciled can be voided, a
+// reconciliation can be undone, and a void is final.
+var transactionTransitions = map[string][]string{
+	TransactionStatusPending:    {TransactionStatusCleared, TransactionStatusVoid},
+	TransactionStatusCleared:    {TransactionStatusReconciled, TransactionStatusVoid},
+	TransactionStatusReconciled: {TransactionStatusCleared},
+}
+
+func IsTransactionStatus(status string) bool {
+	for _, known := range TransactionStatuses {
+		if status == known {
+			return true
+		}
+	}
+	return false
+}
+
+// BeforeCreate defaults new transactions to cleared.
+func (transaction *Transaction) BeforeCreate(tx *gorm.DB) error {
+	if transaction.TransactionStatus == "" {
+		transaction.TransactionStatus = TransactionStatusCleared
+	}
+	return nil
+}
+
+// Status returns the status of the transaction. Transactions created before
+// statuses were tracked count as cleared.
+func (transaction *Transaction) Status() string {
+	if transaction.TransactionStatus == "" {
+		return TransactionStatusCleared
+	}
+	return transaction.TransactionStatus
+}
+
+func (transaction *Transaction) CanTransitionTo(status string) bool {
+	for _, next := range transactionTransitions[transaction.Status()] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (transaction *Transaction) IsPending() bool {
+	return transaction.Status() == TransactionStatusPending
+}
+
+func (transaction *Transaction) IsVoid() bool {
+	return transaction.Status() == TransactionStatusVoid
+}
+
+func (transaction *Transaction) HasSplits() bool {
+	return len(transaction.Splits) > 0
+}
+
+// Direction reports whether money left the account ("debit") or came into it
+// ("credit"). The transaction type wins when it is known, otherwise the sign of
+// the amount decides.
+func (transaction *Transaction) Direction() string {
+	switch strings.ToLower(transaction.TransactionType.Name) {
+	case "debit", "withdraw":
+		return "debit"
+	case "credit", "deposit":
+		return "credit"
+	}
+	if transaction.Amount < 0 {
+		return "debit"
+	}
+	return "credit"
+}
+
+// TransactionSplit is one line of a transaction that has been divided across
+// several categories. The amounts of all splits add up to the parent amount.
+type TransactionSplit struct {
+	gorm.Model
+	ID            int      `json:"id"`
+	TransactionID int      `json:"transaction_id"`
+	Amount        float64  `json:"amount"`
+	CategoryID    int      `json:"category_id"`
+	Category      Category `gorm:"foreignKey:CategoryID"`
+	Memo          string   `json:"memo"`
+	Tags          []Tag    `gorm:"many2many:transaction_split_tags;"`
+}
+
+// Attachment is a file such as a receipt or an invoice kept with a
+// transaction. The content lives in the blob store under StorageKey.
+type Attachment struct {
+	gorm.Model
+	ID            int    `json:"id"`
+	TransactionID int    `json:"transaction_id"`
+	UserID        uint   `json:"user_id"`
+	FileName      string `json:"file_name"`
+	ContentType   string `json:"content_type"`
+	Size          int64  `json:"size"`
+	Checksum      string `json:"checksum"`
+	StorageKey    string `json:"-"`
+}
+
+// AuditLog is an append-only record of a change to an account, transaction,
+// budget, category or tag. Changes is a JSON object with the old and new
+// value of each column that changed. ActorID is empty for changes made by
+// background jobs. Merges also get an entry of their own on the record the
+// others were merged into.
+type AuditLog struct {
+	ID        int       `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+	Entity    string    `json:"entity" gorm:"size:32;index:idx_audit_logs_entity"`
+	EntityID  int       `json:"entity_id" gorm:"index:idx_audit_logs_entity"`
+	Action    string    `json:"action" gorm:"size:16"`
+	ActorID   *uint     `json:"actor_id" gorm:"index"`
+	RequestID string    `json:"request_id" gorm:"size:64;index"`
+	Changes   string    `json:"changes" gorm:"type:text"`
 }
 
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+	AuditActionMerge  = "merge"
+)
+
 func TransactionTypeColors() map[string]string {
 	return map[string]string{
 		"debit":    "#FDA403",
@@ -136,6 +534,141 @@ func TransactionTypeColors() map[string]string {
 	}
 }
 
+// Payees
+type Payee struct {
+	gorm.Model
+	ID                int          `json:"id"`
+	UserID            uint         `json:"user_id"`
+	Name              string       `json:"name"`
+	DefaultCategoryID *int         `json:"default_category_id"`
+	DefaultCategory   *Category    `gorm:"foreignKey:DefaultCategoryID"`
+	Aliases           []PayeeAlias `gorm:"foreignKey:PayeeID"`
+}
+
+// PayeeAlias is another name a payee appears under. Pattern aliases are
+// regular expressions matched against the raw transaction description.
+type PayeeAlias struct {
+	gorm.Model
+	ID        int    `json:"id"`
+	PayeeID   int    `json:"payee_id"`
+	Name      string `json:"name"`
+	IsPattern bool   `json:"is_pattern"`
+}
+
+// Recurring transactions
+
+// RecurringTransaction is a template that posts a transaction on every
+// occurrence of its recurrence rule. NextDate is the next occurrence that has
+// not been posted yet and is nil once the rule has ended.
+type RecurringTransaction struct {
+	gorm.Model
+	ID                int        `json:"id"`
+	UserID            uint       `json:"user_id"`
+	Name              string     `json:"name"`
+	AccountID         int        `json:"account_id"`
+	Account           Account    `gorm:"foreignKey:AccountID"`
+	Amount            float64    `json:"amount"`
+	Currency          string     `json:"currency"`
+	Description       string     `json:"description"`
+	Payee             string     `json:"payee"`
+	CategoryID        int        `json:"category_id"`
+	Category          Category   `gorm:"foreignKey:CategoryID"`
+	TransactionTypeID int        `json:"transaction_type_id"`
+	TransactionType   Category   `gorm:"foreignKey:TransactionTypeID"`
+	Recurrence        string     `json:"recurrence"`
+	StartDate         time.Time  `json:"start_date"`
+	NextDate          *time.Time `json:"next_date"`
+	AutoPost          bool       `json:"auto_post"`
+	Active            bool       `json:"active"`
+	SubscriptionKey   string     `json:"subscription_key"`
+}
+
+// Transaction builds the transaction posted for the occurrence on the given
+// date. Auto-posted occurrences are cleared, the others wait for the user to
+// confirm them.
+func (recurring *RecurringTransaction) Transaction(date time.Time) *Transaction {
+	recurringId := recurring.ID
+	status := TransactionStatusPending
+	if recurring.AutoPost {
+		status = TransactionStatusCleared
+	}
+	return &Transaction{
+		AccountID:         recurring.AccountID,
+		Amount:            recurring.Amount,
+		Currency:          recurring.Currency,
+		Date:              date,
+		Description:       recurring.Description,
+		Payee:             recurring.Payee,
+		CategoryID:        recurring.CategoryID,
+		Category:          recurring.Category,
+		TransactionTypeID: recurring.TransactionTypeID,
+		TransactionType:   recurring.TransactionType,
+		TransactionStatus: status,
+		RecurringID:       &recurringId,
+	}
+}
+
+// Rules
+type Rule struct {
+	gorm.Model
+	ID             int             `json:"id"`
+	UserID         uint            `json:"user_id"`
+	Name           string          `json:"name"`
+	Priority       int             `json:"priority"`
+	Enabled        bool            `json:"enabled"`
+	StopProcessing bool            `json:"stop_processing"`
+	Conditions     []RuleCondition `gorm:"foreignKey:RuleID"`
+	Actions        []RuleAction    `gorm:"foreignKey:RuleID"`
+}
+
+type RuleCondition struct {
+	gorm.Model
+	ID       int    `json:"id"`
+	RuleID   int    `json:"rule_id"`
+	Field    string `json:"field"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+	ValueTo  string `json:"value_to"`
+}
+
+// RuleAction changes a transaction when its rule matches. Category, type and
+// tag actions store the id of the record they assign in Value.
+type RuleAction struct {
+	gorm.Model
+	ID     int    `json:"id"`
+	RuleID int    `json:"rule_id"`
+	Type   string `json:"type"`
+	Value  string `json:"value"`
+}
+
+const (
+	RuleFieldDescription = "description"
+	RuleFieldPayee       = "payee"
+	RuleFieldAmount      = "amount"
+	RuleFieldAccount     = "account"
+	RuleFieldDirection   = "direction"
+)
+
+const (
+	RuleOperatorMatches    = "matches"
+	RuleOperatorContains   = "contains"
+	RuleOperatorEquals     = "equals"
+	RuleOperatorStartsWith = "starts_with"
+	RuleOperatorGt         = "gt"
+	RuleOperatorGte        = "gte"
+	RuleOperatorLt         = "lt"
+	RuleOperatorLte        = "lte"
+	RuleOperatorBetween    = "between"
+)
+
+const (
+	RuleActionSetCategory  = "set_category"
+	RuleActionSetType      = "set_type"
+	RuleActionSetPayee     = "set_payee"
+	RuleActionAddTag       = "add_tag"
+	RuleActionMarkTransfer = "mark_transfer"
+)
+
 type User struct {
 	ID        uint   `json:"id" gorm:"primaryKey"`
 	FirstName string `json:"first_name"`
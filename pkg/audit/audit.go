@@ -0,0 +1,329 @@
+package audit
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrAppendOnly is returned when something tries to change or remove audit
+// log entries.
+var ErrAppendOnly = stderrors.New("the audit log is append-only")
+
+// ignoredColumns change on every write and would only add noise to a diff.
+var ignoredColumns = map[string]bool{"created_at": true, "updated_at": true}
+
+const beforeKey = "audit:before"
+
+// Change is the old and new value of one column.
+type Change struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+type auditor struct {
+	// entities maps the table of each audited model to the entity name used
+	// in the log, e.g. transactions to transaction.
+	entities map[string]string
+	table    string
+}
+
+// Register adds gorm callbacks that write an audit log entry for every
+// create, update and delete of the given models. The affected rows are read
+// before and after each statement so that the entry holds a diff of what
+// actually changed, and entries are written in the same database transaction
+// as the change itself. Only statements built through gorm are seen; raw SQL
+// is not audited.
+func Register(db *gorm.DB, audited ...interface{}) error {
+	auditor := &auditor{entities: make(map[string]string)}
+	for _, model := range audited {
+		statement := &gorm.Statement{DB: db}
+		if err := statement.Parse(model); err != nil {
+			return err
+		}
+		auditor.entities[statement.Schema.Table] = strings.ToLower(statement.Schema.Name)
+	}
+	statement := &gorm.Statement{DB: db}
+	if err := statement.Parse(&models.AuditLog{}); err != nil {
+		return err
+	}
+	auditor.table = statement.Schema.Table
+
+	callbacks := db.Callback()
+	return stderrors.Join(
+		callbacks.Create().After("gorm:create").Register("audit:after_create", auditor.afterCreate),
+		callbacks.Update().Before("gorm:update").Register("audit:before_update", auditor.before),
+		callbacks.Update().After("gorm:update").Register("audit:after_update", auditor.afterUpdate),
+		callbacks.Delete().Before("gorm:delete").Register("audit:before_delete", auditor.before),
+		callbacks.Delete().After("gorm:delete").Register("audit:after_delete", auditor.afterDelete),
+	)
+}
+
+func (auditor *auditor) entity(tx *gorm.DB) (string, bool) {
+	if tx.Statement.Schema == nil {
+		return "", false
+	}
+	entity, ok := auditor.entities[tx.Statement.Table]
+	return entity, ok
+}
+
+// before refuses changes to the audit log and remembers the rows an update
+// or delete is about to touch.
+func (auditor *auditor) before(tx *gorm.DB) {
+	if tx.Error != nil {
+		return
+	}
+	if tx.Statement.Table == auditor.table {
+		tx.AddError(ErrAppendOnly)
+		return
+	}
+	if _, ok := auditor.entity(tx); !ok {
+		return
+	}
+	rows, err := auditor.snapshot(tx, nil)
+	if err != nil {
+		tx.AddError(err)
+		return
+	}
+	tx.InstanceSet(beforeKey, rows)
+}
+
+// afterCreate logs created rows as they were stored, defaults included.
+// Associations are saved with ON CONFLICT DO NOTHING; when nothing was
+// inserted the rows already existed and are not logged again.
+func (auditor *auditor) afterCreate(tx *gorm.DB) {
+	entity, ok := auditor.entity(tx)
+	if !ok || tx.Error != nil || tx.Statement.RowsAffected == 0 {
+		return
+	}
+	ids := primaryKeys(tx.Statement)
+	if len(ids) == 0 {
+		return
+	}
+	rows, err := auditor.snapshot(tx, ids)
+	if err != nil {
+		tx.AddError(err)
+		return
+	}
+	var entries []models.AuditLog
+	for _, row := range rows {
+		entry, ok := newEntry(tx, entity, models.AuditActionCreate, row, nil, row)
+		if ok {
+			entries = append(entries, entry)
+		}
+	}
+	auditor.write(tx, entries)
+}
+
+func (auditor *auditor) afterUpdate(tx *gorm.DB) {
+	entity, ok := auditor.entity(tx)
+	if !ok || tx.Error != nil || tx.Statement.RowsAffected == 0 {
+		return
+	}
+	before := beforeRows(tx)
+	if len(before) == 0 {
+		return
+	}
+	pk := tx.Statement.Schema.PrioritizedPrimaryField
+	ids := make([]interface{}, 0, len(before))
+	for _, row := range before {
+		ids = append(ids, row[pk.DBName])
+	}
+	after, err := auditor.snapshot(tx, ids)
+	if err != nil {
+		tx.AddError(err)
+		return
+	}
+	afterByKey := make(map[string]map[string]interface{}, len(after))
+	for _, row := range after {
+		afterByKey[fmt.Sprint(row[pk.DBName])] = row
+	}
+	var entries []models.AuditLog
+	for _, row := range before {
+		entry, ok := newEntry(tx, entity, models.AuditActionUpdate, row, row, afterByKey[fmt.Sprint(row[pk.DBName])])
+		if ok {
+			entries = append(entries, entry)
+		}
+	}
+	auditor.write(tx, entries)
+}
+
+func (auditor *auditor) afterDelete(tx *gorm.DB) {
+	entity, ok := auditor.entity(tx)
+	if !ok || tx.Error != nil || tx.Statement.RowsAffected == 0 {
+		return
+	}
+	var entries []models.AuditLog
+	for _, row := range beforeRows(tx) {
+		entry, ok := newEntry(tx, entity, models.AuditActionDelete, row, row, nil)
+		if ok {
+			entries = append(entries, entry)
+		}
+	}
+	auditor.write(tx, entries)
+}
+
+// Record writes a log entry for a change the callbacks cannot see, such as
+// relinking tags or merging records, with the actor and request taken from
+// the database session's context.
+func Record(db *gorm.DB, entity string, entityID int, action string, changes map[string]Change) error {
+	encoded, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	ctx := db.Statement.Context
+	entry := models.AuditLog{
+		Entity:    entity,
+		EntityID:  entityID,
+		Action:    action,
+		RequestID: RequestID(ctx),
+		Changes:   string(encoded),
+	}
+	if actor, ok := Actor(ctx); ok {
+		entry.ActorID = &actor
+	}
+	return db.Session(&gorm.Session{NewDB: true}).Create(&entry).Error
+}
+
+func (auditor *auditor) write(tx *gorm.DB, entries []models.AuditLog) {
+	if len(entries) == 0 {
+		return
+	}
+	if err := tx.Session(&gorm.Session{NewDB: true}).Create(&entries).Error; err != nil {
+		tx.AddError(err)
+	}
+}
+
+// snapshot reads the rows a statement applies to. With ids it reads those
+// rows, otherwise it uses the statement's conditions and the primary key of
+// the value it was given.
+func (auditor *auditor) snapshot(tx *gorm.DB, ids []interface{}) ([]map[string]interface{}, error) {
+	statement := tx.Statement
+	pk := statement.Schema.PrioritizedPrimaryField
+	if pk == nil {
+		return nil, nil
+	}
+	query := tx.Session(&gorm.Session{NewDB: true, SkipHooks: true}).
+		Model(reflect.New(statement.Schema.ModelType).Interface())
+	if statement.Unscoped || ids != nil {
+		query = query.Unscoped()
+	}
+	if ids == nil {
+		where, hasWhere := statement.Clauses["WHERE"].Expression.(clause.Where)
+		ids = primaryKeys(statement)
+		if !hasWhere && len(ids) == 0 && !statement.AllowGlobalUpdate {
+			// gorm refuses the statement anyway.
+			return nil, nil
+		}
+		if hasWhere {
+			query = query.Clauses(clause.Where{Exprs: where.Exprs})
+		}
+	}
+	if len(ids) > 0 {
+		query = query.Where(clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: pk.DBName}, Values: ids})
+	}
+	var rows []map[string]interface{}
+	err := query.Find(&rows).Error
+	return rows, err
+}
+
+func beforeRows(tx *gorm.DB) []map[string]interface{} {
+	value, ok := tx.InstanceGet(beforeKey)
+	if !ok {
+		return nil
+	}
+	rows, _ := value.([]map[string]interface{})
+	return rows
+}
+
+// primaryKeys returns the primary keys of the value a statement was given,
+// whether a single model or a slice of them.
+func primaryKeys(statement *gorm.Statement) []interface{} {
+	pk := statement.Schema.PrioritizedPrimaryField
+	if pk == nil {
+		return nil
+	}
+	var ids []interface{}
+	add := func(value reflect.Value) {
+		value = reflect.Indirect(value)
+		if value.Kind() != reflect.Struct || value.Type() != statement.Schema.ModelType {
+			return
+		}
+		if id, zero := pk.ValueOf(statement.Context, value); !zero {
+			ids = append(ids, id)
+		}
+	}
+	switch statement.ReflectValue.Kind() {
+	case reflect.Struct:
+		add(statement.ReflectValue)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < statement.ReflectValue.Len(); i++ {
+			add(statement.ReflectValue.Index(i))
+		}
+	}
+	return ids
+}
+
+// newEntry builds the log entry for one row. Updates that did not change
+// anything are skipped.
+func newEntry(tx *gorm.DB, entity string, action string, row, before, after map[string]interface{}) (models.AuditLog, bool) {
+	changes := diff(before, after)
+	if action == models.AuditActionUpdate && len(changes) == 0 {
+		return models.AuditLog{}, false
+	}
+	encoded, err := json.Marshal(changes)
+	if err != nil {
+		tx.AddError(err)
+		return models.AuditLog{}, false
+	}
+	pk := tx.Statement.Schema.PrioritizedPrimaryField
+	entityID, _ := strconv.Atoi(fmt.Sprint(row[pk.DBName]))
+	entry := models.AuditLog{
+		Entity:    entity,
+		EntityID:  entityID,
+		Action:    action,
+		RequestID: RequestID(tx.Statement.Context),
+		Changes:   string(encoded),
+	}
+	if actor, ok := Actor(tx.Statement.Context); ok {
+		entry.ActorID = &actor
+	}
+	return entry, true
+}
+
+// diff compares two versions of a row. A missing version, as for creates and
+// deletes, reports every column.
+func diff(before, after map[string]interface{}) map[string]Change {
+	changes := make(map[string]Change)
+	for column, value := range after {
+		if ignoredColumns[column] {
+			continue
+		}
+		old, existed := before[column]
+		if !existed || !equal(old, value) {
+			changes[column] = Change{Old: old, New: value}
+		}
+	}
+	for column, value := range before {
+		if _, ok := after[column]; !ok && !ignoredColumns[column] {
+			changes[column] = Change{Old: value, New: nil}
+		}
+	}
+	return changes
+}
+
+func equal(a, b interface{}) bool {
+	if at, ok := a.(time.Time); ok {
+		bt, ok := b.(time.Time)
+		return ok && at.Equal(bt)
+	}
+	return reflect.DeepEqual(a, b)
+}
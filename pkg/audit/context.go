@@ -0,0 +1,32 @@
+package audit
+
+import "context"
+
+type contextKey int
+
+const (
+	actorKey contextKey = iota
+	requestIDKey
+)
+
+// WithActor records the user making changes in a context.
+func WithActor(ctx context.Context, userID uint) context.Context {
+	return context.WithValue(ctx, actorKey, userID)
+}
+
+// WithRequestID records the request a change is made for in a context.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// Actor returns the user making changes, if there is one. Changes made by
+// background jobs have no actor.
+func Actor(ctx context.Context) (uint, bool) {
+	userID, ok := ctx.Value(actorKey).(uint)
+	return userID, ok
+}
+
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
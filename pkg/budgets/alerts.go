@@ -0,0 +1,241 @@
+package budgets
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/notifications"
+	"github.com/christo-andrew/haven/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// Alerter checks the alerts of budgets and sends the ones that fire.
+type Alerter struct {
+	db       *gorm.DB
+	tracker  *Tracker
+	notifier *notifications.Notifier
+
+	mutex sync.Mutex
+	// queued holds the users with a check running in the background, and
+	// whether another check was asked for while it ran.
+	queued  map[uint]bool
+	running sync.WaitGroup
+}
+
+func NewAlerter(db *gorm.DB, notifier *notifications.Notifier) *Alerter {
+	return &Alerter{db: db, tracker: NewTracker(db), notifier: notifier, queued: make(map[uint]bool)}
+}
+
+// CheckLater checks the alerts of a user in the background, for when their
+// transactions have just changed. A user has at most one check running at a
+// time; asking again while it runs checks once more when it is done, however
+// many writes came in meanwhile.
+func (alerter *Alerter) CheckLater(userId uint) {
+	alerter.mutex.Lock()
+	defer alerter.mutex.Unlock()
+	if _, running := alerter.queued[userId]; running {
+		alerter.queued[userId] = true
+		return
+	}
+	alerter.queued[userId] = false
+	alerter.running.Add(1)
+	go alerter.checkQueued(userId)
+}
+
+func (alerter *Alerter) checkQueued(userId uint) {
+	defer alerter.running.Done()
+	for {
+		if err := alerter.Check(userId, time.Now()); err != nil {
+			log.Printf("checking budget alerts of user %d: %v", userId, err)
+		}
+		alerter.mutex.Lock()
+		again := alerter.queued[userId]
+		if !again {
+			delete(alerter.queued, userId)
+		} else {
+			alerter.queued[userId] = false
+		}
+		alerter.mutex.Unlock()
+		if !again {
+			return
+		}
+	}
+}
+
+// Wait waits for the checks running in the background to finish.
+func (alerter *Alerter) Wait() {
+	alerter.running.Wait()
+}
+
+// CheckAll checks the alerts of every user that has any.
+func (alerter *Alerter) CheckAll(now time.Time) error {
+	var userIds []uint
+	err := alerter.db.Model(&models.BudgetAlert{}).Where("active = ?", true).Distinct().Pluck("user_id", &userIds).Error
+	if err != nil {
+		return err
+	}
+	for _, userId := range userIds {
+		if err := alerter.Check(userId, now); err != nil {
+			log.Printf("checking budget alerts of user %d: %v", userId, err)
+		}
+	}
+	return nil
+}
+
+// Check checks the active alerts of a user's budgets that are running now and
+// sends the ones that fire and have not fired yet in the budget's current
+// period. Channels an alert could not be delivered through are kept and tried
+// again at the next check that finds the alert firing in the same period.
+func (alerter *Alerter) Check(userId uint, now time.Time) error {
+	var alerts []models.BudgetAlert
+	if err := alerter.db.Where("user_id = ? AND active = ?", userId, true).Find(&alerts).Error; err != nil {
+		return err
+	}
+	if len(alerts) == 0 {
+		return nil
+	}
+	budgetIds := make([]uint, len(alerts))
+	for i, alert := range alerts {
+		budgetIds[i] = alert.BudgetID
+	}
+	var userBudgets []models.Budget
+	if err := alerter.db.Where("user_id = ? AND id IN ?", userId, budgetIds).Find(&userBudgets).Error; err != nil {
+		return err
+	}
+	filled := make([]*models.Budget, len(userBudgets))
+	byId := make(map[uint]*models.Budget, len(userBudgets))
+	for i := range userBudgets {
+		filled[i] = &userBudgets[i]
+		byId[userBudgets[i].ID] = &userBudgets[i]
+	}
+	if err := alerter.tracker.Fill(filled...); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, alert := range alerts {
+		budget, ok := byId[alert.BudgetID]
+		if !ok || now.Before(budget.StartDate) || now.After(budget.EndDate.AddDate(0, 0, 1)) {
+			continue
+		}
+		message, fires := evaluate(alert, budget, now)
+		var channels []string
+		if alert.PendingChannels != "" && alert.FiredPeriod != nil && alert.FiredPeriod.Equal(budget.StartDate) {
+			// The alert fired in this period but could not be delivered
+			// through some channels; try those again while it still fires.
+			if !fires {
+				continue
+			}
+			result := alerter.db.Model(&models.BudgetAlert{}).
+				Where("id = ? AND pending_channels = ?", alert.ID, alert.PendingChannels).
+				Update("pending_channels", "")
+			if result.Error != nil {
+				errs = append(errs, result.Error)
+				continue
+			}
+			if result.RowsAffected == 0 {
+				continue
+			}
+			channels = alert.PendingChannelList()
+		} else {
+			if !fires {
+				continue
+			}
+			result := alerter.db.Model(&models.BudgetAlert{}).
+				Where("id = ? AND (fired_period IS NULL OR fired_period <> ?)", alert.ID, budget.StartDate).
+				Updates(map[string]interface{}{"fired_period": budget.StartDate, "pending_channels": ""})
+			if result.Error != nil {
+				errs = append(errs, result.Error)
+				continue
+			}
+			// Another check got there first.
+			if result.RowsAffected == 0 {
+				continue
+			}
+			channels = alert.ChannelList()
+		}
+		failed, err := alerter.notifier.Send(message, channels, alert.WebhookURL)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("alert %d: %w", alert.ID, err))
+		}
+		if len(failed) > 0 {
+			err := alerter.db.Model(&models.BudgetAlert{}).Where("id = ?", alert.ID).
+				Update("pending_channels", strings.Join(failed, ",")).Error
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// evaluate works out whether an alert fires and the message it sends.
+func evaluate(alert models.BudgetAlert, budget *models.Budget, now time.Time) (notifications.Message, bool) {
+	name := budget.Name
+	spent := budget.SpentAmount
+	limit := budget.Limit()
+	if alert.CategoryID != nil {
+		found := false
+		for _, line := range budget.Categories {
+			if line.CategoryID == *alert.CategoryID {
+				spent, limit, found = line.SpentAmount, line.Amount, true
+				if line.Category != nil {
+					name = budget.Name + " / " + line.Category.Name
+				}
+			}
+		}
+		if !found {
+			return notifications.Message{}, false
+		}
+	}
+	target := utils.RoundToCents(limit * alert.Threshold / 100)
+	budgetId := budget.ID
+	message := notifications.Message{
+		UserID:   budget.UserId,
+		Kind:     "budget_alert",
+		BudgetID: &budgetId,
+		Data: map[string]interface{}{
+			"alert_id":     alert.ID,
+			"budget_id":    budget.ID,
+			"category_id":  alert.CategoryID,
+			"type":         alert.Type,
+			"threshold":    alert.Threshold,
+			"spent":        spent,
+			"limit":        limit,
+			"period_start": budget.StartDate.Format("2006-01-02"),
+			"period_end":   budget.EndDate.Format("2006-01-02"),
+		},
+	}
+
+	if alert.Type == models.BudgetAlertProjected {
+		day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, budget.StartDate.Location())
+		if day.After(budget.EndDate) {
+			day = budget.EndDate
+		}
+		elapsed := day.Sub(budget.StartDate).Hours()/24 + 1
+		total := budget.EndDate.Sub(budget.StartDate).Hours()/24 + 1
+		if elapsed < 1 || total < 1 {
+			return message, false
+		}
+		projected := utils.RoundToCents(spent / elapsed * total)
+		message.Data["projected"] = projected
+		message.Title = fmt.Sprintf("%s is on track to overspend", name)
+		message.Body = fmt.Sprintf("%.2f of %.2f has been spent so far. At this rate %.2f will be spent by %s.",
+			spent, limit, projected, budget.EndDate.Format("2006-01-02"))
+		return message, limit > 0 && projected >= target
+	}
+
+	percentage := 0.0
+	if limit > 0 {
+		percentage = spent / limit * 100
+	}
+	message.Title = fmt.Sprintf("%s has reached %.0f%% of its limit", name, alert.Threshold)
+	message.Body = fmt.Sprintf("%.2f of %.2f has been spent, %.0f%% of the limit. The budget runs until %s.",
+		spent, limit, percentage, budget.EndDate.Format("2006-01-02"))
+	return message, limit > 0 && spent >= target
+}
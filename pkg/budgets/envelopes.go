@@ -0,0 +1,235 @@
+package budgets
+
+import (
+	"time"
+
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/categories"
+	"github.com/christo-andrew/haven/pkg/database/scopes"
+	"github.com/christo-andrew/haven/pkg/errors"
+	"github.com/christo-andrew/haven/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// MonthLayout is how envelope months are written.
+const MonthLayout = "2006-01"
+
+// EnvelopeMonth is a month of a user's envelopes. ToBeBudgeted is the income
+// received up to the end of the month that has not been assigned yet, in
+// this month or any other, less what was spent outside of envelopes and the
+// overspending that earlier months left for it to cover.
+type EnvelopeMonth struct {
+	Month        string
+	Income       float64
+	Unbudgeted   float64
+	Assigned     float64
+	Overspent    float64
+	ToBeBudgeted float64
+	Envelopes    []EnvelopeBalance
+}
+
+// EnvelopeBalance is one envelope in a month. Activity is what came out of
+// it, negative for spending and positive for refunds, and Available is what
+// is left: what was carried in, plus what was assigned, plus the activity.
+type EnvelopeBalance struct {
+	Envelope  models.Envelope
+	Carried   float64
+	Assigned  float64
+	Activity  float64
+	Available float64
+}
+
+type activity struct {
+	CategoryID int
+	Month      string
+	Debits     float64
+	Credits    float64
+}
+
+// ParseMonth reads a month written as 2006-01.
+func ParseMonth(value string) (time.Time, error) {
+	month, err := time.Parse(MonthLayout, value)
+	if err != nil {
+		return month, errors.InvalidMonthError(value)
+	}
+	return month, nil
+}
+
+// Envelopes works out a user's envelopes for a month. Envelope budgeting
+// starts in the month the user first created an envelope or assigned money
+// to one, whichever is earlier; income and spending before then are not
+// counted. Spending in a category comes out of the envelope of the category
+// or of the closest category above it that has one, and anything else is
+// spent outside of envelopes. Income is money coming in outside of envelopes.
+func Envelopes(userId uint, month time.Time, db *gorm.DB) (EnvelopeMonth, error) {
+	result := EnvelopeMonth{Month: month.Format(MonthLayout), Envelopes: []EnvelopeBalance{}}
+	var envelopes []models.Envelope
+	if err := db.Where("user_id = ?", userId).Order("id").Find(&envelopes).Error; err != nil {
+		return result, err
+	}
+	if len(envelopes) == 0 {
+		return result, nil
+	}
+	envelopeIds := make([]uint, len(envelopes))
+	byCategory := make(map[int]int, len(envelopes))
+	for i, envelope := range envelopes {
+		envelopeIds[i] = envelope.ID
+		byCategory[envelope.CategoryID] = i
+	}
+	var assignments []models.EnvelopeAssignment
+	if err := db.Where("envelope_id IN ?", envelopeIds).Find(&assignments).Error; err != nil {
+		return result, err
+	}
+
+	start := firstOfMonth(envelopes[0].CreatedAt)
+	for _, envelope := range envelopes {
+		if created := firstOfMonth(envelope.CreatedAt); created.Before(start) {
+			start = created
+		}
+	}
+	assigned := make(map[string]map[uint]float64)
+	for _, assignment := range assignments {
+		if assigned[assignment.Month] == nil {
+			assigned[assignment.Month] = make(map[uint]float64)
+		}
+		assigned[assignment.Month][assignment.EnvelopeID] += assignment.Amount
+		if from, err := ParseMonth(assignment.Month); err == nil && from.Before(start) {
+			start = from
+		}
+	}
+
+	var rows []activity
+	if err := scopes.EnvelopeActivity(userId, start, db).Scan(&rows).Error; err != nil {
+		return result, err
+	}
+	tree := userTree(userId, db)
+	spent := make(map[string]map[uint]float64)
+	income := make(map[string]float64)
+	unbudgeted := make(map[string]float64)
+	for _, row := range rows {
+		if i, ok := envelopeOf(row.CategoryID, byCategory, tree); ok {
+			if spent[row.Month] == nil {
+				spent[row.Month] = make(map[uint]float64)
+			}
+			spent[row.Month][envelopes[i].ID] += row.Credits - row.Debits
+			continue
+		}
+		income[row.Month] += row.Credits
+		unbudgeted[row.Month] += row.Debits
+	}
+
+	// Every assignment counts against what is to be budgeted, those for
+	// later months included, since money can only be assigned once.
+	target := month.Format(MonthLayout)
+	for key, amounts := range assigned {
+		for _, amount := range amounts {
+			result.ToBeBudgeted -= amount
+			if key == target {
+				result.Assigned += amount
+			}
+		}
+	}
+
+	carried := make(map[uint]float64, len(envelopes))
+	for current := start; !current.After(month); current = current.AddDate(0, 1, 0) {
+		key := current.Format(MonthLayout)
+		result.ToBeBudgeted += income[key] - unbudgeted[key]
+		last := key == target
+		var overspent float64
+		for _, envelope := range envelopes {
+			balance := EnvelopeBalance{
+				Envelope: envelope,
+				Carried:  carried[envelope.ID],
+				Assigned: assigned[key][envelope.ID],
+				Activity: utils.RoundToCents(spent[key][envelope.ID]),
+			}
+			balance.Available = utils.RoundToCents(balance.Carried + balance.Assigned + balance.Activity)
+			if last {
+				if node, ok := tree.Node(envelope.CategoryID); ok {
+					category := node.Category
+					balance.Envelope.Category = &category
+				}
+				result.Envelopes = append(result.Envelopes, balance)
+				continue
+			}
+			carried[envelope.ID] = balance.Available
+			if balance.Available < 0 && envelope.Cover != models.EnvelopeCoverCarry {
+				overspent -= balance.Available
+				carried[envelope.ID] = 0
+			}
+		}
+		if !last {
+			result.ToBeBudgeted -= overspent
+			result.Overspent = overspent
+		}
+	}
+	if month.Before(start) {
+		for _, envelope := range envelopes {
+			result.Envelopes = append(result.Envelopes, EnvelopeBalance{Envelope: envelope})
+		}
+	}
+	result.Income = utils.RoundToCents(income[target])
+	result.Unbudgeted = utils.RoundToCents(unbudgeted[target])
+	result.Assigned = utils.RoundToCents(result.Assigned)
+	result.Overspent = utils.RoundToCents(result.Overspent)
+	result.ToBeBudgeted = utils.RoundToCents(result.ToBeBudgeted)
+	return result, nil
+}
+
+// Assign sets what is assigned to an envelope for a month.
+func Assign(envelopeId uint, month time.Time, amount float64, db *gorm.DB) error {
+	assignment, err := monthAssignment(envelopeId, month, db)
+	if err != nil {
+		return err
+	}
+	return db.Model(&assignment).Update("amount", utils.RoundToCents(amount)).Error
+}
+
+// Move moves money assigned to one envelope for a month to another, leaving
+// what is to be budgeted as it was. The source may go negative, which is how
+// an overspent envelope is covered from one that has money left.
+func Move(from uint, to uint, month time.Time, amount float64, db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, change := range []struct {
+			envelopeId uint
+			amount     float64
+		}{{from, -amount}, {to, amount}} {
+			assignment, err := monthAssignment(change.envelopeId, month, tx)
+			if err != nil {
+				return err
+			}
+			err = tx.Model(&assignment).Update("amount", utils.RoundToCents(assignment.Amount+change.amount)).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func monthAssignment(envelopeId uint, month time.Time, db *gorm.DB) (models.EnvelopeAssignment, error) {
+	assignment := models.EnvelopeAssignment{EnvelopeID: envelopeId, Month: month.Format(MonthLayout)}
+	err := db.Where(assignment).FirstOrCreate(&assignment).Error
+	return assignment, err
+}
+
+// envelopeOf finds the envelope a category's spending comes out of.
+func envelopeOf(categoryId int, byCategory map[int]int, tree *categories.Tree) (int, bool) {
+	if i, ok := byCategory[categoryId]; ok {
+		return i, true
+	}
+	node, ok := tree.Node(categoryId)
+	if !ok {
+		return 0, false
+	}
+	for parent := node.Parent; parent != nil; parent = parent.Parent {
+		if i, ok := byCategory[parent.Category.ID]; ok {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func firstOfMonth(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
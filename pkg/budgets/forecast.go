@@ -0,0 +1,222 @@
+package budgets
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/database/scopes"
+	"github.com/christo-andrew/haven/pkg/utils"
+)
+
+// forecastHistory is how many earlier stretches of the same length as a
+// budget are looked at for how spending usually builds up over a period.
+const forecastHistory = 3
+
+// minimumShare is the smallest part of a period's spending that must usually
+// have happened by now for the spend so far to be scaled up by it. Below it
+// the spending still to come is taken from history as is.
+const minimumShare = 0.05
+
+const (
+	ForecastRunRate    = "run_rate"
+	ForecastSeasonal   = "seasonal"
+	ForecastNotStarted = "not_started"
+	ForecastEnded      = "ended"
+)
+
+// Forecast is where a budget is heading by the end of its period.
+type Forecast struct {
+	// Basis is how the projection was made: from the run rate so far, or
+	// from how spending built up over the same stretch of earlier periods.
+	Basis             string
+	RunRate           float64
+	ProjectedSpend    float64
+	ProjectedVariance float64
+	WillExceed        bool
+	// OverspendDate is the day the limit is likely to be reached, if it is
+	// reached before the end of the budget.
+	OverspendDate *time.Time
+	// RecommendedDailyAllowance is what can be spent each day from today to
+	// finish within the limit.
+	RecommendedDailyAllowance float64
+}
+
+// Forecast projects the spend of a filled budget at the end of its period.
+// The run rate is the spend so far per day. When the same categories and
+// tags were spent on in the stretches of the same length before the budget,
+// the projection follows how spending built up over those instead, so that
+// spending that comes early in a period, like rent, or late, like a monthly
+// bill, is not extrapolated as if it were spread evenly.
+func (tracker *Tracker) Forecast(budget *models.Budget, now time.Time) (Forecast, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, budget.StartDate.Location())
+	if today.Before(budget.StartDate) || today.After(budget.EndDate) {
+		return project(budget, today, nil), nil
+	}
+	history, err := tracker.history(budget, days(budget.StartDate, today))
+	if err != nil {
+		return Forecast{}, err
+	}
+	return project(budget, today, history), nil
+}
+
+// stretch is what was spent over one of the stretches of the same length
+// before a budget, in all and by the same day of the stretch as today is of
+// the budget.
+type stretch struct {
+	whole float64
+	sofar float64
+}
+
+type dailySpend struct {
+	Day string
+	totals
+}
+
+// history works out the stretches before a budget that had anything spent
+// in them, from the spend of every day before the budget in one query.
+func (tracker *Tracker) history(budget *models.Budget, elapsed float64) ([]stretch, error) {
+	tagIds, lines, err := tracker.load(budget)
+	if err != nil {
+		return nil, err
+	}
+	length := int(days(budget.StartDate, budget.EndDate))
+	var rows []dailySpend
+	err = scopes.BudgetDailySpend(budget.UserId, covered(budget, lines, tracker.tree(budget.UserId)), tagIds,
+		budget.StartDate.AddDate(0, 0, -forecastHistory*length), budget.StartDate.AddDate(0, 0, -1), tracker.db).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return stretches(rows, budget.StartDate, length, elapsed)
+}
+
+// stretches adds up the daily spend before a budget starting on start into
+// the stretches of the given length before it.
+func stretches(rows []dailySpend, start time.Time, length int, elapsed float64) ([]stretch, error) {
+	whole := make([]totals, forecastHistory)
+	sofar := make([]totals, forecastHistory)
+	for _, row := range rows {
+		day, err := time.ParseInLocation(time.DateOnly, row.Day, start.Location())
+		if err != nil {
+			return nil, err
+		}
+		before := int(math.Round(start.Sub(day).Hours() / 24))
+		if before < 1 || before > forecastHistory*length {
+			continue
+		}
+		k := (before - 1) / length
+		whole[k].add(row.totals)
+		// The stretch starts (k+1) lengths before the budget.
+		if float64((k+1)*length-before) < elapsed {
+			sofar[k].add(row.totals)
+		}
+	}
+	var result []stretch
+	for k := range whole {
+		if amount := whole[k].spent(); amount > 0 {
+			result = append(result, stretch{whole: amount, sofar: sofar[k].spent()})
+		}
+	}
+	return result, nil
+}
+
+func (total *totals) add(other totals) {
+	total.Debits += other.Debits
+	total.Refunds += other.Refunds
+}
+
+// spent is what the totals come to, refunds giving back what they refund
+// but never below zero.
+func (total totals) spent() float64 {
+	return utils.RoundToCents(math.Max(total.Debits-total.Refunds, 0))
+}
+
+// project makes the forecast of a filled budget on a day, following the
+// stretches before it when there are any.
+func project(budget *models.Budget, today time.Time, history []stretch) Forecast {
+	forecast := Forecast{Basis: ForecastRunRate}
+	limit := budget.Limit()
+	spent := budget.SpentAmount
+	total := days(budget.StartDate, budget.EndDate)
+	switch {
+	case today.Before(budget.StartDate):
+		forecast.Basis = ForecastNotStarted
+		forecast.ProjectedSpend = spent
+		if total > 0 {
+			forecast.RecommendedDailyAllowance = utils.RoundToCents(math.Max(limit-spent, 0) / total)
+		}
+		forecast.ProjectedVariance = utils.RoundToCents(limit - spent)
+		forecast.WillExceed = spent > limit
+		return forecast
+	case today.After(budget.EndDate):
+		forecast.Basis = ForecastEnded
+		forecast.ProjectedSpend = spent
+		forecast.ProjectedVariance = utils.RoundToCents(limit - spent)
+		forecast.WillExceed = spent > limit
+		if total > 0 {
+			forecast.RunRate = utils.RoundToCents(spent / total)
+		}
+		return forecast
+	}
+
+	elapsed := days(budget.StartDate, today)
+	remaining := total - elapsed
+	forecast.RunRate = utils.RoundToCents(spent / elapsed)
+	projected := spent / elapsed * total
+	if len(history) > 0 {
+		var shares, still []float64
+		for _, s := range history {
+			shares = append(shares, s.sofar/s.whole)
+			still = append(still, s.whole-s.sofar)
+		}
+		forecast.Basis = ForecastSeasonal
+		if share := mean(shares); share >= minimumShare {
+			projected = spent / share
+		} else {
+			projected = spent + mean(still)
+		}
+		// Spending that already happened stays spent.
+		projected = math.Max(projected, spent)
+	}
+
+	forecast.ProjectedSpend = utils.RoundToCents(projected)
+	forecast.ProjectedVariance = utils.RoundToCents(limit - projected)
+	forecast.WillExceed = projected > limit
+	// Today can still be spent, the last day of the budget included.
+	forecast.RecommendedDailyAllowance = utils.RoundToCents(math.Max(limit-spent, 0) / (remaining + 1))
+	if forecast.WillExceed {
+		date := today
+		if spent < limit && remaining > 0 {
+			rate := (projected - spent) / remaining
+			date = today.AddDate(0, 0, int(math.Ceil((limit-spent)/rate)))
+			if date.After(budget.EndDate) {
+				date = budget.EndDate
+			}
+		}
+		forecast.OverspendDate = &date
+	}
+	return forecast
+}
+
+// Warning says when the budget is likely to go over its limit and what can
+// still be spent each day to keep within it. It is empty when the budget is
+// not heading over.
+func (forecast Forecast) Warning() string {
+	if !forecast.WillExceed || forecast.Basis == ForecastEnded {
+		return ""
+	}
+	if forecast.OverspendDate == nil || forecast.RecommendedDailyAllowance == 0 {
+		return fmt.Sprintf("Over the limit, heading for %.2f of spend.", forecast.ProjectedSpend)
+	}
+	return fmt.Sprintf("Likely to go over the limit around %s, ending at %.2f. Keep to %.2f a day to stay within it.",
+		forecast.OverspendDate.Format("2 Jan 2006"), forecast.ProjectedSpend, forecast.RecommendedDailyAllowance)
+}
+
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values))
+}
@@ -0,0 +1,107 @@
+package budgets
+
+import (
+	"testing"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/models"
+)
+
+func TestProject(t *testing.T) {
+	tests := []struct {
+		name          string
+		today         time.Time
+		spent         float64
+		history       []stretch
+		basis         string
+		projected     float64
+		willExceed    bool
+		allowance     float64
+		overspendDate string
+	}{
+		{"run rate heading over", date(2024, 4, 10), 150, nil, ForecastRunRate, 450, true, 7.14, "2024-04-20"},
+		{"run rate within", date(2024, 4, 15), 60, nil, ForecastRunRate, 120, false, 15, ""},
+		{"run rate on the first day", date(2024, 4, 1), 20, nil, ForecastRunRate, 600, true, 9.33, "2024-04-15"},
+		{"seasonal within", date(2024, 4, 10), 150, []stretch{{whole: 300, sofar: 200}, {whole: 200, sofar: 100}},
+			ForecastSeasonal, 257.14, false, 7.14, ""},
+		{"seasonal spending still to come", date(2024, 4, 10), 10, []stretch{{whole: 300, sofar: 0}},
+			ForecastSeasonal, 310, true, 13.81, "2024-04-30"},
+		{"seasonal never below the spend", date(2024, 4, 10), 150, []stretch{{whole: 100, sofar: 100}},
+			ForecastSeasonal, 150, false, 7.14, ""},
+		{"last day within", date(2024, 4, 30), 280, nil, ForecastRunRate, 280, false, 20, ""},
+		{"last day heading over", date(2024, 4, 30), 280, []stretch{{whole: 100, sofar: 50}},
+			ForecastSeasonal, 560, true, 20, "2024-04-30"},
+		{"last day over", date(2024, 4, 30), 320, nil, ForecastRunRate, 320, true, 0, "2024-04-30"},
+		{"not started", date(2024, 3, 25), 0, nil, ForecastNotStarted, 0, false, 10, ""},
+		{"ended", date(2024, 5, 2), 330, nil, ForecastEnded, 330, true, 0, ""},
+	}
+	for _, test := range tests {
+		budget := &models.Budget{StartDate: date(2024, 4, 1), EndDate: date(2024, 4, 30), Amount: 300, SpentAmount: test.spent}
+		forecast := project(budget, test.today, test.history)
+		overspendDate := ""
+		if forecast.OverspendDate != nil {
+			overspendDate = forecast.OverspendDate.Format(time.DateOnly)
+		}
+		if forecast.Basis != test.basis || forecast.ProjectedSpend != test.projected || forecast.WillExceed != test.willExceed ||
+			forecast.RecommendedDailyAllowance != test.allowance || overspendDate != test.overspendDate {
+			t.Errorf("%s: project() = %s %v %v %v %q, want %s %v %v %v %q", test.name,
+				forecast.Basis, forecast.ProjectedSpend, forecast.WillExceed, forecast.RecommendedDailyAllowance, overspendDate,
+				test.basis, test.projected, test.willExceed, test.allowance, test.overspendDate)
+		}
+	}
+}
+
+func TestForecastWarning(t *testing.T) {
+	lastDay := date(2024, 4, 30)
+	tests := []struct {
+		name     string
+		forecast Forecast
+		want     string
+	}{
+		{"within", Forecast{Basis: ForecastRunRate, ProjectedSpend: 120}, ""},
+		{"ended", Forecast{Basis: ForecastEnded, ProjectedSpend: 330, WillExceed: true}, ""},
+		{"over", Forecast{Basis: ForecastRunRate, ProjectedSpend: 320, WillExceed: true, OverspendDate: &lastDay},
+			"Over the limit, heading for 320.00 of spend."},
+		{"last day", Forecast{Basis: ForecastSeasonal, ProjectedSpend: 560, WillExceed: true, OverspendDate: &lastDay, RecommendedDailyAllowance: 20},
+			"Likely to go over the limit around 30 Apr 2024, ending at 560.00. Keep to 20.00 a day to stay within it."},
+	}
+	for _, test := range tests {
+		if got := test.forecast.Warning(); got != test.want {
+			t.Errorf("%s: Warning() = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestStretches(t *testing.T) {
+	spend := func(day string, debits float64, refunds float64) dailySpend {
+		return dailySpend{Day: day, totals: totals{Debits: debits, Refunds: refunds}}
+	}
+	// A budget of 10 days from 2024-04-01, 4 days in: the stretches before it
+	// run from 03-22, 03-12 and 03-02, the first 4 days of each counting
+	// towards what was spent so far.
+	rows := []dailySpend{
+		spend("2024-03-02", 10, 0),
+		spend("2024-03-11", 20, 0),
+		spend("2024-03-12", 100, 0),
+		spend("2024-03-15", 50, 0),
+		spend("2024-03-16", 25, 0),
+		spend("2024-03-21", 0, 100),
+		spend("2024-03-22", 40, 0),
+		spend("2024-03-31", 60, 0),
+		spend("2024-02-20", 1000, 0),
+		spend("2024-04-01", 1000, 0),
+	}
+	got, err := stretches(rows, date(2024, 4, 1), 10, 4)
+	if err != nil {
+		t.Fatalf("stretches() error = %v", err)
+	}
+	want := []stretch{{whole: 100, sofar: 40}, {whole: 75, sofar: 150}, {whole: 30, sofar: 10}}
+	if len(got) != len(want) {
+		t.Fatalf("stretches() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("stretches()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
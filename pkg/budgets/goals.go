@@ -0,0 +1,168 @@
+package budgets
+
+import (
+	"math"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/database/scopes"
+	"github.com/christo-andrew/haven/pkg/ledger"
+	"github.com/christo-andrew/haven/pkg/utils"
+)
+
+const (
+	GoalAchieved = "achieved"
+	GoalOnTrack  = "on_track"
+	GoalBehind   = "behind"
+	GoalMissed   = "missed"
+)
+
+// GoalProgress is how far a goal has come. Expected is what would have been
+// saved by now saving the same amount every day from the start of the goal,
+// and RequiredMonthly what has to be saved each month from now on to reach
+// the target by the target date.
+type GoalProgress struct {
+	FromAccounts      float64
+	FromContributions float64
+	FromBudgets       float64
+	Saved             float64
+	Remaining         float64
+	Percentage        float64
+	Expected          float64
+	MonthsRemaining   int
+	RequiredMonthly   float64
+	Status            string
+}
+
+// GoalProgress works out how far a goal has come. The linked accounts count
+// with their current balance, and the transactions carrying the goal's tag
+// since its start in any other account count with their amount, withdrawals
+// taking away from what was saved. Each budget
+// line linked to the goal counts what it left unspent in every period that
+// ended since the goal started, in the part of the period after the start,
+// with the line's current limit.
+func (tracker *Tracker) GoalProgress(goal models.Goal, now time.Time) (GoalProgress, error) {
+	var progress GoalProgress
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var accountIds []int
+	for _, link := range goal.Accounts {
+		accountIds = append(accountIds, link.AccountID)
+	}
+	if len(accountIds) > 0 {
+		var accounts []models.Account
+		if err := tracker.db.Where("id IN ? AND user_id = ?", accountIds, goal.UserID).Find(&accounts).Error; err != nil {
+			return progress, err
+		}
+		for _, account := range accounts {
+			var totals []ledger.StatusTotal
+			if err := scopes.AccountTotalsByStatus(account.ID, tracker.db).Scan(&totals).Error; err != nil {
+				return progress, err
+			}
+			progress.FromAccounts += ledger.NewBalance(account.Balance, totals).Current
+		}
+	}
+	if goal.TagID != nil {
+		var contributions struct{ Amount float64 }
+		err := scopes.GoalContributions(goal.UserID, *goal.TagID, accountIds, goal.StartDate, tracker.db).Scan(&contributions).Error
+		if err != nil {
+			return progress, err
+		}
+		progress.FromContributions = contributions.Amount
+	}
+	fromBudgets, err := tracker.goalBudgetSavings(goal, today)
+	if err != nil {
+		return progress, err
+	}
+	progress.FromBudgets = fromBudgets
+
+	progress.FromAccounts = utils.RoundToCents(progress.FromAccounts)
+	progress.FromContributions = utils.RoundToCents(progress.FromContributions)
+	progress.FromBudgets = utils.RoundToCents(progress.FromBudgets)
+	saved := progress.FromAccounts + progress.FromContributions + progress.FromBudgets
+	progress.Saved = utils.RoundToCents(saved)
+	progress.Remaining = utils.RoundToCents(math.Max(goal.TargetAmount-saved, 0))
+	if goal.TargetAmount > 0 {
+		progress.Percentage = utils.RoundToCents(math.Min(saved/goal.TargetAmount*100, 100))
+	}
+	total := days(goal.StartDate, goal.TargetDate)
+	elapsed := math.Min(days(goal.StartDate, today), total)
+	if total > 0 {
+		progress.Expected = utils.RoundToCents(goal.TargetAmount * elapsed / total)
+	}
+	progress.MonthsRemaining = monthsUntil(today, goal.TargetDate)
+	if progress.MonthsRemaining > 0 {
+		progress.RequiredMonthly = utils.RoundToCents(progress.Remaining / float64(progress.MonthsRemaining))
+	}
+
+	switch {
+	case saved >= goal.TargetAmount:
+		progress.Status = GoalAchieved
+	case today.After(goal.TargetDate):
+		progress.Status = GoalMissed
+	case saved >= progress.Expected:
+		progress.Status = GoalOnTrack
+	default:
+		progress.Status = GoalBehind
+	}
+	return progress, nil
+}
+
+// goalBudgetSavings adds up what the budget lines linked to a goal left
+// unspent in the periods that ended since the goal started.
+func (tracker *Tracker) goalBudgetSavings(goal models.Goal, today time.Time) (float64, error) {
+	var budgetIds []int
+	err := tracker.db.Model(&models.BudgetCategory{}).Distinct("budget_id").
+		Where("goal_id = ?", goal.ID).Pluck("budget_id", &budgetIds).Error
+	if err != nil || len(budgetIds) == 0 {
+		return 0, err
+	}
+	var linked []models.Budget
+	if err := tracker.db.Where("id IN ? AND user_id = ?", budgetIds, goal.UserID).Find(&linked).Error; err != nil {
+		return 0, err
+	}
+	tree := tracker.tree(goal.UserID)
+	saved := 0.0
+	for _, budget := range linked {
+		tagIds, lines, err := tracker.load(&budget)
+		if err != nil {
+			return 0, err
+		}
+		spans, err := budgetSpans(budget, tracker.db)
+		if err != nil {
+			return 0, err
+		}
+		for _, s := range spans {
+			if !s.end.Before(today) || s.end.Before(goal.StartDate) {
+				continue
+			}
+			clipped := budget
+			clipped.StartDate, clipped.EndDate = s.start, s.end
+			spent, err := tracker.compute(&clipped, lines, tagIds, tree)
+			if err != nil {
+				return 0, err
+			}
+			share := days(later(s.start, goal.StartDate), s.end) / days(s.start, s.end)
+			for _, line := range lines {
+				if line.GoalID != nil && *line.GoalID == goal.ID {
+					saved += math.Max(line.Amount-spent.lines[line.ID], 0) * share
+				}
+			}
+		}
+	}
+	return saved, nil
+}
+
+// monthsUntil counts the monthly contributions left from today to a date,
+// one on today's day of each month, at least one while the date has not
+// passed.
+func monthsUntil(today time.Time, date time.Time) int {
+	if date.Before(today) {
+		return 0
+	}
+	months := (date.Year()-today.Year())*12 + int(date.Month()) - int(today.Month())
+	if date.Day() >= today.Day() {
+		months++
+	}
+	return int(math.Max(float64(months), 1))
+}
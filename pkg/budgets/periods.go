@@ -0,0 +1,157 @@
+package budgets
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// maxCatchUp limits how many missed periods of one budget are closed in a
+// single run.
+const maxCatchUp = 366
+
+// ValidatePeriod checks a period and, for custom periods, their length.
+func ValidatePeriod(period string, days int) error {
+	switch period {
+	case models.BudgetPeriodWeekly, models.BudgetPeriodMonthly, models.BudgetPeriodQuarterly, models.BudgetPeriodYearly:
+		return nil
+	case models.BudgetPeriodCustom:
+		if days < 1 {
+			return fmt.Errorf("a custom period needs period_days of at least 1")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported budget period %q", period)
+	}
+}
+
+// PeriodStart returns the start of the nth period after the one starting on
+// anchor. Monthly periods keep the day of the month of the anchor, falling on
+// the last day of shorter months, so periods starting on the 31st start on
+// the 28th, 30th or 31st.
+func PeriodStart(period string, days int, anchor time.Time, n int) time.Time {
+	switch period {
+	case models.BudgetPeriodWeekly:
+		return anchor.AddDate(0, 0, 7*n)
+	case models.BudgetPeriodQuarterly:
+		return addMonths(anchor, 3*n)
+	case models.BudgetPeriodYearly:
+		return addMonths(anchor, 12*n)
+	case models.BudgetPeriodCustom:
+		return anchor.AddDate(0, 0, days*n)
+	default:
+		return addMonths(anchor, n)
+	}
+}
+
+// PeriodEnd returns the last day of the nth period after the one starting on
+// anchor.
+func PeriodEnd(period string, days int, anchor time.Time, n int) time.Time {
+	return PeriodStart(period, days, anchor, n+1).AddDate(0, 0, -1)
+}
+
+func addMonths(date time.Time, months int) time.Time {
+	year, month, day := date.Date()
+	first := time.Date(year, month+time.Month(months), 1, 0, 0, 0, 0, date.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	if day > last {
+		day = last
+	}
+	return time.Date(first.Year(), first.Month(), day, date.Hour(), date.Minute(), date.Second(), date.Nanosecond(), date.Location())
+}
+
+// Carryover is what a period with the given amount left over passes on to the
+// next one under a rollover setting. A negative leftover is an overspend.
+func Carryover(rollover string, leftover float64) float64 {
+	switch rollover {
+	case models.BudgetRolloverUnspent:
+		return math.Max(leftover, 0)
+	case models.BudgetRolloverOverspent:
+		return math.Min(leftover, 0)
+	case models.BudgetRolloverBoth:
+		return leftover
+	default:
+		return 0
+	}
+}
+
+// CurrentPeriod returns the open period of a recurring budget, creating it
+// for budgets that do not have one yet with nothing carried over.
+func CurrentPeriod(budget *models.Budget, db *gorm.DB) (models.BudgetPeriod, error) {
+	var period models.BudgetPeriod
+	db.Where("budget_id = ? AND start_date = ?", budget.ID, budget.StartDate).First(&period)
+	if period.ID != 0 {
+		return period, nil
+	}
+	period = models.BudgetPeriod{
+		BudgetID:  budget.ID,
+		StartDate: budget.StartDate,
+		EndDate:   budget.EndDate,
+		Amount:    budget.Amount,
+	}
+	err := db.Create(&period).Error
+	return period, err
+}
+
+// Advance closes the periods of a recurring budget that ended before now and
+// opens the ones that follow, until the budget's dates are those of the
+// period now is in. A closed period keeps what was spent in it, and the next
+// period carries over what its rollover setting says.
+func Advance(budget *models.Budget, now time.Time, tracker *Tracker, db *gorm.DB) error {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, budget.EndDate.Location())
+	for closed := 0; budget.Recurring && budget.EndDate.Before(today) && closed < maxCatchUp; closed++ {
+		current, err := CurrentPeriod(budget, db)
+		if err != nil {
+			return err
+		}
+		var first models.BudgetPeriod
+		db.Where("budget_id = ?", budget.ID).Order("start_date").First(&first)
+		var index int64
+		db.Model(&models.BudgetPeriod{}).Where("budget_id = ? AND start_date < ?", budget.ID, current.StartDate).Count(&index)
+
+		spent, err := tracker.Spent(*budget)
+		if err != nil {
+			return err
+		}
+		next := models.BudgetPeriod{
+			BudgetID:  budget.ID,
+			StartDate: PeriodStart(budget.Period, budget.PeriodDays, first.StartDate, int(index)+1),
+			EndDate:   PeriodEnd(budget.Period, budget.PeriodDays, first.StartDate, int(index)+1),
+			Amount:    budget.Amount,
+			Carryover: utils.RoundToCents(Carryover(budget.Rollover, budget.Amount+current.Carryover-spent)),
+		}
+		// A period starting before the current one ends would overlap it,
+		// which happens when the period of the budget is changed midway.
+		if !next.StartDate.After(current.EndDate) {
+			next.StartDate = current.EndDate.AddDate(0, 0, 1)
+			next.EndDate = PeriodEnd(budget.Period, budget.PeriodDays, next.StartDate, 0)
+		}
+		err = db.Transaction(func(tx *gorm.DB) error {
+			err := tx.Model(&current).Updates(map[string]interface{}{
+				"amount":       budget.Amount,
+				"spent_amount": spent,
+				"closed":       true,
+			}).Error
+			if err != nil {
+				return err
+			}
+			if err := tx.Create(&next).Error; err != nil {
+				return err
+			}
+			return tx.Model(budget).Updates(map[string]interface{}{
+				"start_date": next.StartDate,
+				"end_date":   next.EndDate,
+			}).Error
+		})
+		if err != nil {
+			return err
+		}
+		budget.StartDate = next.StartDate
+		budget.EndDate = next.EndDate
+	}
+	return nil
+}
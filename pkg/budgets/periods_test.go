@@ -0,0 +1,106 @@
+package budgets
+
+import (
+	"testing"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/models"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestPeriodStart(t *testing.T) {
+	tests := []struct {
+		period string
+		days   int
+		anchor time.Time
+		n      int
+		want   time.Time
+	}{
+		{models.BudgetPeriodWeekly, 0, date(2024, 12, 30), 1, date(2025, 1, 6)},
+		{models.BudgetPeriodMonthly, 0, date(2024, 1, 15), 1, date(2024, 2, 15)},
+		{models.BudgetPeriodMonthly, 0, date(2024, 1, 31), 1, date(2024, 2, 29)},
+		{models.BudgetPeriodMonthly, 0, date(2024, 1, 31), 2, date(2024, 3, 31)},
+		{models.BudgetPeriodMonthly, 0, date(2024, 3, 31), -1, date(2024, 2, 29)},
+		{"", 0, date(2024, 1, 31), 3, date(2024, 4, 30)},
+		{models.BudgetPeriodQuarterly, 0, date(2024, 11, 30), 1, date(2025, 2, 28)},
+		{models.BudgetPeriodYearly, 0, date(2024, 2, 29), 1, date(2025, 2, 28)},
+		{models.BudgetPeriodYearly, 0, date(2024, 2, 29), 4, date(2028, 2, 29)},
+		{models.BudgetPeriodCustom, 10, date(2024, 2, 25), 1, date(2024, 3, 6)},
+		{models.BudgetPeriodCustom, 10, date(2024, 2, 25), 0, date(2024, 2, 25)},
+	}
+	for _, test := range tests {
+		if got := PeriodStart(test.period, test.days, test.anchor, test.n); !got.Equal(test.want) {
+			t.Errorf("PeriodStart(%q, %d, %s, %d) = %s, want %s", test.period, test.days,
+				test.anchor.Format(time.DateOnly), test.n, got.Format(time.DateOnly), test.want.Format(time.DateOnly))
+		}
+	}
+}
+
+func TestPeriodEnd(t *testing.T) {
+	tests := []struct {
+		period string
+		anchor time.Time
+		n      int
+		want   time.Time
+	}{
+		{models.BudgetPeriodMonthly, date(2024, 1, 1), 0, date(2024, 1, 31)},
+		{models.BudgetPeriodMonthly, date(2024, 1, 31), 0, date(2024, 2, 28)},
+		{models.BudgetPeriodWeekly, date(2024, 1, 1), 0, date(2024, 1, 7)},
+	}
+	for _, test := range tests {
+		if got := PeriodEnd(test.period, 0, test.anchor, test.n); !got.Equal(test.want) {
+			t.Errorf("PeriodEnd(%q, 0, %s, %d) = %s, want %s", test.period,
+				test.anchor.Format(time.DateOnly), test.n, got.Format(time.DateOnly), test.want.Format(time.DateOnly))
+		}
+	}
+}
+
+func TestAddMonths(t *testing.T) {
+	tests := []struct {
+		date   time.Time
+		months int
+		want   time.Time
+	}{
+		{date(2024, 1, 31), 1, date(2024, 2, 29)},
+		{date(2023, 1, 31), 1, date(2023, 2, 28)},
+		{date(2024, 5, 31), 1, date(2024, 6, 30)},
+		{date(2024, 8, 31), 1, date(2024, 9, 30)},
+		{date(2024, 12, 31), 2, date(2025, 2, 28)},
+		{date(2024, 3, 31), -1, date(2024, 2, 29)},
+		{date(2024, 1, 15), 13, date(2025, 2, 15)},
+		{date(2024, 1, 30), 0, date(2024, 1, 30)},
+		{time.Date(2024, 1, 31, 9, 30, 0, 0, time.UTC), 1, time.Date(2024, 2, 29, 9, 30, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		if got := addMonths(test.date, test.months); !got.Equal(test.want) {
+			t.Errorf("addMonths(%s, %d) = %s, want %s", test.date, test.months, got, test.want)
+		}
+	}
+}
+
+func TestCarryover(t *testing.T) {
+	tests := []struct {
+		rollover string
+		leftover float64
+		want     float64
+	}{
+		{models.BudgetRolloverNone, 50, 0},
+		{models.BudgetRolloverNone, -50, 0},
+		{"", 50, 0},
+		{models.BudgetRolloverUnspent, 50, 50},
+		{models.BudgetRolloverUnspent, -50, 0},
+		{models.BudgetRolloverOverspent, 50, 0},
+		{models.BudgetRolloverOverspent, -50, -50},
+		{models.BudgetRolloverBoth, 50, 50},
+		{models.BudgetRolloverBoth, -50, -50},
+		{models.BudgetRolloverBoth, 0, 0},
+	}
+	for _, test := range tests {
+		if got := Carryover(test.rollover, test.leftover); got != test.want {
+			t.Errorf("Carryover(%q, %v) = %v, want %v", test.rollover, test.leftover, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package budgets
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/pdf"
+)
+
+var csvHeader = []string{
+	"budget_id", "budget", "line_id", "category_id", "category",
+	"period_start", "period_end", "limit", "actual", "variance", "percentage",
+}
+
+// WriteCSV writes the report with one row per budget or line and period. The
+// rows of a budget as a whole leave the line columns empty.
+func (report Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	record := func(budget BudgetReport, line *LineReport, row ReportRow) []string {
+		lineId, categoryId, category := "", "", ""
+		if line != nil {
+			lineId = strconv.FormatUint(uint64(line.LineID), 10)
+			categoryId = strconv.Itoa(line.CategoryID)
+			category = line.Category
+		}
+		return []string{
+			strconv.FormatUint(uint64(budget.BudgetID), 10), budget.Name, lineId, categoryId, category,
+			row.Start.Format("2006-01-02"), row.End.Format("2006-01-02"),
+			money(row.Limit), money(row.Actual), money(row.Variance), money(row.Percentage),
+		}
+	}
+	for _, budget := range report.Budgets {
+		for _, row := range budget.Rows {
+			if err := writer.Write(record(budget, nil, row)); err != nil {
+				return err
+			}
+		}
+		for i := range budget.Lines {
+			for _, row := range budget.Lines[i].Rows {
+				if err := writer.Write(record(budget, &budget.Lines[i], row)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+const (
+	pageMargin  = 40.0
+	chartHeight = 110.0
+	rowHeight   = 15.0
+)
+
+var (
+	grey      = pdf.Color{R: 0.45, G: 0.45, B: 0.45}
+	lightGrey = pdf.Color{R: 0.92, G: 0.92, B: 0.92}
+	limitBar  = pdf.Color{R: 0.62, G: 0.72, B: 0.86}
+	underBar  = pdf.Color{R: 0.30, G: 0.69, B: 0.31}
+	overBar   = pdf.Color{R: 0.90, G: 0.30, B: 0.24}
+)
+
+// tableColumns are the left edges of the table columns; the numbers are
+// right aligned to the edge of the next column.
+var tableColumns = []float64{pageMargin, 170, 325, 390, 455, 515, pdf.A4Width - pageMargin}
+
+// WritePDF writes the report as a PDF with a bar chart of the limit and the
+// actual spend of each budget per period, followed by a table of the budget
+// and its lines.
+func (report Report) WritePDF(w io.Writer) error {
+	document := pdf.New()
+	document.AddPage()
+	y := pageMargin + 20
+	document.Text(pageMargin, y, 18, true, pdf.Black, "Budget performance")
+	y += 18
+	document.Text(pageMargin, y, 10, false, grey, fmt.Sprintf("%s to %s, %s",
+		report.From.Format("2 Jan 2006"), report.To.Format("2 Jan 2006"), report.Period))
+	y += 30
+	if len(report.Budgets) == 0 {
+		document.Text(pageMargin, y, 11, false, pdf.Black, "No budgets ran in this period.")
+	}
+
+	bottom := document.Height - pageMargin
+	for _, budget := range report.Budgets {
+		if y+30+chartHeight+3*rowHeight > bottom {
+			document.AddPage()
+			y = pageMargin + 10
+		}
+		document.Text(pageMargin, y, 13, true, pdf.Black, pdf.Truncate(budget.Name, document.Width-2*pageMargin, 13, true))
+		y += 12
+		y = drawChart(document, budget.Rows, report.Period, y)
+
+		y = drawTableHeader(document, y)
+		cells := func(label string, category string, row ReportRow, bold bool) {
+			if y+rowHeight > bottom {
+				document.AddPage()
+				y = drawTableHeader(document, pageMargin+10)
+			}
+			color := pdf.Black
+			if row.Variance < 0 {
+				color = overBar
+			}
+			baseline := y + rowHeight - 4
+			document.Text(tableColumns[0]+4, baseline, 9, bold, pdf.Black, label)
+			document.Text(tableColumns[1]+4, baseline, 9, bold, pdf.Black, pdf.Truncate(category, tableColumns[2]-tableColumns[1]-8, 9, bold))
+			for i, value := range []string{money(row.Limit), money(row.Actual), money(row.Variance), money(row.Percentage) + "%"} {
+				document.TextRight(tableColumns[i+3]-4, baseline, 9, bold, color, value)
+			}
+			y += rowHeight
+			document.Line(pageMargin, y, document.Width-pageMargin, y, 0.3, lightGrey)
+		}
+		for i, row := range budget.Rows {
+			cells(periodRange(row), "All categories", row, true)
+			for _, line := range budget.Lines {
+				if i < len(line.Rows) {
+					cells("", line.Category, line.Rows[i], false)
+				}
+			}
+		}
+		y += 25
+	}
+	_, err := document.WriteTo(w)
+	return err
+}
+
+// drawChart draws a bar chart of the limit and the actual spend in each
+// period and returns where the chart ends.
+func drawChart(document *pdf.Document, rows []ReportRow, period string, y float64) float64 {
+	top := y + 8
+	left, right := pageMargin+40, document.Width-pageMargin
+	base := top + chartHeight
+	highest := 0.0
+	for _, row := range rows {
+		highest = math.Max(highest, math.Max(row.Limit, row.Actual))
+	}
+	if highest == 0 {
+		highest = 1
+	}
+	document.TextRight(left-4, top+4, 7, false, grey, money(highest))
+	document.TextRight(left-4, base, 7, false, grey, "0")
+	document.Line(left, top, right, top, 0.3, lightGrey)
+	document.Line(left, base, right, base, 0.5, grey)
+
+	if len(rows) > 0 {
+		group := (right - left) / float64(len(rows))
+		bar := math.Min(group*0.35, 30)
+		for i, row := range rows {
+			x := left + group*float64(i) + (group-2*bar)/2
+			limitHeight := chartHeight * math.Max(row.Limit, 0) / highest
+			actualHeight := chartHeight * math.Max(row.Actual, 0) / highest
+			document.Rect(x, base-limitHeight, bar, limitHeight, limitBar)
+			color := underBar
+			if row.Actual > row.Limit {
+				color = overBar
+			}
+			document.Rect(x+bar, base-actualHeight, bar, actualHeight, color)
+			label := pdf.Truncate(periodLabel(row, period), group-2, 7, false)
+			document.Text(left+group*float64(i)+(group-pdf.TextWidth(label, 7, false))/2, base+10, 7, false, grey, label)
+		}
+	}
+
+	legend := base + 24
+	document.Rect(left, legend-7, 8, 8, limitBar)
+	document.Text(left+12, legend, 8, false, grey, "Limit")
+	document.Rect(left+50, legend-7, 8, 8, underBar)
+	document.Text(left+62, legend, 8, false, grey, "Actual")
+	document.Rect(left+110, legend-7, 8, 8, overBar)
+	document.Text(left+122, legend, 8, false, grey, "Over limit")
+	return legend + 12
+}
+
+func drawTableHeader(document *pdf.Document, y float64) float64 {
+	document.Rect(pageMargin, y, document.Width-2*pageMargin, rowHeight, lightGrey)
+	baseline := y + rowHeight - 4
+	document.Text(tableColumns[0]+4, baseline, 9, true, pdf.Black, "Period")
+	document.Text(tableColumns[1]+4, baseline, 9, true, pdf.Black, "Category")
+	for i, title := range []string{"Limit", "Actual", "Variance", "Used"} {
+		document.TextRight(tableColumns[i+3]-4, baseline, 9, true, pdf.Black, title)
+	}
+	return y + rowHeight
+}
+
+func periodLabel(row ReportRow, period string) string {
+	switch period {
+	case models.BudgetPeriodWeekly:
+		return row.Start.Format("2 Jan")
+	case models.BudgetPeriodYearly:
+		return row.Start.Format("2006")
+	default:
+		return row.Start.Format("Jan 2006")
+	}
+}
+
+func periodRange(row ReportRow) string {
+	return row.Start.Format("2 Jan 2006") + " - " + row.End.Format("2 Jan 2006")
+}
+
+func money(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
@@ -0,0 +1,201 @@
+package budgets
+
+import (
+	"time"
+
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// MaxReportPeriods is how many periods a report may be split into. Every
+// period costs a spend query per budget and per line.
+const MaxReportPeriods = 120
+
+// Report is how a user's budgets performed over a range of dates, period by
+// period.
+type Report struct {
+	From    time.Time
+	To      time.Time
+	Period  string
+	Budgets []BudgetReport
+}
+
+type BudgetReport struct {
+	BudgetID uint
+	Name     string
+	Rows     []ReportRow
+	Lines    []LineReport
+}
+
+type LineReport struct {
+	LineID     uint
+	CategoryID int
+	Category   string
+	Rows       []ReportRow
+}
+
+// ReportRow is a budget or a line in one period. Variance is what was left
+// of the limit, negative when it was overspent.
+type ReportRow struct {
+	Start      time.Time
+	End        time.Time
+	Limit      float64
+	Actual     float64
+	Variance   float64
+	Percentage float64
+}
+
+// span is a stretch of a budget with one limit: the whole budget, or one
+// period of a recurring budget.
+type span struct {
+	start time.Time
+	end   time.Time
+	limit float64
+}
+
+// BuildReport reports on the user's budgets between two dates, both
+// inclusive, split into periods starting on from. Only budgets running at
+// some point in the range are included, and each only in the periods it
+// runs in. When a budget runs for part of a period, its limit in that period
+// is the part of its limit for the days it runs; recurring budgets use the
+// limit each of their periods had, carryover included. Lines are reported
+// with their current limits.
+func BuildReport(userId uint, from time.Time, to time.Time, period string, db *gorm.DB) (Report, error) {
+	report := Report{From: from, To: to, Period: period, Budgets: []BudgetReport{}}
+	var userBudgets []models.Budget
+	// The dates of a recurring budget are those of its current period, its
+	// earlier periods may still fall in the range.
+	err := db.Where("user_id = ? AND end_date >= ? AND (start_date <= ? OR recurring = ?)", userId, from, to, true).
+		Order("start_date").Order("id").Find(&userBudgets).Error
+	if err != nil {
+		return report, err
+	}
+	tracker := NewTracker(db)
+	tree := userTree(userId, db)
+	for _, budget := range userBudgets {
+		tagIds, lines, err := tracker.load(&budget)
+		if err != nil {
+			return report, err
+		}
+		spans, err := budgetSpans(budget, db)
+		if err != nil {
+			return report, err
+		}
+		first, last := spans[0].start, spans[len(spans)-1].end
+		if first.After(to) {
+			continue
+		}
+		budgetReport := BudgetReport{BudgetID: budget.ID, Name: budget.Name, Rows: []ReportRow{}, Lines: []LineReport{}}
+		for _, line := range lines {
+			lineReport := LineReport{LineID: line.ID, CategoryID: line.CategoryID, Rows: []ReportRow{}}
+			if node, ok := tree.Node(line.CategoryID); ok {
+				lineReport.Category = node.Category.Name
+			}
+			budgetReport.Lines = append(budgetReport.Lines, lineReport)
+		}
+
+		for n := 0; ; n++ {
+			start := PeriodStart(period, 0, from, n)
+			if start.After(to) {
+				break
+			}
+			end := PeriodEnd(period, 0, from, n)
+			if end.After(to) {
+				end = to
+			}
+			if start.After(last) || end.Before(first) {
+				continue
+			}
+			clipped := budget
+			clipped.StartDate = later(start, first)
+			clipped.EndDate = earlier(end, last)
+			spent, err := tracker.compute(&clipped, lines, tagIds, tree)
+			if err != nil {
+				return report, err
+			}
+			limit, share := prorate(spans, clipped.StartDate, clipped.EndDate)
+			budgetReport.Rows = append(budgetReport.Rows, reportRow(start, end, limit, spent.amount))
+			for i, line := range lines {
+				row := reportRow(start, end, line.Amount*share, spent.lines[line.ID])
+				budgetReport.Lines[i].Rows = append(budgetReport.Lines[i].Rows, row)
+			}
+		}
+		report.Budgets = append(report.Budgets, budgetReport)
+	}
+	return report, nil
+}
+
+// budgetSpans splits a budget into the stretches it had one limit for.
+func budgetSpans(budget models.Budget, db *gorm.DB) ([]span, error) {
+	if budget.Recurring {
+		var periods []models.BudgetPeriod
+		if err := db.Where("budget_id = ?", budget.ID).Order("start_date").Find(&periods).Error; err != nil {
+			return nil, err
+		}
+		if len(periods) > 0 {
+			spans := make([]span, len(periods))
+			for i, period := range periods {
+				amount := period.Amount
+				if !period.Closed {
+					amount = budget.Amount
+				}
+				spans[i] = span{start: period.StartDate, end: period.EndDate, limit: amount + period.Carryover}
+			}
+			return spans, nil
+		}
+	}
+	return []span{{start: budget.StartDate, end: budget.EndDate, limit: budget.Amount}}, nil
+}
+
+// prorate works out the limit of a budget between two dates from the spans
+// overlapping them, each giving the part of its limit for the days it has in
+// common with the dates. share is how many times the lines' limits fit in
+// the dates.
+func prorate(spans []span, start time.Time, end time.Time) (limit float64, share float64) {
+	for _, s := range spans {
+		overlap := days(later(s.start, start), earlier(s.end, end))
+		if length := days(s.start, s.end); overlap > 0 && length > 0 {
+			limit += s.limit * overlap / length
+			share += overlap / length
+		}
+	}
+	return limit, share
+}
+
+func reportRow(start time.Time, end time.Time, limit float64, actual float64) ReportRow {
+	row := ReportRow{
+		Start:    start,
+		End:      end,
+		Limit:    utils.RoundToCents(limit),
+		Actual:   utils.RoundToCents(actual),
+		Variance: utils.RoundToCents(limit - actual),
+	}
+	if limit > 0 {
+		row.Percentage = utils.RoundToCents(actual / limit * 100)
+	}
+	return row
+}
+
+// days counts the days from start to end, both included.
+func days(start time.Time, end time.Time) float64 {
+	count := end.Sub(start).Hours()/24 + 1
+	if count < 0 {
+		return 0
+	}
+	return count
+}
+
+func later(a time.Time, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earlier(a time.Time, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,72 @@
+package budgets
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestProrate(t *testing.T) {
+	january := span{start: date(2024, 1, 1), end: date(2024, 1, 31), limit: 310}
+	february := span{start: date(2024, 2, 1), end: date(2024, 2, 29), limit: 290}
+	tests := []struct {
+		name      string
+		spans     []span
+		start     time.Time
+		end       time.Time
+		wantLimit float64
+		wantShare float64
+	}{
+		{"whole span", []span{january}, date(2024, 1, 1), date(2024, 1, 31), 310, 1},
+		{"part of a span", []span{january}, date(2024, 1, 1), date(2024, 1, 10), 100, 10.0 / 31},
+		{"across two spans", []span{january, february}, date(2024, 1, 16), date(2024, 2, 14), 300, 16.0/31 + 14.0/29},
+		{"wider than the spans", []span{january, february}, date(2023, 12, 1), date(2024, 3, 31), 600, 2},
+		{"outside the spans", []span{january}, date(2024, 2, 1), date(2024, 2, 29), 0, 0},
+		{"one day", []span{february}, date(2024, 2, 29), date(2024, 2, 29), 10, 1.0 / 29},
+	}
+	for _, test := range tests {
+		limit, share := prorate(test.spans, test.start, test.end)
+		if math.Abs(limit-test.wantLimit) > 1e-9 || math.Abs(share-test.wantShare) > 1e-9 {
+			t.Errorf("%s: prorate() = %v, %v, want %v, %v", test.name, limit, share, test.wantLimit, test.wantShare)
+		}
+	}
+}
+
+func TestReportRow(t *testing.T) {
+	tests := []struct {
+		limit  float64
+		actual float64
+		want   ReportRow
+	}{
+		{100, 25, ReportRow{Limit: 100, Actual: 25, Variance: 75, Percentage: 25}},
+		{100, 125, ReportRow{Limit: 100, Actual: 125, Variance: -25, Percentage: 125}},
+		{0, 10, ReportRow{Limit: 0, Actual: 10, Variance: -10, Percentage: 0}},
+		{100.0 / 3, 10, ReportRow{Limit: 33.33, Actual: 10, Variance: 23.33, Percentage: 30}},
+		{50, -5, ReportRow{Limit: 50, Actual: -5, Variance: 55, Percentage: -10}},
+	}
+	for _, test := range tests {
+		got := reportRow(time.Time{}, time.Time{}, test.limit, test.actual)
+		if got != test.want {
+			t.Errorf("reportRow(%v, %v) = %+v, want %+v", test.limit, test.actual, got, test.want)
+		}
+	}
+}
+
+func TestDays(t *testing.T) {
+	tests := []struct {
+		start time.Time
+		end   time.Time
+		want  float64
+	}{
+		{date(2024, 1, 1), date(2024, 1, 1), 1},
+		{date(2024, 1, 1), date(2024, 1, 31), 31},
+		{date(2024, 2, 1), date(2024, 2, 29), 29},
+		{date(2024, 1, 2), date(2024, 1, 1), 0},
+		{date(2024, 1, 10), date(2024, 1, 1), 0},
+	}
+	for _, test := range tests {
+		if got := days(test.start, test.end); got != test.want {
+			t.Errorf("days(%s, %s) = %v, want %v", test.start.Format(time.DateOnly), test.end.Format(time.DateOnly), got, test.want)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package budgets
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/categories"
+	"github.com/christo-andrew/haven/pkg/database/scopes"
+	"gorm.io/gorm"
+)
+
+// Tracker works out how much of each budget has been spent from the user's
+// transactions. Spend is computed on read and kept in memory until the
+// budget or anything it is computed from changes, which costs one query per
+// user to check.
+type Tracker struct {
+	db     *gorm.DB
+	mu     sync.Mutex
+	spends map[uint]spend
+	reads  uint64
+}
+
+// maxCachedSpends caps how many budgets a tracker keeps the spend of. Once it
+// is reached, the budgets that were not read in the last half as many reads
+// are dropped, budgets that have been deleted among them.
+const maxCachedSpends = 10000
+
+type spend struct {
+	key    string
+	amount float64
+	// lines maps each line of the budget to its own spend.
+	lines map[uint]float64
+	// read is the tracker's read count when the spend was last read.
+	read uint64
+}
+
+// version is a fingerprint of a user's transactions, splits, transaction
+// tags and categories, see scopes.BudgetSpendVersion.
+type version struct {
+	Transactions    string
+	Splits          string
+	TransactionTags string
+	SplitTags       string
+	Categories      string
+}
+
+type totals struct {
+	Debits  float64
+	Refunds float64
+}
+
+func NewTracker(db *gorm.DB) *Tracker {
+	return &Tracker{db: db, spends: make(map[uint]spend)}
+}
+
+// Fill loads the lines of budgets and what recurring budgets carried over
+// into their current period, and sets the spent amount of the budgets and
+// their lines. A budget covers its category, the categories of its lines
+// and every category below those, along with anything carrying one of its
+// tags; a line covers its category and the categories below it. Spend
+// covered by overlapping lines counts once towards the budget. Refunds give
+// back what they refund, and a spent amount never drops below zero.
+// Transfers are not spending.
+func (tracker *Tracker) Fill(budgets ...*models.Budget) error {
+	versions := make(map[uint]string)
+	trees := make(map[uint]*categories.Tree)
+	for _, budget := range budgets {
+		current, ok := versions[budget.UserId]
+		if !ok {
+			var row version
+			if err := scopes.BudgetSpendVersion(budget.UserId, tracker.db).Scan(&row).Error; err != nil {
+				return err
+			}
+			current = fmt.Sprint(row)
+			versions[budget.UserId] = current
+		}
+		tagIds, lines, err := tracker.load(budget)
+		if err != nil {
+			return err
+		}
+		tree, ok := trees[budget.UserId]
+		if !ok {
+			tree = tracker.tree(budget.UserId)
+			trees[budget.UserId] = tree
+		}
+		if budget.Recurring {
+			var period models.BudgetPeriod
+			tracker.db.Where("budget_id = ? AND start_date = ?", budget.ID, budget.StartDate).First(&period)
+			budget.Carryover = period.Carryover
+		}
+
+		key := fmt.Sprint(current, budget.UpdatedAt, budget.CategoryID, budget.StartDate, budget.EndDate, tagIds)
+		for _, line := range lines {
+			key += fmt.Sprint(line.ID, line.CategoryID)
+		}
+		cached, ok := tracker.cached(budget.ID, key)
+		if !ok {
+			cached, err = tracker.compute(budget, lines, tagIds, tree)
+			if err != nil {
+				return err
+			}
+			cached.key = key
+			tracker.store(budget.ID, cached)
+		}
+
+		budget.SpentAmount = cached.amount
+		for i := range lines {
+			lines[i].SpentAmount = cached.lines[lines[i].ID]
+			if node, ok := tree.Node(lines[i].CategoryID); ok {
+				category := node.Category
+				lines[i].Category = &category
+			}
+		}
+		budget.Categories = lines
+	}
+	return nil
+}
+
+// Spent works out how much of a budget has been spent between its dates
+// without going through the cache, for periods that are being closed.
+func (tracker *Tracker) Spent(budget models.Budget) (float64, error) {
+	tagIds, lines, err := tracker.load(&budget)
+	if err != nil {
+		return 0, err
+	}
+	result, err := tracker.compute(&budget, lines, tagIds, tracker.tree(budget.UserId))
+	return result.amount, err
+}
+
+// load reads the tags and the lines of a budget.
+func (tracker *Tracker) load(budget *models.Budget) ([]int, []models.BudgetCategory, error) {
+	var tagIds []int
+	err := tracker.db.Table("budget_tags").Where("budget_id = ?", budget.ID).Order("tag_id").Pluck("tag_id", &tagIds).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	var lines []models.BudgetCategory
+	err = tracker.db.Where("budget_id = ?", budget.ID).Order("id").Find(&lines).Error
+	return tagIds, lines, err
+}
+
+func (tracker *Tracker) compute(budget *models.Budget, lines []models.BudgetCategory, tagIds []int, tree *categories.Tree) (spend, error) {
+	result := spend{lines: make(map[uint]float64, len(lines))}
+	for _, line := range lines {
+		amount, err := tracker.spent(budget, tree.Descendants(line.CategoryID), nil)
+		if err != nil {
+			return result, err
+		}
+		result.lines[line.ID] = amount
+	}
+	amount, err := tracker.spent(budget, covered(budget, lines, tree), tagIds)
+	result.amount = amount
+	return result, err
+}
+
+// covered lists the categories a budget covers: its own category, those of
+// its lines and every category below those.
+func covered(budget *models.Budget, lines []models.BudgetCategory, tree *categories.Tree) []int {
+	seen := make(map[int]bool)
+	var categoryIds []int
+	cover := func(categoryId int) {
+		for _, id := range tree.Descendants(categoryId) {
+			if !seen[id] {
+				seen[id] = true
+				categoryIds = append(categoryIds, id)
+			}
+		}
+	}
+	if budget.CategoryID != 0 {
+		cover(int(budget.CategoryID))
+	}
+	for _, line := range lines {
+		cover(line.CategoryID)
+	}
+	return categoryIds
+}
+
+func (tracker *Tracker) spent(budget *models.Budget, categoryIds []int, tagIds []int) (float64, error) {
+	var row totals
+	err := scopes.BudgetSpend(budget.UserId, categoryIds, tagIds, budget.StartDate, budget.EndDate, tracker.db).Scan(&row).Error
+	return row.spent(), err
+}
+
+func (tracker *Tracker) tree(userId uint) *categories.Tree {
+	return userTree(userId, tracker.db)
+}
+
+// userTree holds the categories a user sees, with their own names for them.
+func userTree(userId uint, db *gorm.DB) *categories.Tree {
+	var userCategories []models.Category
+	db.Scopes(scopes.VisibleCategories(userId)).Find(&userCategories)
+	var overrides []models.CategoryOverride
+	scopes.GetUserCategoryOverrides(userId, db).Find(&overrides)
+	return categories.NewTree(categories.Personalise(userCategories, overrides))
+}
+
+func (tracker *Tracker) cached(budgetId uint, key string) (spend, bool) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	tracker.reads++
+	cached, ok := tracker.spends[budgetId]
+	if !ok || cached.key != key {
+		return spend{}, false
+	}
+	cached.read = tracker.reads
+	tracker.spends[budgetId] = cached
+	return cached, true
+}
+
+func (tracker *Tracker) store(budgetId uint, cached spend) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	if len(tracker.spends) >= maxCachedSpends {
+		for id, stored := range tracker.spends {
+			if stored.read+maxCachedSpends/2 < tracker.reads {
+				delete(tracker.spends, id)
+			}
+		}
+	}
+	cached.read = tracker.reads
+	tracker.spends[budgetId] = cached
+}
@@ -0,0 +1,173 @@
+package budgets
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/database/scopes"
+	"github.com/christo-andrew/haven/pkg/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	SuggestAverage = "average"
+	SuggestMedian  = "median"
+)
+
+// Suggestion is a limit for a category derived from what was spent in it
+// over the last months. Months counts the months with any spending.
+type Suggestion struct {
+	CategoryID int
+	Category   string
+	Average    float64
+	Median     float64
+	Amount     float64
+	Months     int
+}
+
+// Suggest suggests a monthly limit for each category the user spent in over
+// the months before the current one, using the average or the median of the
+// monthly spend. Months without spending count as nothing spent, so a one-off
+// purchase does not become a monthly limit with the median. Refunds reduce
+// the month they are in. Categories are suggested as they are, without adding
+// up the categories below them, most spent first.
+func Suggest(userId uint, months int, method string, now time.Time, db *gorm.DB) ([]Suggestion, error) {
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	from := current.AddDate(0, -months, 0)
+	var rows []activity
+	if err := scopes.EnvelopeActivity(userId, from, db).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	index := make(map[string]int, months)
+	for i := 0; i < months; i++ {
+		index[from.AddDate(0, i, 0).Format(MonthLayout)] = i
+	}
+	spent := make(map[int][]float64)
+	for _, row := range rows {
+		month, ok := index[row.Month]
+		if !ok || row.CategoryID == 0 {
+			continue
+		}
+		if spent[row.CategoryID] == nil {
+			spent[row.CategoryID] = make([]float64, months)
+		}
+		spent[row.CategoryID][month] += row.Debits - row.Credits
+	}
+
+	tree := userTree(userId, db)
+	suggestions := []Suggestion{}
+	for categoryId, monthly := range spent {
+		suggestion := Suggestion{CategoryID: categoryId}
+		for i := range monthly {
+			monthly[i] = math.Max(monthly[i], 0)
+			if monthly[i] > 0 {
+				suggestion.Months++
+			}
+		}
+		if suggestion.Months == 0 {
+			continue
+		}
+		if node, ok := tree.Node(categoryId); ok {
+			suggestion.Category = node.Category.Name
+		}
+		suggestion.Average = utils.RoundToCents(mean(monthly))
+		suggestion.Median = utils.RoundToCents(median(monthly))
+		suggestion.Amount = suggestion.Average
+		if method == SuggestMedian {
+			suggestion.Amount = suggestion.Median
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Amount != suggestions[j].Amount {
+			return suggestions[i].Amount > suggestions[j].Amount
+		}
+		return suggestions[i].CategoryID < suggestions[j].CategoryID
+	})
+	return suggestions, nil
+}
+
+// Preset is a built-in template that splits income between groups of
+// categories, each group getting a percentage of it.
+type Preset struct {
+	Key         string
+	Name        string
+	Description string
+	Groups      []PresetGroup
+}
+
+type PresetGroup struct {
+	Name       string
+	Percentage float64
+}
+
+var Presets = []Preset{
+	{
+		Key:         "50-30-20",
+		Name:        "50/30/20",
+		Description: "Half of income on needs, 30% on wants and 20% on savings and paying off debt.",
+		Groups: []PresetGroup{
+			{Name: "needs", Percentage: 50},
+			{Name: "wants", Percentage: 30},
+			{Name: "savings", Percentage: 20},
+		},
+	},
+}
+
+// FindPreset looks a preset up by its key.
+func FindPreset(key string) (Preset, bool) {
+	for _, preset := range Presets {
+		if preset.Key == key {
+			return preset, true
+		}
+	}
+	return Preset{}, false
+}
+
+// Lines splits income between the groups of the preset and the amount of
+// each group between its categories. Categories get a part of their group in
+// proportion to their weight, usually what was spent in them before, or an
+// equal part when none of the group's categories has a weight.
+func (preset Preset) Lines(income float64, groups map[string][]int, weights map[int]float64) []models.BudgetCategory {
+	var lines []models.BudgetCategory
+	for _, group := range preset.Groups {
+		categoryIds := groups[group.Name]
+		total := 0.0
+		for _, categoryId := range categoryIds {
+			total += weights[categoryId]
+		}
+		amount := income * group.Percentage / 100
+		for _, categoryId := range categoryIds {
+			share := 1 / float64(len(categoryIds))
+			if total > 0 {
+				share = weights[categoryId] / total
+			}
+			lines = append(lines, models.BudgetCategory{CategoryID: categoryId, Amount: utils.RoundToCents(amount * share)})
+		}
+	}
+	return lines
+}
+
+// TemplateLines works out the lines of a budget started from a template, the
+// lines with a percentage getting that percentage of income. A line merged
+// from lines of both kinds gets its amount and its share of income.
+func TemplateLines(template models.BudgetTemplate, income float64) []models.BudgetCategory {
+	var lines []models.BudgetCategory
+	for _, line := range template.Lines {
+		amount := line.Amount + income*line.Percentage/100
+		lines = append(lines, models.BudgetCategory{CategoryID: line.CategoryID, Amount: utils.RoundToCents(amount)})
+	}
+	return lines
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
@@ -0,0 +1,61 @@
+package categories
+
+import "github.com/christo-andrew/haven/internal/models"
+
+// Default is a category of the seeded catalogue.
+type Default struct {
+	Name     string
+	Color    string
+	Icon     string
+	Children []Default
+}
+
+// Defaults is the catalogue of system categories seeded on migration. Names
+// are matched when seeding, so renaming one here adds a new category rather
+// than renaming the existing one.
+var Defaults = []Default{
+	{Name: models.DefaultCategoryName, Color: "#9E9E9E", Icon: "circle"},
+	{Name: "Income", Color: "#4CAF50", Icon: "banknote", Children: []Default{
+		{Name: "Salary", Color: "#43A047", Icon: "briefcase"},
+		{Name: "Interest", Color: "#66BB6A", Icon: "percent"},
+		{Name: "Refunds", Color: "#81C784", Icon: "rotate-ccw"},
+	}},
+	{Name: "Housing", Color: "#795548", Icon: "home", Children: []Default{
+		{Name: "Rent", Color: "#6D4C41", Icon: "key"},
+		{Name: "Mortgage", Color: "#8D6E63", Icon: "landmark"},
+		{Name: "Maintenance", Color: "#A1887F", Icon: "wrench"},
+	}},
+	{Name: "Utilities", Color: "#00BCD4", Icon: "zap", Children: []Default{
+		{Name: "Electricity", Color: "#00ACC1", Icon: "plug"},
+		{Name: "Water", Color: "#26C6DA", Icon: "droplet"},
+		{Name: "Internet", Color: "#4DD0E1", Icon: "wifi"},
+		{Name: "Phone", Color: "#80DEEA", Icon: "smartphone"},
+	}},
+	{Name: "Food", Color: "#FF9800", Icon: "utensils", Children: []Default{
+		{Name: "Groceries", Color: "#FB8C00", Icon: "shopping-cart"},
+		{Name: "Restaurants", Color: "#FFA726", Icon: "chef-hat"},
+		{Name: "Coffee", Color: "#FFB74D", Icon: "coffee"},
+	}},
+	{Name: "Transport", Color: "#3F51B5", Icon: "car", Children: []Default{
+		{Name: "Fuel", Color: "#3949AB", Icon: "fuel"},
+		{Name: "Public Transport", Color: "#5C6BC0", Icon: "bus"},
+		{Name: "Taxi", Color: "#7986CB", Icon: "car-taxi-front"},
+	}},
+	{Name: "Shopping", Color: "#E91E63", Icon: "shopping-bag", Children: []Default{
+		{Name: "Clothing", Color: "#D81B60", Icon: "shirt"},
+		{Name: "Electronics", Color: "#EC407A", Icon: "monitor"},
+	}},
+	{Name: "Health", Color: "#F44336", Icon: "heart-pulse", Children: []Default{
+		{Name: "Medical", Color: "#E53935", Icon: "stethoscope"},
+		{Name: "Pharmacy", Color: "#EF5350", Icon: "pill"},
+		{Name: "Fitness", Color: "#E57373", Icon: "dumbbell"},
+	}},
+	{Name: "Entertainment", Color: "#9C27B0", Icon: "film", Children: []Default{
+		{Name: "Subscriptions", Color: "#8E24AA", Icon: "repeat"},
+		{Name: "Travel", Color: "#AB47BC", Icon: "plane"},
+	}},
+	{Name: "Education", Color: "#009688", Icon: "graduation-cap"},
+	{Name: "Insurance", Color: "#607D8B", Icon: "shield"},
+	{Name: "Fees", Color: "#FF5722", Icon: "receipt"},
+	{Name: "Transfers", Color: "#CDDC39", Icon: "arrow-left-right"},
+}
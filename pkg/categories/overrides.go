@@ -0,0 +1,35 @@
+package categories
+
+import "github.com/christo-andrew/haven/internal/models"
+
+// Personalise applies a user's overrides to categories, leaving the slice
+// passed in untouched.
+func Personalise(categories []models.Category, overrides []models.CategoryOverride) []models.Category {
+	byCategory := make(map[int]models.CategoryOverride, len(overrides))
+	for _, override := range overrides {
+		byCategory[override.CategoryID] = override
+	}
+	personalised := make([]models.Category, len(categories))
+	for i, category := range categories {
+		if override, ok := byCategory[category.ID]; ok {
+			category = Apply(category, override)
+		}
+		personalised[i] = category
+	}
+	return personalised
+}
+
+// Apply applies one override to a category.
+func Apply(category models.Category, override models.CategoryOverride) models.Category {
+	if override.Name != "" {
+		category.Name = override.Name
+	}
+	if override.Color != "" {
+		category.Color = override.Color
+	}
+	if override.Icon != "" {
+		category.Icon = override.Icon
+	}
+	category.Hidden = override.Hidden
+	return category
+}
@@ -0,0 +1,223 @@
+package categories
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/christo-andrew/haven/internal/models"
+)
+
+// PathSeparator joins the names of a category and its ancestors, as in
+// "Food > Groceries".
+const PathSeparator = " > "
+
+// Node is a category in a tree. Depth is 0 for top level categories.
+type Node struct {
+	Category models.Category
+	Parent   *Node
+	Children []*Node
+	Depth    int
+}
+
+// Tree holds categories by their parents. Categories whose parent is missing
+// from the tree are treated as top level ones, so a tree can be built from
+// any subset of categories.
+type Tree struct {
+	nodes map[int]*Node
+	roots []*Node
+}
+
+func NewTree(categories []models.Category) *Tree {
+	tree := &Tree{nodes: make(map[int]*Node, len(categories))}
+	for _, category := range categories {
+		tree.nodes[category.ID] = &Node{Category: category}
+	}
+	for _, category := range categories {
+		node := tree.nodes[category.ID]
+		parent, ok := tree.parentOf(node)
+		if !ok {
+			tree.roots = append(tree.roots, node)
+			continue
+		}
+		node.Parent = parent
+		parent.Children = append(parent.Children, node)
+	}
+	for _, root := range tree.roots {
+		root.setDepth(0)
+	}
+	sortNodes(tree.roots)
+	return tree
+}
+
+// parentOf returns the parent of a node, ignoring parents that would close a
+// cycle. Moves are validated so cycles should not exist, but a bad row must
+// not hang every report.
+func (tree *Tree) parentOf(node *Node) (*Node, bool) {
+	if node.Category.ParentID == nil {
+		return nil, false
+	}
+	parent, ok := tree.nodes[*node.Category.ParentID]
+	if !ok {
+		return nil, false
+	}
+	seen := map[int]bool{node.Category.ID: true}
+	for ancestor := parent; ancestor != nil; {
+		if seen[ancestor.Category.ID] {
+			return nil, false
+		}
+		seen[ancestor.Category.ID] = true
+		if ancestor.Category.ParentID == nil {
+			break
+		}
+		ancestor = tree.nodes[*ancestor.Category.ParentID]
+	}
+	return parent, true
+}
+
+func (node *Node) setDepth(depth int) {
+	node.Depth = depth
+	sortNodes(node.Children)
+	for _, child := range node.Children {
+		child.setDepth(depth + 1)
+	}
+}
+
+func sortNodes(nodes []*Node) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return strings.ToLower(nodes[i].Category.Name) < strings.ToLower(nodes[j].Category.Name)
+	})
+}
+
+func (tree *Tree) Roots() []*Node {
+	return tree.roots
+}
+
+func (tree *Tree) Node(id int) (*Node, bool) {
+	node, ok := tree.nodes[id]
+	return node, ok
+}
+
+// Ancestors returns the ancestors of a node, starting from its top level
+// category.
+func (node *Node) Ancestors() []*Node {
+	var ancestors []*Node
+	for parent := node.Parent; parent != nil; parent = parent.Parent {
+		ancestors = append([]*Node{parent}, ancestors...)
+	}
+	return ancestors
+}
+
+// Path returns the names of a category and its ancestors joined with
+// PathSeparator.
+func (node *Node) Path() string {
+	var names []string
+	for _, ancestor := range node.Ancestors() {
+		names = append(names, ancestor.Category.Name)
+	}
+	return strings.Join(append(names, node.Category.Name), PathSeparator)
+}
+
+// Descendants returns the ids of a category and every category below it.
+func (tree *Tree) Descendants(id int) []int {
+	node, ok := tree.nodes[id]
+	if !ok {
+		return []int{id}
+	}
+	ids := []int{id}
+	for _, child := range node.Children {
+		ids = append(ids, tree.Descendants(child.Category.ID)...)
+	}
+	return ids
+}
+
+// IsDescendant reports whether a category is below another one, or is that
+// category itself.
+func (tree *Tree) IsDescendant(id int, ancestorId int) bool {
+	for node, ok := tree.nodes[id]; ok && node != nil; node = node.Parent {
+		if node.Category.ID == ancestorId {
+			return true
+		}
+	}
+	return id == ancestorId
+}
+
+// Total is the amount of a category, including the categories rolled up into
+// it.
+type Total struct {
+	Node   *Node
+	Amount float64
+}
+
+// Rollup adds up amounts recorded against individual categories at one level
+// of the tree. Amounts of categories below that level are added to their
+// ancestor at the level, and categories above it report only their own
+// amounts. The level counts from the children of under, or from the top of
+// the tree when under is 0; a negative level reports every category on its
+// own. With under set, only amounts within that category are reported, and
+// those recorded against it directly are reported as its own.
+//
+// Amounts of categories missing from the tree are dropped. Totals are
+// returned largest first.
+func (tree *Tree) Rollup(amounts map[int]float64, under int, level int) []Total {
+	totals := make(map[int]*Total)
+	var order []int
+	for id, amount := range amounts {
+		node, ok := tree.nodes[id]
+		if !ok {
+			continue
+		}
+		group := tree.group(node, under, level)
+		if group == nil {
+			continue
+		}
+		total, ok := totals[group.Category.ID]
+		if !ok {
+			total = &Total{Node: group}
+			totals[group.Category.ID] = total
+			order = append(order, group.Category.ID)
+		}
+		total.Amount += amount
+	}
+	result := make([]Total, 0, len(order))
+	for _, id := range order {
+		result = append(result, *totals[id])
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Amount != result[j].Amount {
+			return result[i].Amount > result[j].Amount
+		}
+		return result[i].Node.Category.ID < result[j].Node.Category.ID
+	})
+	return result
+}
+
+// group returns the node an amount recorded against node is reported under.
+func (tree *Tree) group(node *Node, under int, level int) *Node {
+	if level < 0 {
+		if under != 0 && !tree.IsDescendant(node.Category.ID, under) {
+			return nil
+		}
+		return node
+	}
+	path := append(node.Ancestors(), node)
+	if under != 0 {
+		index := -1
+		for i, ancestor := range path {
+			if ancestor.Category.ID == under {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return nil
+		}
+		if index == len(path)-1 {
+			return node
+		}
+		path = path[index+1:]
+	}
+	if level >= len(path) {
+		return node
+	}
+	return path[level]
+}
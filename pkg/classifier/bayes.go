@@ -0,0 +1,134 @@
+package classifier
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// Prediction is a category and how confident the classifier is in it. The
+// confidences of all categories add up to one.
+type Prediction struct {
+	CategoryID int     `json:"category_id"`
+	Confidence float64 `json:"confidence"`
+}
+
+// NaiveBayes is a multinomial naive Bayes classifier with Laplace smoothing. It
+// can be trained incrementally with Learn and Unlearn and is safe for
+// concurrent use.
+type NaiveBayes struct {
+	mu            sync.RWMutex
+	documents     int
+	classCounts   map[int]int
+	featureCounts map[int]map[string]int
+	featureTotals map[int]int
+	vocabulary    map[string]int
+}
+
+func New() *NaiveBayes {
+	return &NaiveBayes{
+		classCounts:   make(map[int]int),
+		featureCounts: make(map[int]map[string]int),
+		featureTotals: make(map[int]int),
+		vocabulary:    make(map[string]int),
+	}
+}
+
+// Learn records that a transaction with these features belongs to a category.
+func (nb *NaiveBayes) Learn(categoryID int, features []string) {
+	nb.mu.Lock()
+	defer nb.mu.Unlock()
+	nb.documents++
+	nb.classCounts[categoryID]++
+	if nb.featureCounts[categoryID] == nil {
+		nb.featureCounts[categoryID] = make(map[string]int)
+	}
+	for _, feature := range features {
+		nb.featureCounts[categoryID][feature]++
+		nb.featureTotals[categoryID]++
+		nb.vocabulary[feature]++
+	}
+}
+
+// Unlearn reverses an earlier Learn, used when a transaction is recategorised.
+func (nb *NaiveBayes) Unlearn(categoryID int, features []string) {
+	nb.mu.Lock()
+	defer nb.mu.Unlock()
+	if nb.classCounts[categoryID] == 0 {
+		return
+	}
+	nb.documents--
+	nb.classCounts[categoryID]--
+	for _, feature := range features {
+		if nb.featureCounts[categoryID][feature] == 0 {
+			continue
+		}
+		nb.featureCounts[categoryID][feature]--
+		nb.featureTotals[categoryID]--
+		if nb.vocabulary[feature]--; nb.vocabulary[feature] <= 0 {
+			delete(nb.vocabulary, feature)
+		}
+	}
+	if nb.classCounts[categoryID] == 0 {
+		delete(nb.classCounts, categoryID)
+		delete(nb.featureCounts, categoryID)
+		delete(nb.featureTotals, categoryID)
+	}
+}
+
+// Predict returns the most likely categories for the features, best first.
+// Features never seen during training are ignored. A limit of zero returns
+// every category.
+func (nb *NaiveBayes) Predict(features []string, limit int) []Prediction {
+	nb.mu.RLock()
+	defer nb.mu.RUnlock()
+	if nb.documents == 0 {
+		return nil
+	}
+
+	vocabularySize := float64(len(nb.vocabulary))
+	scores := make(map[int]float64, len(nb.classCounts))
+	for categoryID, count := range nb.classCounts {
+		score := math.Log(float64(count) / float64(nb.documents))
+		total := float64(nb.featureTotals[categoryID])
+		for _, feature := range features {
+			if _, known := nb.vocabulary[feature]; !known {
+				continue
+			}
+			score += math.Log((float64(nb.featureCounts[categoryID][feature]) + 1) / (total + vocabularySize))
+		}
+		scores[categoryID] = score
+	}
+
+	predictions := normalise(scores)
+	sort.Slice(predictions, func(i, j int) bool {
+		if predictions[i].Confidence == predictions[j].Confidence {
+			return predictions[i].CategoryID < predictions[j].CategoryID
+		}
+		return predictions[i].Confidence > predictions[j].Confidence
+	})
+	if limit > 0 && len(predictions) > limit {
+		predictions = predictions[:limit]
+	}
+	return predictions
+}
+
+// normalise turns log scores into probabilities with a numerically stable
+// softmax.
+func normalise(scores map[int]float64) []Prediction {
+	best := math.Inf(-1)
+	for _, score := range scores {
+		best = math.Max(best, score)
+	}
+	var sum float64
+	predictions := make([]Prediction, 0, len(scores))
+	for categoryID, score := range scores {
+		weight := math.Exp(score - best)
+		sum += weight
+		predictions = append(predictions, Prediction{CategoryID: categoryID, Confidence: weight})
+	}
+	for i := range predictions {
+		predictions[i].Confidence = math.Round(predictions[i].Confidence/sum*10000) / 10000
+	}
+	return predictions
+}
@@ -0,0 +1,61 @@
+package classifier
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// amountBuckets are the upper bounds used to turn an amount into a feature.
+// Amounts are bucketed on a rough log scale so that a 12.50 coffee and a 14.00
+// coffee look alike while rent does not.
+var amountBuckets = []float64{10, 50, 100, 500, 1000, 5000, 10000, 50000}
+
+// Features extracts the tokens the classifier learns from: the words of the
+// description, the payee and the size of the amount.
+func Features(description string, payee string, amount float64) []string {
+	var features []string
+	for _, token := range Tokenize(description) {
+		features = append(features, "w:"+token)
+	}
+	if payee = strings.ToLower(strings.TrimSpace(payee)); payee != "" {
+		features = append(features, "p:"+payee)
+	}
+	features = append(features, "a:"+amountBucket(amount))
+	return features
+}
+
+// Tokenize splits text into lower case words, dropping numbers and single
+// characters which are mostly references and terminal ids.
+func Tokenize(text string) []string {
+	var tokens []string
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if len(word) < 2 || isNumber(word) {
+			continue
+		}
+		tokens = append(tokens, word)
+	}
+	return tokens
+}
+
+func amountBucket(amount float64) string {
+	amount = math.Abs(amount)
+	for i, bound := range amountBuckets {
+		if amount < bound {
+			return string(rune('0' + i))
+		}
+	}
+	return string(rune('0' + len(amountBuckets)))
+}
+
+func isNumber(word string) bool {
+	for _, r := range word {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
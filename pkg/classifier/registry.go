@@ -0,0 +1,73 @@
+package classifier
+
+import "sync"
+
+// Registry keeps one classifier per user in memory. Classifiers are trained
+// lazily the first time a user asks for a suggestion.
+type Registry struct {
+	mu       sync.Mutex
+	models   map[uint]*NaiveBayes
+	training map[uint]*training
+}
+
+// training is a classifier being trained. Callers asking for the same user
+// meanwhile wait on once rather than training it again.
+type training struct {
+	once  sync.Once
+	model *NaiveBayes
+}
+
+func NewRegistry() *Registry {
+	return &Registry{models: make(map[uint]*NaiveBayes), training: make(map[uint]*training)}
+}
+
+// Get returns the classifier of a user, calling train to build it from the
+// user's history when it is not loaded yet. Training runs outside the lock,
+// so other users are not held up by it.
+func (registry *Registry) Get(userID uint, train func(model *NaiveBayes)) *NaiveBayes {
+	registry.mu.Lock()
+	if model, ok := registry.models[userID]; ok {
+		registry.mu.Unlock()
+		return model
+	}
+	run, ok := registry.training[userID]
+	if !ok {
+		run = &training{}
+		registry.training[userID] = run
+	}
+	registry.mu.Unlock()
+
+	run.once.Do(func() {
+		model := New()
+		train(model)
+		run.model = model
+	})
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	// The user may have been forgotten while the model was trained, in
+	// which case it was trained on data that is out of date.
+	if registry.training[userID] == run {
+		registry.models[userID] = run.model
+		delete(registry.training, userID)
+	}
+	return run.model
+}
+
+// Loaded returns the classifier of a user only if it has already been trained,
+// so that incremental updates are not applied on top of a fresh training run.
+func (registry *Registry) Loaded(userID uint) (*NaiveBayes, bool) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	model, ok := registry.models[userID]
+	return model, ok
+}
+
+// Forget drops the classifier of a user, so that it is trained again from
+// the database. Used when categories are merged away from under it.
+func (registry *Registry) Forget(userID uint) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	delete(registry.models, userID)
+	delete(registry.training, userID)
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"github.com/christo-andrew/haven/pkg/logging"
+	"github.com/christo-andrew/haven/pkg/notifications"
+	"github.com/christo-andrew/haven/pkg/storage"
 	"github.com/christo-andrew/haven/pkg/utils"
 	"github.com/joho/godotenv"
 	"gorm.io/driver/mysql"
@@ -47,11 +49,36 @@ type ServerConfig struct {
 	EnvPath          string
 }
 
+// SchedulerConfig holds the configuration of background jobs
+type SchedulerConfig struct {
+	Enabled           bool
+	RecurringInterval time.Duration
+	BudgetInterval    time.Duration
+	AlertInterval     time.Duration
+}
+
+// StorageConfig holds the configuration of the blob store used for uploads
+type StorageConfig struct {
+	Driver        string
+	LocalPath     string
+	S3            storage.S3Config
+	MaxUploadSize int64
+}
+
+// NotificationConfig holds the configuration of email and webhook delivery
+type NotificationConfig struct {
+	SMTP           notifications.SMTPConfig
+	WebhookTimeout time.Duration
+}
+
 // Config is the root configuration structure
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
+	Server        ServerConfig
+	Database      DatabaseConfig
+	Redis         RedisConfig
+	Scheduler     SchedulerConfig
+	Storage       StorageConfig
+	Notifications NotificationConfig
 }
 
 func (config *Config) Validate() {
@@ -62,6 +89,10 @@ func (config *Config) Validate() {
 	if err := config.Server.Validate(); err != nil {
 		log.Fatal(err)
 	}
+
+	if err := config.Scheduler.Validate(); err != nil {
+		log.Fatal(err)
+	}
 }
 
 func (serverConfig *ServerConfig) Validate() error {
@@ -110,11 +141,83 @@ func New(envPath string) (*Config, error) {
 	}
 
 	return &Config{
-		Server:   getServerConfig(),
-		Database: getDatabaseConfig(),
+		Server:        getServerConfig(),
+		Database:      getDatabaseConfig(),
+		Scheduler:     getSchedulerConfig(),
+		Storage:       getStorageConfig(),
+		Notifications: getNotificationConfig(),
 	}, nil
 }
 
+func getNotificationConfig() NotificationConfig {
+	return NotificationConfig{
+		SMTP: notifications.SMTPConfig{
+			Host:     utils.GetEnvOrDefault("SMTP_HOST", ""),
+			Port:     utils.GetEnvAsIntOrDefault("SMTP_PORT", 587),
+			Username: utils.GetEnvOrDefault("SMTP_USERNAME", ""),
+			Password: utils.GetEnvOrDefault("SMTP_PASSWORD", ""),
+			From:     utils.GetEnvOrDefault("SMTP_FROM", "haven@localhost"),
+		},
+		WebhookTimeout: time.Duration(utils.GetEnvAsIntOrDefault("WEBHOOK_TIMEOUT", 10)) * time.Second,
+	}
+}
+
+// GetNotifier returns a notifier delivering through the configured servers
+func (notificationConfig NotificationConfig) GetNotifier(db *gorm.DB) *notifications.Notifier {
+	return notifications.New(db, notificationConfig.SMTP, notificationConfig.WebhookTimeout)
+}
+
+func getStorageConfig() StorageConfig {
+	return StorageConfig{
+		Driver:    utils.GetEnvOrDefault("STORAGE_DRIVER", "local"),
+		LocalPath: utils.GetEnvOrDefault("STORAGE_LOCAL_PATH", "storage"),
+		S3: storage.S3Config{
+			Endpoint:        utils.GetEnvOrDefault("STORAGE_S3_ENDPOINT", "https://s3.amazonaws.com"),
+			Region:          utils.GetEnvOrDefault("STORAGE_S3_REGION", "us-east-1"),
+			Bucket:          utils.GetEnvOrDefault("STORAGE_S3_BUCKET", ""),
+			AccessKeyID:     utils.GetEnvOrDefault("STORAGE_S3_ACCESS_KEY_ID", ""),
+			SecretAccessKey: utils.GetEnvOrDefault("STORAGE_S3_SECRET_ACCESS_KEY", ""),
+			UsePathStyle:    utils.GetEnvAsBoolOrDefault("STORAGE_S3_USE_PATH_STYLE", true),
+		},
+		MaxUploadSize: int64(utils.GetEnvAsIntOrDefault("STORAGE_MAX_UPLOAD_SIZE", 10<<20)),
+	}
+}
+
+// GetStorage returns the blob store selected by the driver
+func (storageConfig StorageConfig) GetStorage() (storage.Storage, error) {
+	switch storageConfig.Driver {
+	case "local":
+		return storage.NewLocalStorage(storageConfig.LocalPath)
+	case "s3":
+		return storage.NewS3Storage(storageConfig.S3)
+	}
+	return nil, fmt.Errorf("unknown storage driver %q", storageConfig.Driver)
+}
+
+func getSchedulerConfig() SchedulerConfig {
+	return SchedulerConfig{
+		Enabled:           utils.GetEnvAsBoolOrDefault("SCHEDULER_ENABLED", true),
+		RecurringInterval: time.Duration(utils.GetEnvAsIntOrDefault("SCHEDULER_RECURRING_INTERVAL", 3600)) * time.Second,
+		BudgetInterval:    time.Duration(utils.GetEnvAsIntOrDefault("SCHEDULER_BUDGET_INTERVAL", 3600)) * time.Second,
+		AlertInterval:     time.Duration(utils.GetEnvAsIntOrDefault("SCHEDULER_ALERT_INTERVAL", 900)) * time.Second,
+	}
+}
+
+// Validate checks that every job of the scheduler runs at a positive interval
+func (schedulerConfig SchedulerConfig) Validate() error {
+	intervals := map[string]time.Duration{
+		"SCHEDULER_RECURRING_INTERVAL": schedulerConfig.RecurringInterval,
+		"SCHEDULER_BUDGET_INTERVAL":    schedulerConfig.BudgetInterval,
+		"SCHEDULER_ALERT_INTERVAL":     schedulerConfig.AlertInterval,
+	}
+	for name, interval := range intervals {
+		if interval <= 0 {
+			return fmt.Errorf("%s must be a positive number of seconds", name)
+		}
+	}
+	return nil
+}
+
 // DefaultDatabaseConfig returns default database configuration from environment variables
 func getDatabaseConfig() DatabaseConfig {
 	return DatabaseConfig{
@@ -0,0 +1,25 @@
+package database
+
+import (
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/audit"
+	"gorm.io/gorm"
+)
+
+// AuditedModels are the models whose every change is recorded in the audit
+// log.
+var AuditedModels = []interface{}{
+	&models.Account{},
+	&models.Transaction{},
+	&models.Budget{},
+	&models.Category{},
+	&models.Tag{},
+}
+
+// RegisterAudit starts recording changes to the audited models. It must run
+// after Migrate so that the audit log table exists.
+func RegisterAudit(db *gorm.DB) {
+	if err := audit.Register(db, AuditedModels...); err != nil {
+		panic(err)
+	}
+}
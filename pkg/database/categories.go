@@ -0,0 +1,226 @@
+package database
+
+import (
+	"strconv"
+
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/categories"
+	"gorm.io/gorm"
+)
+
+// seedDefaultCategories creates the system categories of the catalogue.
+// System categories that already exist are kept as they are. A shared
+// category from before categories belonged to users is adopted instead of
+// creating a duplicate, so transactions that used it keep their category.
+// Transaction types stay shared and become system categories as they are.
+func seedDefaultCategories(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.Category{}).
+			Where("context_type = ? AND user_id IS NULL", "transaction_types").
+			Update("is_system", true).Error
+		if err != nil {
+			return err
+		}
+		return seedCategories(categories.Defaults, nil, tx)
+	})
+}
+
+func seedCategories(defaults []categories.Default, parentId *int, db *gorm.DB) error {
+	for _, seed := range defaults {
+		var category models.Category
+		db.Where("context = ? AND context_type = ? AND name = ?", "accounts", "transaction_categories", seed.Name).
+			Where("user_id IS NULL").
+			Order("is_system DESC, id").
+			First(&category)
+		if category.ID == 0 {
+			category = models.Category{Context: "accounts", ContextType: "transaction_categories", Name: seed.Name}
+		}
+		if !category.IsSystem {
+			category.IsSystem = true
+			category.ParentID = parentId
+			if category.Color == "" {
+				category.Color = seed.Color
+			}
+			if category.Icon == "" {
+				category.Icon = seed.Icon
+			}
+			if err := db.Save(&category).Error; err != nil {
+				return err
+			}
+		}
+		if err := seedCategories(seed.Children, &category.ID, db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// categoryUsers lists the users whose records refer to a category, deleted
+// records included.
+const categoryUsers = `SELECT accounts.user_id FROM transactions
+				INNER JOIN accounts ON accounts.id = transactions.account_id
+				WHERE transactions.category_id = @id
+			  UNION SELECT accounts.user_id FROM transaction_splits
+				INNER JOIN transactions ON transactions.id = transaction_splits.transaction_id
+				INNER JOIN accounts ON accounts.id = transactions.account_id
+				WHERE transaction_splits.category_id = @id
+			  UNION SELECT user_id FROM recurring_transactions WHERE category_id = @id
+			  UNION SELECT user_id FROM payees WHERE default_category_id = @id
+			  UNION SELECT user_id FROM budgets WHERE category_id = @id
+			  UNION SELECT budgets.user_id FROM budget_categories
+				INNER JOIN budgets ON budgets.id = budget_categories.budget_id
+				WHERE budget_categories.category_id = @id
+			  UNION SELECT user_id FROM envelopes WHERE category_id = @id
+			  UNION SELECT budget_templates.user_id FROM budget_template_lines
+				INNER JOIN budget_templates ON budget_templates.id = budget_template_lines.budget_template_id
+				WHERE budget_template_lines.category_id = @id
+			  UNION SELECT rules.user_id FROM rule_actions
+				INNER JOIN rules ON rules.id = rule_actions.rule_id
+				WHERE rule_actions.type = @type AND rule_actions.value = @value
+			  ORDER BY user_id`
+
+// splitSharedCategories gives every category created before categories
+// belonged to users an owner. A category used by one user is given to that
+// user. A category used by several users is given to the first and copied
+// for each of the others, whose records are moved to their copy. Subcategories
+// end up under their owner's copy of the parent. Categories nobody uses are
+// left without an owner, which hides them from everyone.
+func splitSharedCategories(db *gorm.DB) error {
+	var shared []models.Category
+	db.Unscoped().Where("user_id IS NULL AND is_system = ? AND context_type <> ?", false, "transaction_types").
+		Order("id").Find(&shared)
+	if len(shared) == 0 {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		// copies maps a shared category and a user to the user's copy.
+		copies := make(map[[2]int]int)
+		var owned []models.Category
+		for _, category := range shared {
+			var userIds []uint
+			err := tx.Raw(categoryUsers, map[string]interface{}{
+				"id":    category.ID,
+				"type":  models.RuleActionSetCategory,
+				"value": strconv.Itoa(category.ID),
+			}).Scan(&userIds).Error
+			if err != nil {
+				return err
+			}
+			for i, userId := range userIds {
+				owner := userId
+				if i == 0 {
+					err = tx.Unscoped().Model(&category).Update("user_id", owner).Error
+					category.UserID = &owner
+					owned = append(owned, category)
+					copies[[2]int{category.ID, int(owner)}] = category.ID
+				} else {
+					var copied models.Category
+					copied, err = copyCategory(category, owner, tx)
+					if err == nil {
+						err = moveUserCategoryReferences(category.ID, copied.ID, owner, tx)
+					}
+					owned = append(owned, copied)
+					copies[[2]int{category.ID, int(owner)}] = copied.ID
+				}
+				if err != nil {
+					return err
+				}
+			}
+		}
+		for i := range owned {
+			if err := reparentOwnedCategory(&owned[i], shared, copies, tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func copyCategory(category models.Category, userId uint, db *gorm.DB) (models.Category, error) {
+	copied := models.Category{
+		Name:        category.Name,
+		Description: category.Description,
+		Context:     category.Context,
+		ContextType: category.ContextType,
+		ParentID:    category.ParentID,
+		UserID:      &userId,
+		Color:       category.Color,
+		Icon:        category.Icon,
+	}
+	err := db.Create(&copied).Error
+	return copied, err
+}
+
+// reparentOwnedCategory points a category that was under a shared category at
+// its owner's copy of that parent, copying the parent when the owner only
+// used the subcategory.
+func reparentOwnedCategory(category *models.Category, shared []models.Category, copies map[[2]int]int, db *gorm.DB) error {
+	if category.ParentID == nil {
+		return nil
+	}
+	var parent *models.Category
+	for i := range shared {
+		if shared[i].ID == *category.ParentID {
+			parent = &shared[i]
+			break
+		}
+	}
+	if parent == nil {
+		return nil
+	}
+	key := [2]int{parent.ID, int(*category.UserID)}
+	parentId, ok := copies[key]
+	if !ok {
+		copied, err := copyCategory(*parent, *category.UserID, db)
+		if err != nil {
+			return err
+		}
+		copies[key] = copied.ID
+		parentId = copied.ID
+		if err := reparentOwnedCategory(&copied, shared, copies, db); err != nil {
+			return err
+		}
+	}
+	if parentId == *category.ParentID {
+		return nil
+	}
+	category.ParentID = &parentId
+	return db.Unscoped().Model(category).Update("parent_id", parentId).Error
+}
+
+// moveUserCategoryReferences points one user's records at another category.
+func moveUserCategoryReferences(fromId int, toId int, userId uint, db *gorm.DB) error {
+	userAccounts := db.Model(&models.Account{}).Unscoped().Select("id").Where("user_id = ?", userId)
+	userTransactions := db.Model(&models.Transaction{}).Unscoped().Select("id").Where("account_id IN (?)", userAccounts)
+	userBudgets := db.Model(&models.Budget{}).Unscoped().Select("id").Where("user_id = ?", userId)
+	userRules := db.Model(&models.Rule{}).Unscoped().Select("id").Where("user_id = ?", userId)
+	userTemplates := db.Model(&models.BudgetTemplate{}).Unscoped().Select("id").Where("user_id = ?", userId)
+	updates := []struct {
+		model  interface{}
+		column string
+		where  string
+		owner  interface{}
+	}{
+		{&models.Transaction{}, "category_id", "account_id IN (?)", userAccounts},
+		{&models.TransactionSplit{}, "category_id", "transaction_id IN (?)", userTransactions},
+		{&models.RecurringTransaction{}, "category_id", "user_id = ?", userId},
+		{&models.Payee{}, "default_category_id", "user_id = ?", userId},
+		{&models.Budget{}, "category_id", "user_id = ?", userId},
+		{&models.BudgetCategory{}, "category_id", "budget_id IN (?)", userBudgets},
+		{&models.Envelope{}, "category_id", "user_id = ?", userId},
+		{&models.BudgetTemplateLine{}, "category_id", "budget_template_id IN (?)", userTemplates},
+	}
+	for _, update := range updates {
+		err := db.Unscoped().Model(update.model).
+			Where(update.column+" = ?", fromId).
+			Where(update.where, update.owner).
+			Update(update.column, toId).Error
+		if err != nil {
+			return err
+		}
+	}
+	return db.Unscoped().Model(&models.RuleAction{}).
+		Where("type = ? AND value = ?", models.RuleActionSetCategory, strconv.Itoa(fromId)).
+		Where("rule_id IN (?)", userRules).
+		Update("value", strconv.Itoa(toId)).Error
+}
@@ -10,15 +10,115 @@ func Migrate(db *gorm.DB) {
 		&models.Account{},
 		&models.BankAccount{},
 		&models.Transaction{},
+		&models.TransactionSplit{},
+		&models.Attachment{},
 		&models.CreditCardAccount{},
+		&models.LoanAccount{},
 		&models.RealEstateAccount{},
 		&models.Category{},
+		&models.CategoryOverride{},
 		&models.User{},
 		&models.Tag{},
 		&models.BudgetCategory{},
 		&models.Budget{},
+		&models.BudgetPeriod{},
+		&models.BudgetTemplate{},
+		&models.BudgetTemplateLine{},
+		&models.Goal{},
+		&models.GoalAccount{},
+		&models.Envelope{},
+		&models.EnvelopeAssignment{},
+		&models.BudgetAlert{},
+		&models.Notification{},
+		&models.Rule{},
+		&models.RuleCondition{},
+		&models.RuleAction{},
+		&models.Payee{},
+		&models.PayeeAlias{},
+		&models.RecurringTransaction{},
+		&models.AuditLog{},
 	)
 	if err != nil {
 		panic(err)
 	}
+	if err := createFullTextIndexes(db); err != nil {
+		panic(err)
+	}
+	if err := backfillTransactionStatus(db); err != nil {
+		panic(err)
+	}
+	if err := seedDefaultCategories(db); err != nil {
+		panic(err)
+	}
+	if err := splitSharedCategories(db); err != nil {
+		panic(err)
+	}
+	if err := moveLoanAccounts(db); err != nil {
+		panic(err)
+	}
+}
+
+// moveLoanAccounts moves loans that were created as real estate accounts,
+// before loans had an account type of their own, to loan accounts.
+func moveLoanAccounts(db *gorm.DB) error {
+	var accounts []models.Account
+	err := db.Where("account_type = ? AND base_account_type = ?", "loan", "real_estate_accounts").Find(&accounts).Error
+	if err != nil {
+		return err
+	}
+	for _, account := range accounts {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			loan := models.LoanAccount{}
+			if err := tx.Omit("Account").Create(&loan).Error; err != nil {
+				return err
+			}
+			err := tx.Model(&models.Account{}).Where("id = ?", account.ID).
+				Updates(map[string]interface{}{"base_account_type": "loan_accounts", "base_account_id": loan.ID}).Error
+			if err != nil {
+				return err
+			}
+			return tx.Delete(&models.RealEstateAccount{}, account.BaseAccountID).Error
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillTransactionStatus marks transactions created before statuses were
+// tracked as cleared.
+func backfillTransactionStatus(db *gorm.DB) error {
+	return db.Model(&models.Transaction{}).
+		Where("transaction_status IS NULL OR transaction_status = ?", "").
+		Update("transaction_status", models.TransactionStatusCleared).Error
+}
+
+// fullTextIndexes are the MySQL FULLTEXT indexes used by transaction search.
+// The columns must match the MATCH clauses in pkg/search.
+var fullTextIndexes = []struct {
+	table   string
+	name    string
+	columns string
+}{
+	{"transactions", "idx_transactions_search", "description, payee, reference, notes"},
+	{"tags", "idx_tags_search", "name"},
+}
+
+// createFullTextIndexes adds the full-text indexes on MySQL. Other databases
+// fall back to an in-process index and need nothing.
+func createFullTextIndexes(db *gorm.DB) error {
+	if db.Dialector.Name() != "mysql" {
+		return nil
+	}
+	for _, index := range fullTextIndexes {
+		if db.Migrator().HasIndex(index.table, index.name) {
+			continue
+		}
+		err := db.Exec("CREATE FULLTEXT INDEX " + index.name + " ON " + index.table + " (" + index.columns + ")").Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -11,3 +11,7 @@ func GetRecentTransactions(db *gorm.DB, accountId int, limit int) *gorm.DB {
 func GetAccountTransactionsByCategory(accountId int, categoryId int, db *gorm.DB) *gorm.DB {
 	return db.Where("account_id = ? AND category_id = ?", accountId, categoryId)
 }
+
+func GetUserAccountById(id int, userId uint, db *gorm.DB) *gorm.DB {
+	return db.Where("id = ? AND user_id = ?", id, userId)
+}
@@ -0,0 +1,11 @@
+package scopes
+
+import "gorm.io/gorm"
+
+func GetTransactionAttachments(transactionId int, db *gorm.DB) *gorm.DB {
+	return db.Where("transaction_id = ?", transactionId).Order("created_at ASC")
+}
+
+func GetTransactionAttachmentById(id int, transactionId int, db *gorm.DB) *gorm.DB {
+	return GetTransactionAttachments(transactionId, db).Where("id = ?", id)
+}
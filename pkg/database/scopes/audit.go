@@ -0,0 +1,11 @@
+package scopes
+
+import "gorm.io/gorm"
+
+func GetEntityAuditLog(entity string, entityId int, db *gorm.DB) *gorm.DB {
+	return db.Where("entity = ? AND entity_id = ?", entity, entityId).Order("id DESC")
+}
+
+func GetActorAuditLog(userId uint, db *gorm.DB) *gorm.DB {
+	return db.Where("actor_id = ?", userId).Order("id DESC")
+}
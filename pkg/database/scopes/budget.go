@@ -0,0 +1,115 @@
+package scopes
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// BudgetSpend sums the lines of a user's transactions in any of the
+// categories or carrying any of the tags, split transactions counting at the
+// level of their individual lines. Debits are spent and credits are refunds,
+// transfers between the user's accounts are neither. Both dates are
+// inclusive, a budget ending on the 31st counts everything posted on the 31st.
+func BudgetSpend(userId uint, categoryIds []int, tagIds []int, startDate time.Time, endDate time.Time, db *gorm.DB) *gorm.DB {
+	return budgetSpend("", userId, categoryIds, tagIds, startDate, endDate, db)
+}
+
+// BudgetDailySpend is BudgetSpend day by day, written as 2006-01-02, for the
+// days with anything spent or refunded.
+func BudgetDailySpend(userId uint, categoryIds []int, tagIds []int, startDate time.Time, endDate time.Time, db *gorm.DB) *gorm.DB {
+	return budgetSpend("DATE_FORMAT(transaction_lines.date, '%Y-%m-%d')", userId, categoryIds, tagIds, startDate, endDate, db)
+}
+
+func budgetSpend(day string, userId uint, categoryIds []int, tagIds []int, startDate time.Time, endDate time.Time, db *gorm.DB) *gorm.DB {
+	args := []interface{}{userId, startDate, endDate.AddDate(0, 0, 1)}
+	var matches []string
+	if len(categoryIds) > 0 {
+		matches = append(matches, "transaction_lines.category_id IN ?")
+		args = append(args, categoryIds)
+	}
+	if len(tagIds) > 0 {
+		matches = append(matches, taggedLine)
+		args = append(args, tagIds, tagIds)
+	}
+	if len(matches) == 0 {
+		matches = append(matches, "FALSE")
+	}
+	selectDay, groupByDay := "", ""
+	if day != "" {
+		selectDay = day + " AS day,"
+		groupByDay = " GROUP BY " + day + " ORDER BY day"
+	}
+	query := `SELECT ` + selectDay + `
+				COALESCE(SUM(CASE WHEN ` + isDebit + ` THEN ABS(transaction_lines.amount) ELSE 0 END), 0) AS debits,
+				COALESCE(SUM(CASE WHEN ` + isDebit + ` THEN 0 ELSE ABS(transaction_lines.amount) END), 0) AS refunds
+			  FROM ` + transactionLines + `
+			  INNER JOIN transactions ON transactions.id = transaction_lines.transaction_id
+			  INNER JOIN categories AS transaction_types ON transaction_types.id = transaction_lines.transaction_type_id
+			  INNER JOIN accounts ON accounts.id = transaction_lines.account_id
+			  WHERE accounts.user_id = ? AND transactions.is_transfer = FALSE
+			    AND transaction_lines.date >= ? AND transaction_lines.date < ?
+			    AND (` + strings.Join(matches, " OR ") + `)` + groupByDay + `;`
+
+	return db.Raw(query, args...)
+}
+
+// BudgetSpendVersion selects a fingerprint of everything a user's budget
+// spend depends on: their transactions, splits, transaction tags and
+// categories. It changes whenever any of them is added, changed or removed.
+// Tag links have no timestamps, so they are fingerprinted by a checksum of
+// the links themselves, which changes when a merge swaps one for another.
+func BudgetSpendVersion(userId uint, db *gorm.DB) *gorm.DB {
+	query := `SELECT
+				(SELECT CONCAT(COUNT(*), '|', COALESCE(MAX(transactions.updated_at), ''))
+					FROM transactions
+					INNER JOIN accounts ON accounts.id = transactions.account_id
+					WHERE accounts.user_id = @user AND transactions.deleted_at IS NULL) AS transactions,
+				(SELECT CONCAT(COUNT(*), '|', COALESCE(MAX(transaction_splits.updated_at), ''))
+					FROM transaction_splits
+					INNER JOIN transactions ON transactions.id = transaction_splits.transaction_id
+					INNER JOIN accounts ON accounts.id = transactions.account_id
+					WHERE accounts.user_id = @user AND transaction_splits.deleted_at IS NULL) AS splits,
+				(SELECT CONCAT(COUNT(*), '|', COALESCE(MAX(tags.updated_at), ''), '|',
+						BIT_XOR(CRC32(CONCAT(transaction_tags.transaction_id, ':', transaction_tags.tag_id))))
+					FROM transaction_tags
+					INNER JOIN tags ON tags.id = transaction_tags.tag_id
+					INNER JOIN transactions ON transactions.id = transaction_tags.transaction_id
+					INNER JOIN accounts ON accounts.id = transactions.account_id
+					WHERE accounts.user_id = @user) AS transaction_tags,
+				(SELECT CONCAT(COUNT(*), '|', COALESCE(MAX(tags.updated_at), ''), '|',
+						BIT_XOR(CRC32(CONCAT(transaction_split_tags.transaction_split_id, ':', transaction_split_tags.tag_id))))
+					FROM transaction_split_tags
+					INNER JOIN tags ON tags.id = transaction_split_tags.tag_id
+					INNER JOIN transaction_splits ON transaction_splits.id = transaction_split_tags.transaction_split_id
+					INNER JOIN transactions ON transactions.id = transaction_splits.transaction_id
+					INNER JOIN accounts ON accounts.id = transactions.account_id
+					WHERE accounts.user_id = @user) AS split_tags,
+				(SELECT CONCAT(COUNT(*), '|', COALESCE(MAX(categories.updated_at), ''))
+					FROM categories
+					WHERE (categories.is_system = TRUE OR categories.user_id = @user) AND categories.deleted_at IS NULL) AS categories`
+
+	return db.Raw(query, map[string]interface{}{"user": userId})
+}
+
+// EnvelopeActivity sums the lines of a user's transactions by category and
+// month, written as 2006-01, from the start of the given month. Debits and
+// credits are kept apart; transfers between the user's accounts are neither.
+func EnvelopeActivity(userId uint, from time.Time, db *gorm.DB) *gorm.DB {
+	query := `SELECT
+				COALESCE(transaction_lines.category_id, 0) AS category_id,
+				DATE_FORMAT(transaction_lines.date, '%Y-%m') AS month,
+				COALESCE(SUM(CASE WHEN ` + isDebit + ` THEN ABS(transaction_lines.amount) ELSE 0 END), 0) AS debits,
+				COALESCE(SUM(CASE WHEN ` + isDebit + ` THEN 0 ELSE ABS(transaction_lines.amount) END), 0) AS credits
+			  FROM ` + transactionLines + `
+			  INNER JOIN transactions ON transactions.id = transaction_lines.transaction_id
+			  INNER JOIN categories AS transaction_types ON transaction_types.id = transaction_lines.transaction_type_id
+			  INNER JOIN accounts ON accounts.id = transaction_lines.account_id
+			  WHERE accounts.user_id = ? AND transactions.is_transfer = FALSE
+			    AND transaction_lines.date >= ?
+			  GROUP BY COALESCE(transaction_lines.category_id, 0), DATE_FORMAT(transaction_lines.date, '%Y-%m')
+			  ORDER BY month;`
+
+	return db.Raw(query, userId, from)
+}
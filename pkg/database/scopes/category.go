@@ -13,21 +13,42 @@ func GetTransactionCategories(db *gorm.DB) *gorm.DB {
 	return db.Where("context = 'accounts' AND context_type = 'transaction_categories'")
 }
 
+// VisibleCategories keeps the system categories and those of one user.
+func VisibleCategories(userId uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("categories.is_system = ? OR categories.user_id = ?", true, userId)
+	}
+}
+
+// GetOrCreateTransactionType returns the transaction type with a name.
+// Transaction types are shared by every user.
 func GetOrCreateTransactionType(name string, db *gorm.DB) *models.Category {
 	var transactionType models.Category
 	db.Scopes(GetTransactionTypes).Where("name = ?", name).First(&transactionType)
 	if transactionType.Name == "" {
-		transactionType = models.Category{Name: name, Context: "accounts", ContextType: "transaction_types"}
+		transactionType = models.Category{Name: name, Context: "accounts", ContextType: "transaction_types", IsSystem: true}
 		db.Create(&transactionType)
 	}
 	return &transactionType
 }
 
-func GetOrCreateTransactionCategory(name string, db *gorm.DB) *models.Category {
+// GetOrCreateTransactionCategory returns the category a user means by a name:
+// one of their own categories, a system category they renamed to it or a
+// system category with that name, in that order. Otherwise a new category is
+// created for the user.
+func GetOrCreateTransactionCategory(name string, userId uint, db *gorm.DB) *models.Category {
 	var transactionCategory models.Category
-	db.Scopes(GetTransactionCategories).Where("name = ?", name).First(&transactionCategory)
+	db.Scopes(GetTransactionCategories).Where("user_id = ? AND name = ?", userId, name).First(&transactionCategory)
+	if transactionCategory.Name == "" {
+		db.Scopes(GetTransactionCategories).
+			Where("is_system = ? AND id IN (?)", true, GetUserCategoryOverrides(userId, db).Select("category_id").Where("name = ?", name)).
+			First(&transactionCategory)
+	}
 	if transactionCategory.Name == "" {
-		transactionCategory = models.Category{Name: name, Context: "accounts", ContextType: "transaction_categories"}
+		db.Scopes(GetTransactionCategories).Where("is_system = ? AND name = ?", true, name).First(&transactionCategory)
+	}
+	if transactionCategory.Name == "" {
+		transactionCategory = models.Category{Name: name, Context: "accounts", ContextType: "transaction_categories", UserID: &userId}
 		db.Create(&transactionCategory)
 	}
 	return &transactionCategory
@@ -36,3 +57,7 @@ func GetOrCreateTransactionCategory(name string, db *gorm.DB) *models.Category {
 func GetCategoriesByContextAndContextType(context string, contextType string, db *gorm.DB) *gorm.DB {
 	return db.Where("context = ? AND context_type = ?", context, contextType)
 }
+
+func GetUserCategoryOverrides(userId uint, db *gorm.DB) *gorm.DB {
+	return db.Model(&models.CategoryOverride{}).Where("user_id = ?", userId)
+}
@@ -0,0 +1,30 @@
+package scopes
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// GoalContributions sums the lines of a user's transactions carrying a tag
+// from a date on, leaving out the accounts given. Credits count as money put
+// towards the goal and debits as money taken back out of it. Transfers count:
+// money set aside for a goal is usually moved between the user's own
+// accounts.
+func GoalContributions(userId uint, tagId int, excludedAccountIds []int, from time.Time, db *gorm.DB) *gorm.DB {
+	args := []interface{}{userId, from, []int{tagId}, []int{tagId}}
+	excluded := ""
+	if len(excludedAccountIds) > 0 {
+		excluded = " AND accounts.id NOT IN ?"
+		args = append(args, excludedAccountIds)
+	}
+	query := `SELECT COALESCE(SUM(CASE WHEN ` + isDebit + ` THEN -ABS(transaction_lines.amount) ELSE ABS(transaction_lines.amount) END), 0) AS amount
+			  FROM ` + transactionLines + `
+			  INNER JOIN transactions ON transactions.id = transaction_lines.transaction_id
+			  INNER JOIN categories AS transaction_types ON transaction_types.id = transaction_lines.transaction_type_id
+			  INNER JOIN accounts ON accounts.id = transaction_lines.account_id
+			  WHERE accounts.user_id = ? AND transaction_lines.date >= ?
+			    AND ` + taggedLine + excluded + `;`
+
+	return db.Raw(query, args...)
+}
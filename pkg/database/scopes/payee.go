@@ -0,0 +1,35 @@
+package scopes
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func GetUserPayees(userId uint, db *gorm.DB) *gorm.DB {
+	return db.Preload("Aliases").Preload("DefaultCategory").Where("user_id = ?", userId).Order("name ASC")
+}
+
+func GetUserPayeeById(id int, userId uint, db *gorm.DB) *gorm.DB {
+	return GetUserPayees(userId, db).Where("id = ?", id)
+}
+
+// PayeeSpend totals the money paid to each payee of a user between two dates,
+// largest first.
+func PayeeSpend(userId uint, from time.Time, to time.Time, db *gorm.DB) *gorm.DB {
+	query := `SELECT
+				payees.id AS payee_id,
+				payees.name AS payee,
+				SUM(ABS(transactions.amount)) AS amount,
+				COUNT(transactions.id) AS transactions
+			  FROM transactions
+			  INNER JOIN payees ON payees.id = transactions.payee_id
+			  INNER JOIN categories AS transaction_types ON transactions.transaction_type_id = transaction_types.id
+			  WHERE payees.user_id = ? AND payees.deleted_at IS NULL AND transactions.deleted_at IS NULL
+			    AND transactions.date >= ? AND transactions.date < ?
+			    AND ` + isDebit + `
+			  GROUP BY payees.id, payees.name
+			  ORDER BY amount DESC;`
+
+	return db.Raw(query, userId, from, to)
+}
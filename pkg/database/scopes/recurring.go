@@ -0,0 +1,35 @@
+package scopes
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func GetUserRecurringTransactions(userId uint, db *gorm.DB) *gorm.DB {
+	return db.Preload("Category").Preload("TransactionType").Where("user_id = ?", userId).Order("next_date ASC")
+}
+
+func GetUserRecurringTransactionById(id int, userId uint, db *gorm.DB) *gorm.DB {
+	return GetUserRecurringTransactions(userId, db).Where("id = ?", id)
+}
+
+// GetDueRecurringTransactions returns the active templates of all users with
+// an occurrence on or before the given time that has not been posted.
+func GetDueRecurringTransactions(now time.Time, db *gorm.DB) *gorm.DB {
+	return db.Where("active = ? AND next_date IS NOT NULL AND next_date <= ?", true, now)
+}
+
+func LastRecurringTransactionDate(recurringId int, db *gorm.DB) *gorm.DB {
+	return db.Table("transactions").Select("MAX(date)").Where("recurring_id = ? AND deleted_at IS NULL", recurringId)
+}
+
+// GetUserSubscriptionHistory returns the transactions of a user since the
+// given date that subscription detection looks at.
+func GetUserSubscriptionHistory(userId int, since time.Time, db *gorm.DB) *gorm.DB {
+	return db.Preload("TransactionType").
+		Scopes(UserTransactions(userId)).
+		Where("date >= ? AND is_transfer = ?", since, false).
+		Where(notVoid).
+		Order("date ASC")
+}
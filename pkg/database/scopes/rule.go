@@ -0,0 +1,11 @@
+package scopes
+
+import "gorm.io/gorm"
+
+func GetUserRules(userId uint, db *gorm.DB) *gorm.DB {
+	return db.Preload("Conditions").Preload("Actions").Where("user_id = ?", userId).Order("priority DESC, id ASC")
+}
+
+func GetUserRuleById(id int, userId uint, db *gorm.DB) *gorm.DB {
+	return GetUserRules(userId, db).Where("id = ?", id)
+}
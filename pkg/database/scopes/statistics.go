@@ -7,7 +7,7 @@ func TransactionsTotalThisWeekVsLastWeek(accountId int, db *gorm.DB) *gorm.DB {
     			ROUND(SUM(CASE WHEN transactions.date BETWEEN DATE_ADD(CURDATE(), INTERVAL(1-DAYOFWEEK(CURDATE())) DAY) AND DATE_ADD(CURDATE(), INTERVAL(7-DAYOFWEEK(CURDATE())) DAY) THEN transactions.amount ELSE 0 END), 2) AS this_week,
     			ROUND(SUM(CASE WHEN transactions.date BETWEEN DATE_ADD(CURDATE(), INTERVAL(-6-DAYOFWEEK(CURDATE())) DAY) AND DATE_ADD(CURDATE(), INTERVAL(0-DAYOFWEEK(CURDATE())) DAY) THEN transactions.amount ELSE 0 END), 2) AS last_week
 			  FROM transactions
-			  WHERE account_id = ?;`
+			  WHERE account_id = ? AND deleted_at IS NULL AND ` + notVoid + `;`
 
 	return db.Raw(query, accountId)
 }
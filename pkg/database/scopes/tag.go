@@ -1,16 +1,147 @@
 package scopes
 
 import (
+	"errors"
+	"strconv"
+
 	"github.com/christo-andrew/haven/internal/models"
 	"gorm.io/gorm"
 )
 
-func GetOrCreateTransactionTag(name string, db *gorm.DB) *models.Tag {
+// GetOrCreateTransactionTag returns the user's tag with a name, or a tag
+// with the name that has no owner, creating a tag for the user when there is
+// neither.
+func GetOrCreateTransactionTag(name string, userId uint, db *gorm.DB) (*models.Tag, error) {
 	var tag models.Tag
-	db.Where("name = ?", name).First(&tag)
-	if tag.Name == "" {
-		tag = models.Tag{Name: name}
-		db.Create(&tag)
+	err := db.Where("name = ? AND (user_id = ? OR user_id IS NULL)", name, userId).
+		Order("user_id IS NULL").First(&tag).Error
+	if err == nil {
+		return &tag, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
 	}
-	return &tag
+	tag = models.Tag{Name: name, UserID: &userId}
+	if err := db.Create(&tag).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// tagUsers lists the users whose transactions, splits, accounts, budgets,
+// goals or rules use a tag.
+const tagUsers = `SELECT accounts.user_id FROM transaction_tags
+				INNER JOIN transactions ON transactions.id = transaction_tags.transaction_id
+				INNER JOIN accounts ON accounts.id = transactions.account_id
+				WHERE transaction_tags.tag_id = @id
+			  UNION SELECT accounts.user_id FROM transaction_split_tags
+				INNER JOIN transaction_splits ON transaction_splits.id = transaction_split_tags.transaction_split_id
+				INNER JOIN transactions ON transactions.id = transaction_splits.transaction_id
+				INNER JOIN accounts ON accounts.id = transactions.account_id
+				WHERE transaction_split_tags.tag_id = @id
+			  UNION SELECT accounts.user_id FROM account_tags
+				INNER JOIN accounts ON accounts.id = account_tags.account_id
+				WHERE account_tags.tag_id = @id
+			  UNION SELECT budgets.user_id FROM budget_tags
+				INNER JOIN budgets ON budgets.id = budget_tags.budget_id
+				WHERE budget_tags.tag_id = @id
+			  UNION SELECT user_id FROM goals WHERE tag_id = @id
+			  UNION SELECT rules.user_id FROM rule_actions
+				INNER JOIN rules ON rules.id = rule_actions.rule_id
+				WHERE rule_actions.type = @type AND rule_actions.value = @value AND rule_actions.deleted_at IS NULL`
+
+// GetTagUsers selects the ids of the users that use a tag. Tags are shared,
+// so a tag nobody uses any more can be deleted.
+func GetTagUsers(tagId int, db *gorm.DB) *gorm.DB {
+	return db.Raw(tagUsers, map[string]interface{}{
+		"id":    tagId,
+		"type":  models.RuleActionAddTag,
+		"value": strconv.Itoa(tagId),
+	})
+}
+
+// GetUserTagUsage selects the tags a user created or uses, and the tags that
+// have no owner, with how many of the user's transactions, splits, accounts,
+// budgets and rules carry each.
+func GetUserTagUsage(userId uint, db *gorm.DB) *gorm.DB {
+	query := `SELECT * FROM (
+				SELECT tags.id, tags.name, tags.user_id,
+					(SELECT COUNT(*) FROM transaction_tags
+						INNER JOIN transactions ON transactions.id = transaction_tags.transaction_id
+						INNER JOIN accounts ON accounts.id = transactions.account_id
+						WHERE transaction_tags.tag_id = tags.id AND accounts.user_id = @user
+						  AND transactions.deleted_at IS NULL) AS transactions,
+					(SELECT COUNT(*) FROM transaction_split_tags
+						INNER JOIN transaction_splits ON transaction_splits.id = transaction_split_tags.transaction_split_id
+						INNER JOIN transactions ON transactions.id = transaction_splits.transaction_id
+						INNER JOIN accounts ON accounts.id = transactions.account_id
+						WHERE transaction_split_tags.tag_id = tags.id AND accounts.user_id = @user
+						  AND transaction_splits.deleted_at IS NULL AND transactions.deleted_at IS NULL) AS splits,
+					(SELECT COUNT(*) FROM account_tags
+						INNER JOIN accounts ON accounts.id = account_tags.account_id
+						WHERE account_tags.tag_id = tags.id AND accounts.user_id = @user
+						  AND accounts.deleted_at IS NULL) AS accounts,
+					(SELECT COUNT(*) FROM budget_tags
+						INNER JOIN budgets ON budgets.id = budget_tags.budget_id
+						WHERE budget_tags.tag_id = tags.id AND budgets.user_id = @user
+						  AND budgets.deleted_at IS NULL) AS budgets,
+					(SELECT COUNT(*) FROM rule_actions
+						INNER JOIN rules ON rules.id = rule_actions.rule_id
+						WHERE rule_actions.type = @type AND rule_actions.value = CAST(tags.id AS CHAR)
+						  AND rules.user_id = @user AND rule_actions.deleted_at IS NULL AND rules.deleted_at IS NULL) AS rules
+				FROM tags
+				WHERE tags.deleted_at IS NULL
+			  ) AS tag_usage
+			  WHERE tag_usage.user_id = @user OR tag_usage.user_id IS NULL
+				OR tag_usage.transactions + tag_usage.splits + tag_usage.accounts + tag_usage.budgets + tag_usage.rules > 0
+			  ORDER BY tag_usage.name`
+
+	return db.Raw(query, map[string]interface{}{
+		"user": userId,
+		"type": models.RuleActionAddTag,
+	})
+}
+
+// taggedLine keeps the transaction lines that carry one of the tags, either on
+// their transaction or on their own split.
+const taggedLine = `(transaction_lines.transaction_id IN (SELECT transaction_id FROM transaction_tags WHERE tag_id IN ?)
+				OR transaction_lines.split_id IN (SELECT transaction_split_id FROM transaction_split_tags WHERE tag_id IN ?))`
+
+// taggedTransaction keeps the transactions that carry one of the tags, on
+// themselves or on any of their splits.
+const taggedTransaction = `(transactions.id IN (SELECT transaction_id FROM transaction_tags WHERE tag_id IN ?)
+				OR transactions.id IN (SELECT transaction_splits.transaction_id FROM transaction_splits
+					INNER JOIN transaction_split_tags ON transaction_split_tags.transaction_split_id = transaction_splits.id
+					WHERE transaction_split_tags.tag_id IN ? AND transaction_splits.deleted_at IS NULL))`
+
+// AccountTransactionTotalsByTag sums the transactions of an account by tag. A
+// tagged transaction counts in full, a tagged split counts its own amount,
+// unless its transaction already carries the same tag.
+func AccountTransactionTotalsByTag(accountId int, db *gorm.DB) *gorm.DB {
+	query := `SELECT
+				tags.id AS id,
+				tags.name AS name,
+				SUM(tag_lines.amount) AS amount
+			  FROM (
+				SELECT transaction_tags.tag_id, transactions.account_id, transactions.amount
+				FROM transaction_tags
+				INNER JOIN transactions ON transactions.id = transaction_tags.transaction_id
+				WHERE transactions.deleted_at IS NULL AND ` + notVoid + `
+				UNION ALL
+				SELECT transaction_split_tags.tag_id, transactions.account_id, transaction_splits.amount
+				FROM transaction_split_tags
+				INNER JOIN transaction_splits ON transaction_splits.id = transaction_split_tags.transaction_split_id
+				INNER JOIN transactions ON transactions.id = transaction_splits.transaction_id
+				WHERE transaction_splits.deleted_at IS NULL AND transactions.deleted_at IS NULL AND ` + notVoid + `
+				  AND NOT EXISTS (
+					SELECT 1 FROM transaction_tags
+					WHERE transaction_tags.transaction_id = transactions.id AND transaction_tags.tag_id = transaction_split_tags.tag_id
+				  )
+			  ) AS tag_lines
+			  INNER JOIN tags ON tags.id = tag_lines.tag_id AND tags.deleted_at IS NULL
+			  WHERE tag_lines.account_id = ?
+			  GROUP BY tags.id, tags.name
+			  ORDER BY amount DESC;`
+
+	return db.Raw(query, accountId)
 }
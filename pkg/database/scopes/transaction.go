@@ -5,7 +5,45 @@ import (
 	"time"
 )
 
-func AccountTransactionsByYearAndMonth(accountId int, year int, db *gorm.DB) *gorm.DB {
+// isDebit mirrors models.Transaction.Direction in SQL. It expects the
+// transaction type to be joined as transaction_types.
+const isDebit = `(LOWER(transaction_types.name) IN ('debit', 'withdraw')
+				OR (LOWER(transaction_types.name) NOT IN ('credit', 'deposit') AND transactions.amount < 0))`
+
+// notVoid excludes voided transactions from totals.
+const notVoid = `COALESCE(transactions.transaction_status, '') <> 'void'`
+
+// transactionLines is a derived table with one row per category line. A split
+// transaction contributes one row per split, every other transaction
+// contributes itself, so category aggregates never count a split twice.
+// split_id is only set on the rows of splits.
+const transactionLines = `(
+				SELECT transactions.id AS transaction_id, NULL AS split_id, transactions.account_id, transactions.date,
+					transactions.transaction_type_id, transactions.category_id, transactions.amount
+				FROM transactions
+				WHERE transactions.deleted_at IS NULL AND ` + notVoid + `
+				  AND NOT EXISTS (
+					SELECT 1 FROM transaction_splits
+					WHERE transaction_splits.transaction_id = transactions.id AND transaction_splits.deleted_at IS NULL
+				  )
+				UNION ALL
+				SELECT transactions.id, transaction_splits.id, transactions.account_id, transactions.date,
+					transactions.transaction_type_id, transaction_splits.category_id, transaction_splits.amount
+				FROM transaction_splits
+				INNER JOIN transactions ON transactions.id = transaction_splits.transaction_id
+				WHERE transaction_splits.deleted_at IS NULL AND transactions.deleted_at IS NULL AND ` + notVoid + `
+			  ) AS transaction_lines`
+
+// AccountTransactionsByYearAndMonth sums an account's transactions by month and
+// transaction type. With tags given, only transactions carrying one of them
+// count.
+func AccountTransactionsByYearAndMonth(accountId int, year int, tagIds []int, db *gorm.DB) *gorm.DB {
+	args := []interface{}{accountId, year}
+	tagged := ""
+	if len(tagIds) > 0 {
+		tagged = " AND " + taggedTransaction
+		args = append(args, tagIds, tagIds)
+	}
 	query := `SELECT
 				MONTH(transactions.date) as month,
 				YEAR(transactions.date) as year,
@@ -14,11 +52,11 @@ func AccountTransactionsByYearAndMonth(accountId int, year int, db *gorm.DB) *go
 			  FROM transactions
 			  INNER JOIN categories AS transaction_types ON transactions.transaction_type_id = transaction_types.id
 			  INNER JOIN categories AS transaction_categories ON transactions.category_id = transaction_categories.id
-			  WHERE transactions.account_id = ? AND YEAR(transactions.date) = ?
+			  WHERE transactions.account_id = ? AND YEAR(transactions.date) = ? AND ` + notVoid + tagged + `
 			  GROUP BY MONTH(transactions.date),YEAR(transactions.date), transaction_type
 			  ORDER BY MONTH(transactions.date) ASC, YEAR(transactions.date) DESC;`
 
-	return db.Raw(query, accountId, year)
+	return db.Raw(query, args...)
 }
 
 func TotalAmountByTransactionTypeGroupedByYearAndMonth(accountId int, db *gorm.DB) *gorm.DB {
@@ -36,44 +74,76 @@ func TotalAmountByTransactionTypeGroupedByYearAndMonth(accountId int, db *gorm.D
 }
 
 func GetTransactionById(id int, db *gorm.DB) *gorm.DB {
-	return db.Preload("TransactionType").Preload("Category").Where("id = ?", id)
+	return db.Preload("TransactionType").Preload("Category").Preload("Splits.Category").Preload("Splits.Tags").Where("id = ?", id)
 }
 
-func GroupAccountTransactionsByTransactionCategory(accountId int, db *gorm.DB) *gorm.DB {
+func GetUserTransactionById(id int, userId int, db *gorm.DB) *gorm.DB {
+	return GetTransactionById(id, db).Scopes(UserTransactions(userId))
+}
+
+func UserTransactions(userId int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("transactions.account_id IN (SELECT id FROM accounts WHERE user_id = ? AND deleted_at IS NULL)", userId)
+	}
+}
+
+// AccountTransactionTotalsByCategory sums the category lines of an account
+// for each category. Categories are not rolled up into their parents here;
+// callers that report on the category tree do that. With tags given, only
+// lines carrying one of them count.
+func AccountTransactionTotalsByCategory(accountId int, tagIds []int, db *gorm.DB) *gorm.DB {
+	args := []interface{}{accountId}
+	tagged := ""
+	if len(tagIds) > 0 {
+		tagged = " AND " + taggedLine
+		args = append(args, tagIds, tagIds)
+	}
 	query := `SELECT
-				transaction_categories.name AS category,
-				SUM(transactions.amount) AS amount
-			  FROM transactions
-			  INNER JOIN categories AS transaction_types ON transactions.transaction_type_id = transaction_types.id
-			  INNER JOIN categories AS transaction_categories ON transactions.category_id = transaction_categories.id
-			  WHERE transactions.account_id = ?
-			  GROUP BY transaction_categories.name;`
+				transaction_lines.category_id AS category_id,
+				SUM(transaction_lines.amount) AS amount
+			  FROM ` + transactionLines + `
+			  INNER JOIN categories AS transaction_types ON transaction_lines.transaction_type_id = transaction_types.id
+			  WHERE transaction_lines.account_id = ?` + tagged + `
+			  GROUP BY transaction_lines.category_id;`
+
+	return db.Raw(query, args...)
+}
 
-	return db.Raw(query, accountId)
+// WithTransactionStatus keeps the transactions in any of the given statuses.
+func WithTransactionStatus(statuses []string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("transactions.transaction_status IN ?", statuses)
+	}
 }
 
-func PercentageOfTotalAmountByTransactionCategory(accountId int, limit int, db *gorm.DB) *gorm.DB {
+// AccountTotalsByStatus sums the signed amounts of an account's transactions
+// for each status, along with the money that went out.
+func AccountTotalsByStatus(accountId int, db *gorm.DB) *gorm.DB {
 	query := `SELECT
-    transaction_categories.name AS category,
-    	SUM(ROUND(transactions.amount)) AS amount,
-    	ROUND(SUM(transactions.amount) / (SELECT SUM(amount) FROM transactions WHERE account_id = ?) * 100, 2) AS percentage
-	FROM transactions
-			 INNER JOIN categories AS transaction_types ON transactions.transaction_type_id = transaction_types.id
-			 INNER JOIN categories AS transaction_categories ON transactions.category_id = transaction_categories.id
-	WHERE transactions.account_id = ?
-	GROUP BY transaction_categories.name
-	ORDER BY percentage DESC
-	LIMIT ?;`
+				COALESCE(NULLIF(transactions.transaction_status, ''), 'cleared') AS status,
+				SUM(CASE WHEN ` + isDebit + ` THEN -ABS(transactions.amount) ELSE ABS(transactions.amount) END) AS amount,
+				SUM(CASE WHEN ` + isDebit + ` THEN ABS(transactions.amount) ELSE 0 END) AS debits
+			  FROM transactions
+			  INNER JOIN categories AS transaction_types ON transactions.transaction_type_id = transaction_types.id
+			  WHERE transactions.account_id = ? AND transactions.deleted_at IS NULL
+			  GROUP BY COALESCE(NULLIF(transactions.transaction_status, ''), 'cleared');`
 
-	return db.Raw(query, accountId, accountId, limit)
+	return db.Raw(query, accountId)
 }
 
 func GetTransactionByIdWithTags(transactionId int, db *gorm.DB) *gorm.DB {
 	return db.Preload("Tags").Where("id = ?", transactionId)
 }
 
+// GetCategorisedTransactions returns the transactions of a user that have been
+// given a category other than the catch-all one.
+func GetCategorisedTransactions(userId int, uncategorised string, db *gorm.DB) *gorm.DB {
+	return db.Scopes(UserTransactions(userId)).
+		Where("category_id NOT IN (SELECT id FROM categories WHERE name = ?)", uncategorised)
+}
+
 func GetAllTransactions(db *gorm.DB) *gorm.DB {
-	return db.Preload("TransactionType").Preload("Category")
+	return db.Preload("TransactionType").Preload("Category").Preload("Splits.Category")
 }
 
 func GetTransactionsByAccountId(accountId int, db *gorm.DB) *gorm.DB {
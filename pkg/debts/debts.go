@@ -0,0 +1,235 @@
+// Package debts plans paying off several debts out of one monthly budget.
+package debts
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/christo-andrew/haven/pkg/utils"
+)
+
+const (
+	Snowball  = "snowball"
+	Avalanche = "avalanche"
+	Custom    = "custom"
+)
+
+// maxMonths caps a plan at 50 years. A budget that only just covers the
+// interest would otherwise never pay anything off.
+const maxMonths = 600
+
+// ErrBudgetTooLow is returned when the monthly budget does not cover the
+// minimum payments.
+var ErrBudgetTooLow = errors.New("the monthly budget does not cover the minimum payments")
+
+// Debt is what is owed on an account. Rate is the yearly interest rate in
+// percent, charged monthly on what is still owed.
+type Debt struct {
+	AccountID int
+	Name      string
+	Balance   float64
+	Rate      float64
+	Minimum   float64
+}
+
+// Plan is how the debts get paid off when the budget left after the minimum
+// payments goes to one debt at a time, in Order, moving on to the next debt
+// once one is paid off. PaidOff is false when the debts are not paid off
+// within 50 years, DebtFree is then left empty.
+type Plan struct {
+	Strategy      string
+	Order         []int
+	Months        int
+	PaidOff       bool
+	DebtFree      *time.Time
+	TotalInterest float64
+	TotalPaid     float64
+	Debts         []DebtResult
+	Schedule      []Month
+}
+
+// DebtResult is how one debt gets paid off; PaidOffIn is the month of its
+// last payment, empty if it is not paid off.
+type DebtResult struct {
+	AccountID int
+	Name      string
+	Interest  float64
+	Paid      float64
+	PaidOffIn *time.Time
+}
+
+type Month struct {
+	Month    time.Time
+	Payments []Payment
+	Paid     float64
+	Interest float64
+	Balance  float64
+}
+
+// Payment is what is paid on a debt in a month, the interest charged on it
+// that month and what is owed after the payment.
+type Payment struct {
+	AccountID int
+	Interest  float64
+	Payment   float64
+	Balance   float64
+}
+
+// SnowballOrder pays the smallest debt first, the highest rate first among
+// debts of the same size.
+func SnowballOrder(debts []Debt) []int {
+	return order(debts, func(a Debt, b Debt) bool {
+		if a.Balance != b.Balance {
+			return a.Balance < b.Balance
+		}
+		return a.Rate > b.Rate
+	})
+}
+
+// AvalancheOrder pays the debt with the highest rate first, the smallest
+// first among debts with the same rate.
+func AvalancheOrder(debts []Debt) []int {
+	return order(debts, func(a Debt, b Debt) bool {
+		if a.Rate != b.Rate {
+			return a.Rate > b.Rate
+		}
+		return a.Balance < b.Balance
+	})
+}
+
+// CustomOrder pays the debts in the order given, followed by any debts left
+// out of it in avalanche order.
+func CustomOrder(debts []Debt, accountIds []int) []int {
+	result := append([]int(nil), accountIds...)
+	given := make(map[int]bool, len(accountIds))
+	for _, accountId := range accountIds {
+		given[accountId] = true
+	}
+	for _, accountId := range AvalancheOrder(debts) {
+		if !given[accountId] {
+			result = append(result, accountId)
+		}
+	}
+	return result
+}
+
+func order(debts []Debt, less func(a Debt, b Debt) bool) []int {
+	sorted := append([]Debt(nil), debts...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return less(sorted[i], sorted[j])
+	})
+	result := make([]int, len(sorted))
+	for i, debt := range sorted {
+		result[i] = debt.AccountID
+	}
+	return result
+}
+
+// MinimumPayments adds up the minimum payments of the debts in the first
+// month, a debt smaller than its minimum payment needing only its balance.
+func MinimumPayments(debts []Debt) float64 {
+	total := 0.0
+	for _, debt := range debts {
+		total += math.Min(debt.Minimum, debt.Balance)
+	}
+	return utils.RoundToCents(total)
+}
+
+// Simulate pays the debts off month by month from start with the same
+// budget every month. Each month interest is charged on what is owed, every
+// debt gets its minimum payment, or what is left of it if less, and the rest
+// of the budget goes to the debts in the order given. The minimum payment of
+// a debt that has been paid off goes to the next debt in line.
+func Simulate(strategy string, debts []Debt, payoffOrder []int, budget float64, start time.Time) (Plan, error) {
+	plan := Plan{Strategy: strategy, Order: payoffOrder, Debts: []DebtResult{}, Schedule: []Month{}}
+	if MinimumPayments(debts) > budget {
+		return plan, ErrBudgetTooLow
+	}
+	balances := make(map[int]float64, len(debts))
+	results := make(map[int]*DebtResult, len(debts))
+	for _, debt := range debts {
+		balances[debt.AccountID] = debt.Balance
+		plan.Debts = append(plan.Debts, DebtResult{AccountID: debt.AccountID, Name: debt.Name})
+	}
+	for i := range plan.Debts {
+		results[plan.Debts[i].AccountID] = &plan.Debts[i]
+	}
+
+	owed := func() float64 {
+		total := 0.0
+		for _, balance := range balances {
+			total += balance
+		}
+		return total
+	}
+	for month := 0; owed() > 0 && month < maxMonths; month++ {
+		current := Month{Month: start.AddDate(0, month, 0), Payments: []Payment{}}
+		payments := make(map[int]*Payment, len(debts))
+		left := budget
+		for _, debt := range debts {
+			balance := balances[debt.AccountID]
+			if balance <= 0 {
+				continue
+			}
+			payment := &Payment{AccountID: debt.AccountID}
+			payment.Interest = utils.RoundToCents(balance * debt.Rate / 100 / 12)
+			balance += payment.Interest
+			payment.Payment = utils.RoundToCents(math.Min(debt.Minimum, balance))
+			balances[debt.AccountID] = utils.RoundToCents(balance - payment.Payment)
+			left -= payment.Payment
+			payments[debt.AccountID] = payment
+		}
+		for _, accountId := range payoffOrder {
+			payment, ok := payments[accountId]
+			if !ok || left <= 0 {
+				continue
+			}
+			extra := utils.RoundToCents(math.Min(left, balances[accountId]))
+			payment.Payment = utils.RoundToCents(payment.Payment + extra)
+			balances[accountId] = utils.RoundToCents(balances[accountId] - extra)
+			left -= extra
+		}
+		for _, debt := range debts {
+			payment, ok := payments[debt.AccountID]
+			if !ok {
+				continue
+			}
+			payment.Balance = balances[debt.AccountID]
+			current.Payments = append(current.Payments, *payment)
+			current.Paid += payment.Payment
+			current.Interest += payment.Interest
+			current.Balance += payment.Balance
+			result := results[debt.AccountID]
+			result.Interest += payment.Interest
+			result.Paid += payment.Payment
+			if payment.Balance <= 0 {
+				paidOffIn := current.Month
+				result.PaidOffIn = &paidOffIn
+			}
+		}
+		current.Paid = utils.RoundToCents(current.Paid)
+		current.Interest = utils.RoundToCents(current.Interest)
+		current.Balance = utils.RoundToCents(current.Balance)
+		plan.TotalPaid += current.Paid
+		plan.TotalInterest += current.Interest
+		plan.Schedule = append(plan.Schedule, current)
+	}
+
+	plan.Months = len(plan.Schedule)
+	plan.TotalPaid = utils.RoundToCents(plan.TotalPaid)
+	plan.TotalInterest = utils.RoundToCents(plan.TotalInterest)
+	for i := range plan.Debts {
+		plan.Debts[i].Interest = utils.RoundToCents(plan.Debts[i].Interest)
+		plan.Debts[i].Paid = utils.RoundToCents(plan.Debts[i].Paid)
+	}
+	if owed() <= 0 {
+		plan.PaidOff = true
+		if plan.Months > 0 {
+			debtFree := plan.Schedule[plan.Months-1].Month
+			plan.DebtFree = &debtFree
+		}
+	}
+	return plan, nil
+}
@@ -0,0 +1,195 @@
+package debts
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestOrders(t *testing.T) {
+	debts := []Debt{
+		{AccountID: 1, Balance: 1000, Rate: 20, Minimum: 25},
+		{AccountID: 2, Balance: 300, Rate: 5, Minimum: 25},
+		{AccountID: 3, Balance: 300, Rate: 10, Minimum: 10},
+		{AccountID: 4, Balance: 500, Rate: 10, Minimum: 15},
+	}
+	tests := []struct {
+		name string
+		got  []int
+		want []int
+	}{
+		{"snowball", SnowballOrder(debts), []int{3, 2, 4, 1}},
+		{"avalanche", AvalancheOrder(debts), []int{1, 3, 4, 2}},
+		{"custom", CustomOrder(debts, []int{2, 4}), []int{2, 4, 1, 3}},
+		{"custom left out", CustomOrder(debts, nil), []int{1, 3, 4, 2}},
+	}
+	for _, test := range tests {
+		if !reflect.DeepEqual(test.got, test.want) {
+			t.Errorf("%s order = %v, want %v", test.name, test.got, test.want)
+		}
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	tests := []struct {
+		name      string
+		debts     []Debt
+		order     []int
+		budget    float64
+		months    int
+		paidOff   bool
+		totalPaid float64
+		interest  float64
+		// paidOffIn is the month each debt is paid off in, counted from one,
+		// or zero when it is not.
+		paidOffIn map[int]int
+	}{
+		{
+			name:      "payoff rolls over to the next debt",
+			debts:     []Debt{{AccountID: 1, Balance: 100, Minimum: 10}, {AccountID: 2, Balance: 200, Minimum: 20}},
+			order:     []int{1, 2},
+			budget:    100,
+			months:    3,
+			paidOff:   true,
+			totalPaid: 300,
+			paidOffIn: map[int]int{1: 2, 2: 3},
+		},
+		{
+			name:      "the other order",
+			debts:     []Debt{{AccountID: 1, Balance: 100, Minimum: 10}, {AccountID: 2, Balance: 200, Minimum: 20}},
+			order:     []int{2, 1},
+			budget:    100,
+			months:    3,
+			paidOff:   true,
+			totalPaid: 300,
+			paidOffIn: map[int]int{1: 3, 2: 3},
+		},
+		{
+			name:      "interest only never pays off",
+			debts:     []Debt{{AccountID: 1, Balance: 1200, Rate: 12, Minimum: 12}},
+			order:     []int{1},
+			budget:    12,
+			months:    maxMonths,
+			paidOff:   false,
+			totalPaid: 12 * maxMonths,
+			interest:  12 * maxMonths,
+			paidOffIn: map[int]int{1: 0},
+		},
+		{
+			name:      "balance below its minimum",
+			debts:     []Debt{{AccountID: 1, Balance: 30, Minimum: 50}},
+			order:     []int{1},
+			budget:    50,
+			months:    1,
+			paidOff:   true,
+			totalPaid: 30,
+			paidOffIn: map[int]int{1: 1},
+		},
+		{
+			name:      "balance below its minimum with interest",
+			debts:     []Debt{{AccountID: 1, Balance: 30, Rate: 12, Minimum: 50}},
+			order:     []int{1},
+			budget:    50,
+			months:    1,
+			paidOff:   true,
+			totalPaid: 30.3,
+			interest:  0.3,
+			paidOffIn: map[int]int{1: 1},
+		},
+	}
+	for _, test := range tests {
+		plan, err := Simulate(Custom, test.debts, test.order, test.budget, start)
+		if err != nil {
+			t.Errorf("%s: Simulate() error = %v", test.name, err)
+			continue
+		}
+		if plan.Months != test.months || plan.PaidOff != test.paidOff || plan.TotalPaid != test.totalPaid || plan.TotalInterest != test.interest {
+			t.Errorf("%s: Simulate() = %d months, paid off %v, %v paid, %v interest, want %d, %v, %v, %v", test.name,
+				plan.Months, plan.PaidOff, plan.TotalPaid, plan.TotalInterest, test.months, test.paidOff, test.totalPaid, test.interest)
+		}
+		if test.paidOff != (plan.DebtFree != nil) {
+			t.Errorf("%s: DebtFree = %v, want it set %v", test.name, plan.DebtFree, test.paidOff)
+		}
+		for _, result := range plan.Debts {
+			want := test.paidOffIn[result.AccountID]
+			switch {
+			case want == 0 && result.PaidOffIn != nil:
+				t.Errorf("%s: debt %d paid off in %s, want not paid off", test.name, result.AccountID, result.PaidOffIn.Format("2006-01"))
+			case want != 0 && (result.PaidOffIn == nil || !result.PaidOffIn.Equal(start.AddDate(0, want-1, 0))):
+				t.Errorf("%s: debt %d paid off in %v, want %s", test.name, result.AccountID, result.PaidOffIn, start.AddDate(0, want-1, 0).Format("2006-01"))
+			}
+		}
+	}
+}
+
+func TestSimulateRollover(t *testing.T) {
+	debts := []Debt{{AccountID: 1, Balance: 100, Minimum: 10}, {AccountID: 2, Balance: 200, Minimum: 20}}
+	plan, err := Simulate(Snowball, debts, SnowballOrder(debts), 100, start)
+	if err != nil {
+		t.Fatalf("Simulate() error = %v", err)
+	}
+	// In the second month the first debt needs 20 to be paid off, the 60
+	// left over goes to the second on top of its minimum.
+	want := []Payment{{AccountID: 1, Payment: 20, Balance: 0}, {AccountID: 2, Payment: 80, Balance: 100}}
+	if got := plan.Schedule[1].Payments; !reflect.DeepEqual(got, want) {
+		t.Errorf("second month payments = %+v, want %+v", got, want)
+	}
+	// The first debt is paid off and gets nothing in the third month.
+	want = []Payment{{AccountID: 2, Payment: 100, Balance: 0}}
+	if got := plan.Schedule[2].Payments; !reflect.DeepEqual(got, want) {
+		t.Errorf("third month payments = %+v, want %+v", got, want)
+	}
+}
+
+func TestSimulateStrategies(t *testing.T) {
+	debts := []Debt{
+		{AccountID: 1, Balance: 5000, Rate: 24, Minimum: 100},
+		{AccountID: 2, Balance: 800, Rate: 6, Minimum: 30},
+		{AccountID: 3, Balance: 2500, Rate: 15, Minimum: 60},
+	}
+	snowball, err := Simulate(Snowball, debts, SnowballOrder(debts), 500, start)
+	if err != nil {
+		t.Fatalf("Simulate(snowball) error = %v", err)
+	}
+	avalanche, err := Simulate(Avalanche, debts, AvalancheOrder(debts), 500, start)
+	if err != nil {
+		t.Fatalf("Simulate(avalanche) error = %v", err)
+	}
+	if !snowball.PaidOff || !avalanche.PaidOff {
+		t.Fatalf("plans paid off = %v, %v, want both", snowball.PaidOff, avalanche.PaidOff)
+	}
+	if avalanche.TotalInterest >= snowball.TotalInterest {
+		t.Errorf("avalanche interest %v, want less than snowball's %v", avalanche.TotalInterest, snowball.TotalInterest)
+	}
+	// Snowball pays the smallest debt off first, avalanche the dearest.
+	if first := firstPaidOff(snowball); first != 2 {
+		t.Errorf("snowball pays off debt %d first, want 2", first)
+	}
+	if first := firstPaidOff(avalanche); first != 1 {
+		t.Errorf("avalanche pays off debt %d first, want 1", first)
+	}
+}
+
+func firstPaidOff(plan Plan) int {
+	first := 0
+	var month time.Time
+	for _, result := range plan.Debts {
+		if result.PaidOffIn != nil && (first == 0 || result.PaidOffIn.Before(month)) {
+			first, month = result.AccountID, *result.PaidOffIn
+		}
+	}
+	return first
+}
+
+func TestSimulateBudgetTooLow(t *testing.T) {
+	debts := []Debt{{AccountID: 1, Balance: 1000, Minimum: 40}, {AccountID: 2, Balance: 20, Minimum: 30}}
+	if got := MinimumPayments(debts); got != 60 {
+		t.Errorf("MinimumPayments() = %v, want 60", got)
+	}
+	if _, err := Simulate(Avalanche, debts, AvalancheOrder(debts), 59.99, start); !errors.Is(err, ErrBudgetTooLow) {
+		t.Errorf("Simulate() error = %v, want %v", err, ErrBudgetTooLow)
+	}
+}
@@ -1,6 +1,9 @@
 package errors
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 func InvalidDataError() error {
 	return errors.New("invalid data")
@@ -13,3 +16,247 @@ func UserNotFoundError() error {
 func InvalidCredentialsError() error {
 	return errors.New("invalid credentials")
 }
+
+func TransactionNotFoundError() error {
+	return errors.New("transaction not found")
+}
+
+func SplitAmountMismatchError(expected float64, actual float64) error {
+	return fmt.Errorf("split amounts add up to %.2f but the transaction amount is %.2f", actual, expected)
+}
+
+func InvalidSplitAmountError() error {
+	return errors.New("split amount cannot be zero")
+}
+
+func RuleNotFoundError() error {
+	return errors.New("rule not found")
+}
+
+func PayeeNotFoundError() error {
+	return errors.New("payee not found")
+}
+
+func AccountNotFoundError() error {
+	return errors.New("account not found")
+}
+
+func RecurringTransactionNotFoundError() error {
+	return errors.New("recurring transaction not found")
+}
+
+func TransactionNotPendingError() error {
+	return errors.New("transaction is not pending")
+}
+
+func SubscriptionNotFoundError() error {
+	return errors.New("subscription not found")
+}
+
+func SubscriptionAlreadyConfirmedError() error {
+	return errors.New("subscription is already a recurring transaction")
+}
+
+func SubscriptionStoppedError() error {
+	return errors.New("subscription has stopped")
+}
+
+func AttachmentNotFoundError() error {
+	return errors.New("attachment not found")
+}
+
+func AttachmentTooLargeError(limit int64) error {
+	return fmt.Errorf("attachment is larger than the %d byte limit", limit)
+}
+
+func UnsupportedAttachmentTypeError(contentType string) error {
+	return fmt.Errorf("attachments of type %s are not supported", contentType)
+}
+
+func EmptySearchQueryError() error {
+	return errors.New("search query cannot be empty")
+}
+
+func InvalidTransactionStatusError(status string) error {
+	return fmt.Errorf("invalid transaction status %q, use one of pending, cleared, reconciled or void", status)
+}
+
+func InvalidStatusTransitionError(from string, to string) error {
+	return fmt.Errorf("a %s transaction cannot be marked %s", from, to)
+}
+
+func UnknownAuditEntityError(entity string) error {
+	return fmt.Errorf("unknown entity %q, use one of account, transaction, budget, category or tag", entity)
+}
+
+func AuditRecordNotFoundError() error {
+	return errors.New("record not found")
+}
+
+func CategoryNotFoundError() error {
+	return errors.New("category not found")
+}
+
+func CategoryCycleError() error {
+	return errors.New("a category cannot be moved under itself or one of its subcategories")
+}
+
+func CategoryContextMismatchError() error {
+	return errors.New("categories of different contexts cannot be combined")
+}
+
+func InvalidCategoryLevelError() error {
+	return errors.New("level must be a whole number of at least 0")
+}
+
+func SystemCategoryError() error {
+	return errors.New("system categories are shared, personalise them with an override instead")
+}
+
+func EmptyNameError() error {
+	return errors.New("name cannot be empty")
+}
+
+func NameTakenError(name string) error {
+	return fmt.Errorf("%q is already taken, merge into it instead", name)
+}
+
+func ReassignmentRequiredError() error {
+	return errors.New("reassign_to must name another record to move everything to")
+}
+
+func TagNotFoundError() error {
+	return errors.New("tag not found")
+}
+
+func BudgetNotFoundError() error {
+	return errors.New("budget not found")
+}
+
+func InvalidTagIdError(value string) error {
+	return fmt.Errorf("invalid tag id %q", value)
+}
+
+func BudgetCategoryNotFoundError() error {
+	return errors.New("budget category not found")
+}
+
+func BudgetCategoryRequiredError() error {
+	return errors.New("a budget needs a category_id or a list of categories")
+}
+
+func InvalidBudgetAmountError() error {
+	return errors.New("amount must be greater than 0")
+}
+
+func DuplicateBudgetCategoryError() error {
+	return errors.New("a category can only be in a budget once")
+}
+
+func BudgetEndDateRequiredError() error {
+	return errors.New("end_date is required for budgets that do not recur")
+}
+
+func InvalidMonthError(value string) error {
+	return fmt.Errorf("invalid month %q, use the form 2006-01", value)
+}
+
+func EnvelopeNotFoundError() error {
+	return errors.New("envelope not found")
+}
+
+func DuplicateEnvelopeError() error {
+	return errors.New("the category already has an envelope")
+}
+
+func SameEnvelopeError() error {
+	return errors.New("money can only be moved between two different envelopes")
+}
+
+func BudgetAlertNotFoundError() error {
+	return errors.New("budget alert not found")
+}
+
+func WebhookURLRequiredError() error {
+	return errors.New("webhook_url is required to deliver alerts by webhook")
+}
+
+func AlertCategoryNotInBudgetError() error {
+	return errors.New("category_id must be one of the budget's categories")
+}
+
+func NotificationNotFoundError() error {
+	return errors.New("notification not found")
+}
+
+func InvalidDateRangeError() error {
+	return errors.New("to cannot be before from")
+}
+
+func InvalidReportPeriodError(period string) error {
+	return fmt.Errorf("invalid period %q, use one of weekly, monthly, quarterly or yearly", period)
+}
+
+func ReportTooLongError(maxPeriods int) error {
+	return fmt.Errorf("a report covers at most %d periods, shorten the range or use a longer period", maxPeriods)
+}
+
+func InvalidReportFormatError(format string) error {
+	return fmt.Errorf("invalid format %q, use one of json, csv or pdf", format)
+}
+
+func BudgetTemplateNotFoundError() error {
+	return errors.New("budget template not found")
+}
+
+func BudgetTemplateLineAmountError() error {
+	return errors.New("each category of a template needs either an amount or a percentage, not both")
+}
+
+func BudgetTemplatePercentageError() error {
+	return errors.New("the percentages of a template cannot add up to more than 100")
+}
+
+func IncomeRequiredError() error {
+	return errors.New("income is required to work out limits that are a percentage of it")
+}
+
+func UnknownPresetGroupError(group string) error {
+	return fmt.Errorf("unknown group %q", group)
+}
+
+func EmptyBudgetDraftError() error {
+	return errors.New("there are no categories to budget for")
+}
+
+func InvalidSuggestionMonthsError() error {
+	return errors.New("months must be between 1 and 24")
+}
+
+func InvalidSuggestionMethodError(method string) error {
+	return fmt.Errorf("invalid method %q, use average or median", method)
+}
+
+func GoalNotFoundError() error {
+	return errors.New("goal not found")
+}
+
+func InvalidGoalDatesError() error {
+	return errors.New("target_date must be after start_date")
+}
+
+func NoDebtsError() error {
+	return errors.New("there are no credit card or loan balances to pay off")
+}
+
+func DebtBudgetTooLowError(minimum float64) error {
+	return fmt.Errorf("monthly_budget must cover the minimum payments of %.2f", minimum)
+}
+
+func NotDebtAccountError(accountId int) error {
+	return fmt.Errorf("account %d is not a credit card or loan account", accountId)
+}
+
+func DebtNotInPlanError(accountId int) error {
+	return fmt.Errorf("account %d is not one of the debts being planned", accountId)
+}
@@ -0,0 +1,36 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/budgets"
+	"gorm.io/gorm"
+)
+
+// AdvanceBudgetPeriods closes the periods of recurring budgets that have
+// ended and opens the next ones, catching up on periods missed while the
+// server was down.
+func AdvanceBudgetPeriods(ctx context.Context, db *gorm.DB) error {
+	now := time.Now()
+	db = db.WithContext(ctx)
+	var due []models.Budget
+	if err := db.Where("recurring = ? AND end_date < ?", true, now).Find(&due).Error; err != nil {
+		return err
+	}
+	tracker := budgets.NewTracker(db)
+	for i := range due {
+		if err := budgets.Advance(&due[i], now, tracker, db); err != nil {
+			log.Printf("advancing budget %d: %v", due[i].ID, err)
+		}
+	}
+	return nil
+}
+
+// CheckBudgetAlerts checks the alerts of every running budget, so that alerts
+// also fire without new transactions, such as projected overspends.
+func CheckBudgetAlerts(ctx context.Context, alerter *budgets.Alerter) error {
+	return alerter.CheckAll(time.Now())
+}
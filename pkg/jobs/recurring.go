@@ -0,0 +1,102 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/database/scopes"
+	"github.com/christo-andrew/haven/pkg/ledger"
+	"github.com/christo-andrew/haven/pkg/recurrence"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxCatchUp limits how many missed occurrences of one template are posted in
+// a single run.
+const maxCatchUp = 366
+
+// Posted is told about each transaction a job posts once it is saved, along
+// with the pending authorisation it replaced if there was one.
+type Posted func(userId uint, transaction models.Transaction, replaced *models.Transaction)
+
+// errOccurrencePosted stops posting an occurrence that another run of the job
+// has posted meanwhile.
+var errOccurrencePosted = errors.New("occurrence has been posted already")
+
+// PostDueRecurringTransactions posts every occurrence of a recurring
+// transaction that is due, catching up on occurrences missed while the server
+// was down.
+func PostDueRecurringTransactions(ctx context.Context, db *gorm.DB, posted Posted) error {
+	now := time.Now()
+	db = db.WithContext(ctx)
+	var due []models.RecurringTransaction
+	err := scopes.GetDueRecurringTransactions(now, db).Preload("Category").Preload("TransactionType").Find(&due).Error
+	if err != nil {
+		return err
+	}
+	posters := make(map[uint]*ledger.Poster)
+	for i := range due {
+		poster, ok := posters[due[i].UserID]
+		if !ok {
+			poster = ledger.NewPoster(due[i].UserID, db)
+			posters[due[i].UserID] = poster
+		}
+		if err := PostRecurringTransaction(&due[i], now, poster, posted, db); err != nil {
+			log.Printf("posting recurring transaction %d: %v", due[i].ID, err)
+		}
+	}
+	return nil
+}
+
+// PostRecurringTransaction posts the occurrences of a template up to now and
+// moves its next date forward. Occurrences are posted like any other new
+// transaction, each in its own database transaction together with the new
+// next date. The template is locked meanwhile, so an occurrence is never
+// posted twice, not even by jobs running on several servers at once.
+func PostRecurringTransaction(recurring *models.RecurringTransaction, now time.Time, poster *ledger.Poster, posted Posted, db *gorm.DB) error {
+	rule, err := recurrence.Parse(recurring.Recurrence)
+	if err != nil {
+		return err
+	}
+	for count := 0; recurring.NextDate != nil && !recurring.NextDate.After(now) && count < maxCatchUp; count++ {
+		date := *recurring.NextDate
+		var nextDate *time.Time
+		if next, ok := rule.Next(recurring.StartDate, date); ok {
+			nextDate = &next
+		}
+		transaction := recurring.Transaction(date)
+		var replaced *models.Transaction
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var current models.RecurringTransaction
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, recurring.ID).Error; err != nil {
+				return err
+			}
+			if current.NextDate == nil || !current.NextDate.Equal(date) {
+				return errOccurrencePosted
+			}
+			var err error
+			if replaced, err = poster.Post(transaction, tx); err != nil {
+				return err
+			}
+			return tx.Model(&models.RecurringTransaction{}).Where("id = ?", recurring.ID).Updates(map[string]interface{}{
+				"next_date": nextDate,
+				"active":    nextDate != nil,
+			}).Error
+		})
+		if errors.Is(err, errOccurrencePosted) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		recurring.NextDate = nextDate
+		recurring.Active = nextDate != nil
+		if posted != nil {
+			posted(recurring.UserID, *transaction, replaced)
+		}
+	}
+	return nil
+}
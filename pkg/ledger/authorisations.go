@@ -0,0 +1,116 @@
+package ledger
+
+import (
+	"math"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/payees"
+	"gorm.io/gorm"
+)
+
+// AuthorisationWindow is how long a card authorisation can stay pending
+// before the transaction that settles it is posted.
+const AuthorisationWindow = 10 * 24 * time.Hour
+
+// AuthorisationTolerance is how much the posted amount may differ from the
+// authorised one, as a fraction of the larger of the two. Tips, fuel
+// pre-authorisations and exchange rates all move the final amount.
+const AuthorisationTolerance = 0.3
+
+// Post saves a new transaction. When a cleared transaction settles a pending
+// authorisation on the same account, it takes the authorisation's place: it
+// inherits the notes, category, tags, attachments and recurring template the
+// authorisation had and the authorisation is deleted. The replaced
+// authorisation is returned, or nil if there was none.
+func Post(transaction *models.Transaction, db *gorm.DB) (*models.Transaction, error) {
+	authorisation := FindAuthorisation(transaction, db)
+	if authorisation == nil {
+		return nil, db.Create(transaction).Error
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		return replace(authorisation, transaction, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return authorisation, nil
+}
+
+// FindAuthorisation returns the pending transaction a posted transaction
+// settles: same account, direction and payee, authorised within the window
+// before it and for a similar amount. The closest amount wins, then the
+// closest date.
+func FindAuthorisation(posted *models.Transaction, db *gorm.DB) *models.Transaction {
+	if posted.IsPending() || posted.IsVoid() || posted.AccountID == 0 {
+		return nil
+	}
+	var candidates []models.Transaction
+	db.Preload("TransactionType").Preload("Category").Preload("Tags").
+		Where("account_id = ? AND transaction_status = ?", posted.AccountID, models.TransactionStatusPending).
+		Where("date BETWEEN ? AND ?", posted.Date.Add(-AuthorisationWindow), posted.Date.Add(24*time.Hour)).
+		Find(&candidates)
+
+	var best *models.Transaction
+	var bestDifference float64
+	var bestDistance time.Duration
+	for i := range candidates {
+		candidate := &candidates[i]
+		if candidate.Direction() != posted.Direction() || !samePayee(candidate, posted) {
+			continue
+		}
+		authorised, settled := math.Abs(candidate.Amount), math.Abs(posted.Amount)
+		difference := math.Abs(authorised - settled)
+		if difference > AuthorisationTolerance*math.Max(authorised, settled) {
+			continue
+		}
+		distance := posted.Date.Sub(candidate.Date).Abs()
+		if best == nil || difference < bestDifference || (difference == bestDifference && distance < bestDistance) {
+			best, bestDifference, bestDistance = candidate, difference, distance
+		}
+	}
+	return best
+}
+
+func samePayee(authorisation, posted *models.Transaction) bool {
+	if authorisation.PayeeID != nil && posted.PayeeID != nil {
+		return *authorisation.PayeeID == *posted.PayeeID
+	}
+	name := payees.Normalize(payeeOrDescription(posted))
+	return name != "" && name == payees.Normalize(payeeOrDescription(authorisation))
+}
+
+func payeeOrDescription(transaction *models.Transaction) string {
+	if transaction.Payee != "" {
+		return transaction.Payee
+	}
+	return transaction.Description
+}
+
+func replace(authorisation, posted *models.Transaction, tx *gorm.DB) error {
+	if posted.Notes == "" {
+		posted.Notes = authorisation.Notes
+	}
+	if posted.RecurringID == nil {
+		posted.RecurringID = authorisation.RecurringID
+	}
+	if posted.Category.Name == models.DefaultCategoryName && authorisation.Category.Name != models.DefaultCategoryName {
+		posted.CategoryID = authorisation.CategoryID
+		posted.Category = authorisation.Category
+	}
+	if err := tx.Create(posted).Error; err != nil {
+		return err
+	}
+	if len(authorisation.Tags) > 0 {
+		if err := tx.Model(posted).Association("Tags").Append(authorisation.Tags); err != nil {
+			return err
+		}
+	}
+	err := tx.Model(&models.Attachment{}).
+		Where("transaction_id = ?", authorisation.ID).
+		Update("transaction_id", posted.ID).Error
+	if err != nil {
+		return err
+	}
+	return tx.Delete(&models.Transaction{}, authorisation.ID).Error
+}
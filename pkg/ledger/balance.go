@@ -0,0 +1,46 @@
+package ledger
+
+import "github.com/christo-andrew/haven/internal/models"
+
+// StatusTotal is the net amount of an account's transactions in one status.
+// Debits is the money that went out, as a positive number.
+type StatusTotal struct {
+	Status string
+	Amount float64
+	Debits float64
+}
+
+// Balance breaks the balance of an account down by how settled the money is.
+// The opening balance is the balance the account was created with.
+type Balance struct {
+	Opening    float64
+	Reconciled float64
+	Cleared    float64
+	Pending    float64
+	Available  float64
+	Current    float64
+}
+
+// NewBalance adds the totals of each status to the opening balance. The
+// cleared balance includes reconciled transactions. Pending debits are held
+// against the available balance straight away, while pending credits only
+// count once they clear. Voided transactions are ignored.
+func NewBalance(opening float64, totals []StatusTotal) Balance {
+	balance := Balance{Opening: opening, Reconciled: opening, Cleared: opening}
+	var pendingDebits float64
+	for _, total := range totals {
+		switch total.Status {
+		case models.TransactionStatusReconciled:
+			balance.Reconciled += total.Amount
+			balance.Cleared += total.Amount
+		case models.TransactionStatusCleared:
+			balance.Cleared += total.Amount
+		case models.TransactionStatusPending:
+			balance.Pending += total.Amount
+			pendingDebits += total.Debits
+		}
+	}
+	balance.Available = balance.Cleared - pendingDebits
+	balance.Current = balance.Cleared + balance.Pending
+	return balance
+}
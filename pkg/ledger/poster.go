@@ -0,0 +1,30 @@
+package ledger
+
+import (
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/payees"
+	"github.com/christo-andrew/haven/pkg/rules"
+	"gorm.io/gorm"
+)
+
+// Poster posts the new transactions of a user the way every create path
+// does, whether they are entered, imported or posted by a recurring
+// transaction: the payee is resolved, the user's rules are applied and the
+// transaction is posted to the ledger.
+type Poster struct {
+	resolver *payees.Resolver
+	engine   *rules.Engine
+}
+
+func NewPoster(userId uint, db *gorm.DB) *Poster {
+	return &Poster{resolver: payees.NewResolver(userId, db), engine: rules.NewEngine(userId, db)}
+}
+
+// Post posts a transaction in the given database transaction, which also
+// saves a payee created for it. Like Post, it returns the pending
+// authorisation the transaction replaced, if any.
+func (poster *Poster) Post(transaction *models.Transaction, tx *gorm.DB) (*models.Transaction, error) {
+	poster.resolver.Resolve(transaction, tx)
+	poster.engine.Apply(transaction)
+	return Post(transaction, tx)
+}
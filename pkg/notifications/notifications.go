@@ -0,0 +1,154 @@
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/models"
+	"gorm.io/gorm"
+)
+
+const (
+	ChannelInApp   = "in_app"
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+)
+
+// SMTPConfig is the mail server emails are sent through. Username may be
+// left empty for servers that do not authenticate, such as a local sink.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// Message is a notification for a user. Data is passed on to webhooks as is.
+type Message struct {
+	UserID   uint
+	Kind     string
+	Title    string
+	Body     string
+	BudgetID *uint
+	Data     map[string]interface{}
+}
+
+// Notifier delivers messages to the in-app notification feed, by email and
+// to webhooks.
+type Notifier struct {
+	db     *gorm.DB
+	smtp   SMTPConfig
+	client *http.Client
+}
+
+func New(db *gorm.DB, smtp SMTPConfig, webhookTimeout time.Duration) *Notifier {
+	return &Notifier{db: db, smtp: smtp, client: newWebhookClient(webhookTimeout)}
+}
+
+// Send delivers a message through each of the channels. A channel failing
+// does not stop the others; the channels that failed are returned with their
+// errors joined together.
+func (notifier *Notifier) Send(message Message, channels []string, webhookURL string) ([]string, error) {
+	var failed []string
+	var errs []error
+	for _, channel := range channels {
+		var err error
+		switch channel {
+		case ChannelInApp:
+			err = notifier.notify(message)
+		case ChannelEmail:
+			err = notifier.email(message)
+		case ChannelWebhook:
+			err = notifier.post(message, webhookURL)
+		default:
+			err = fmt.Errorf("unknown notification channel %q", channel)
+		}
+		if err != nil {
+			failed = append(failed, channel)
+			errs = append(errs, fmt.Errorf("%s: %w", channel, err))
+		}
+	}
+	return failed, errors.Join(errs...)
+}
+
+func (notifier *Notifier) notify(message Message) error {
+	return notifier.db.Create(&models.Notification{
+		UserID:   message.UserID,
+		Kind:     message.Kind,
+		Title:    message.Title,
+		Body:     message.Body,
+		BudgetID: message.BudgetID,
+	}).Error
+}
+
+func (notifier *Notifier) email(message Message) error {
+	if notifier.smtp.Host == "" {
+		return errors.New("no SMTP server is configured")
+	}
+	var user models.User
+	notifier.db.First(&user, message.UserID)
+	if user.Email == "" {
+		return errors.New("the user has no email address")
+	}
+	var auth smtp.Auth
+	if notifier.smtp.Username != "" {
+		auth = smtp.PlainAuth("", notifier.smtp.Username, notifier.smtp.Password, notifier.smtp.Host)
+	}
+	body := strings.Join([]string{
+		"From: " + headerValue(notifier.smtp.From),
+		"To: " + headerValue(user.Email),
+		"Subject: " + mime.QEncoding.Encode("utf-8", headerValue(message.Title)),
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		message.Body,
+	}, "\r\n")
+	address := fmt.Sprintf("%s:%d", notifier.smtp.Host, notifier.smtp.Port)
+	return smtp.SendMail(address, auth, notifier.smtp.From, []string{user.Email}, []byte(body))
+}
+
+// headerValue keeps a value on one line, so that names chosen by users, such
+// as budget and category names, cannot add headers to an email.
+func headerValue(value string) string {
+	return strings.Join(strings.FieldsFunc(value, func(r rune) bool {
+		return r == '\r' || r == '\n'
+	}), " ")
+}
+
+func (notifier *Notifier) post(message Message, webhookURL string) error {
+	if webhookURL == "" {
+		return errors.New("no webhook URL is set")
+	}
+	if target, err := url.Parse(webhookURL); err != nil || target.Scheme != "https" {
+		return ErrInsecureWebhook
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"kind":      message.Kind,
+		"title":     message.Title,
+		"body":      message.Body,
+		"data":      message.Data,
+		"sent_at":   time.Now().UTC(),
+		"user_id":   message.UserID,
+		"budget_id": message.BudgetID,
+	})
+	if err != nil {
+		return err
+	}
+	response, err := notifier.client.Post(webhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("webhook answered %s", response.Status)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrInsecureWebhook  = errors.New("webhook_url must be an https URL")
+	ErrForbiddenWebhook = errors.New("webhook_url must not point at a loopback, private or link-local address")
+)
+
+// reservedNetworks are ranges that are not reachable on the internet and that
+// the net.IP predicates do not cover.
+var reservedNetworks = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),
+	mustParseCIDR("100.64.0.0/10"),
+}
+
+func mustParseCIDR(value string) *net.IPNet {
+	_, network, err := net.ParseCIDR(value)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
+
+// publicAddress reports whether webhooks may be delivered to an address:
+// loopback, private, link-local, cloud metadata and other internal addresses
+// are refused so that users cannot reach the server's own network.
+func publicAddress(ip net.IP) bool {
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, network := range reservedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckWebhookURL makes sure a webhook URL is https and that its host
+// resolves to public addresses only. The addresses are checked again when
+// connecting, as the host may resolve differently by then.
+func CheckWebhookURL(ctx context.Context, value string) error {
+	target, err := url.Parse(value)
+	if err != nil || target.Scheme != "https" || target.Hostname() == "" {
+		return ErrInsecureWebhook
+	}
+	if ip := net.ParseIP(target.Hostname()); ip != nil {
+		if !publicAddress(ip) {
+			return ErrForbiddenWebhook
+		}
+		return nil
+	}
+	addresses, err := net.DefaultResolver.LookupIPAddr(ctx, target.Hostname())
+	if err != nil {
+		return fmt.Errorf("webhook_url host cannot be resolved: %w", err)
+	}
+	for _, address := range addresses {
+		if !publicAddress(address.IP) {
+			return ErrForbiddenWebhook
+		}
+	}
+	return nil
+}
+
+// newWebhookClient returns the client webhooks are posted with. The address
+// is checked in the dialer, after the host name has been resolved, so that a
+// host resolving to a public address when the webhook was saved cannot be
+// pointed at an internal one later. Proxies and redirects are not followed.
+func newWebhookClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(_ string, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if !publicAddress(net.ParseIP(host)) {
+				return ErrForbiddenWebhook
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,122 @@
+package payees
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Normalizer strips the noise card terminals and banks add around a merchant
+// name, so that "POS 1234 JAVA HOUSE NAIROBI KE" and "JAVA HOUSE" are the same
+// payee.
+type Normalizer struct {
+	// NoisePrefixes are words dropped from the start of a description.
+	NoisePrefixes map[string]bool
+	// Countries are country codes dropped from the end of a description.
+	Countries map[string]bool
+	// Locations are towns and cities dropped from the end of a description.
+	Locations map[string]bool
+}
+
+var DefaultNormalizer = &Normalizer{
+	NoisePrefixes: set(
+		"POS", "PURCHASE", "PURCH", "CARD", "DEBIT", "VISA", "MASTERCARD", "MC", "CONTACTLESS",
+		"ECOM", "ONLINE", "PAYMENT", "PMT", "TRF", "TRANSFER", "TO", "AT", "SQ", "PAYPAL", "ATM",
+	),
+	Countries: set(
+		"KE", "KEN", "UG", "UGA", "TZ", "TZA", "RW", "RWA", "ET", "ETH", "ZA", "ZAF", "NG", "NGA",
+		"GB", "GBR", "UK", "US", "USA", "IE", "IRL", "NL", "NLD", "DE", "DEU", "FR", "FRA", "AE", "ARE",
+	),
+	Locations: set(
+		"NAIROBI", "MOMBASA", "KISUMU", "NAKURU", "ELDORET", "THIKA", "KAMPALA", "ENTEBBE",
+		"KIGALI", "DAR", "ARUSHA", "ADDIS", "LAGOS", "JOHANNESBURG", "LONDON", "DUBLIN", "AMSTERDAM",
+	),
+}
+
+// Normalize returns the merchant part of a description in upper case.
+func Normalize(description string) string {
+	return DefaultNormalizer.Normalize(description)
+}
+
+func (normalizer *Normalizer) Normalize(description string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		switch r {
+		case '*', '#', '/', '\\', '_', '|', ',', ';', ':':
+			return ' '
+		}
+		return unicode.ToUpper(r)
+	}, description)
+
+	var tokens []string
+	for _, token := range strings.Fields(cleaned) {
+		if isNoiseToken(token) {
+			continue
+		}
+		tokens = append(tokens, token)
+	}
+	for len(tokens) > 1 && normalizer.NoisePrefixes[tokens[0]] {
+		tokens = tokens[1:]
+	}
+	if len(tokens) > 1 && normalizer.Countries[tokens[len(tokens)-1]] {
+		tokens = tokens[:len(tokens)-1]
+	}
+	for len(tokens) > 1 && normalizer.Locations[tokens[len(tokens)-1]] {
+		tokens = tokens[:len(tokens)-1]
+	}
+	if len(tokens) == 0 {
+		return strings.ToUpper(strings.TrimSpace(description))
+	}
+	return strings.Join(tokens, " ")
+}
+
+// DisplayName turns a normalised name into the form shown to users,
+// "JAVA HOUSE" becomes "Java House".
+func DisplayName(name string) string {
+	words := strings.Fields(strings.ToLower(name))
+	for i, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
+
+// isNoiseToken reports whether a token is a terminal id, reference, date or
+// masked card number rather than part of a name. Names can have digits in
+// them, as in "7-ELEVEN", "3M" or "21ST CENTURY", so a token is only noise
+// when it is made of digits, masks and separators, or when it is a long
+// reference that is mostly digits.
+func isNoiseToken(token string) bool {
+	if strings.Trim(token, "X") == "" || strings.Trim(token, "-.") == "" {
+		return true
+	}
+	digits, others := 0, 0
+	masked := true
+	for _, r := range token {
+		switch {
+		case unicode.IsDigit(r):
+			digits++
+		case r == 'X':
+			others++
+		case r == '-' || r == '.':
+		default:
+			others++
+			masked = false
+		}
+	}
+	if digits == 0 {
+		return false
+	}
+	return masked || (len(token) >= minReferenceLength && digits >= others)
+}
+
+// minReferenceLength is the length from which a token with as many digits as
+// letters is taken for a reference, "FT24071" rather than "A1".
+const minReferenceLength = 6
+
+func set(values ...string) map[string]bool {
+	result := make(map[string]bool, len(values))
+	for _, value := range values {
+		result[value] = true
+	}
+	return result
+}
@@ -0,0 +1,54 @@
+package payees
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		description string
+		want        string
+	}{
+		{"POS 1234 JAVA HOUSE NAIROBI KE", "JAVA HOUSE"},
+		{"Java House", "JAVA HOUSE"},
+		{"CARD XXXX1234 NAIVAS 12.03.2024", "NAIVAS"},
+		{"VISA 4111XXXXXXXX1111 CARREFOUR", "CARREFOUR"},
+		{"PAYPAL *SPOTIFY REF FT24071ABC", "SPOTIFY REF"},
+		{"7-ELEVEN 00412 DUBLIN IE", "7-ELEVEN"},
+		{"POS 3M KENYA", "3M KENYA"},
+		{"21ST CENTURY FOX", "21ST CENTURY FOX"},
+		{"PURCHASE 7ELEVEN #4411", "7ELEVEN"},
+		{"1234 5678", "1234 5678"},
+	}
+	for _, test := range tests {
+		if got := Normalize(test.description); got != test.want {
+			t.Errorf("Normalize(%q) = %q, want %q", test.description, got, test.want)
+		}
+	}
+}
+
+func TestIsNoiseToken(t *testing.T) {
+	tests := []struct {
+		token string
+		want  bool
+	}{
+		{"1234", true},
+		{"2024-03-12", true},
+		{"12.03", true},
+		{"XXXX", true},
+		{"XXXX1234", true},
+		{"1234XXXXXX5678", true},
+		{"--", true},
+		{"FT24071ABC", true},
+		{"REF000123", true},
+		{"7-ELEVEN", false},
+		{"3M", false},
+		{"21ST", false},
+		{"7ELEVEN", false},
+		{"A1", false},
+		{"JAVA", false},
+	}
+	for _, test := range tests {
+		if got := isNoiseToken(test.token); got != test.want {
+			t.Errorf("isNoiseToken(%q) = %v, want %v", test.token, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package payees
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/database/scopes"
+	"gorm.io/gorm"
+)
+
+type pattern struct {
+	payee   *models.Payee
+	pattern *regexp.Regexp
+}
+
+// Resolver links transactions to the payees of a user, creating payees for
+// merchants it has not seen before.
+type Resolver struct {
+	userID   uint
+	byName   map[string]*models.Payee
+	patterns []pattern
+}
+
+func NewResolver(userID uint, db *gorm.DB) *Resolver {
+	resolver := &Resolver{
+		userID: userID,
+		byName: make(map[string]*models.Payee),
+	}
+	var userPayees []models.Payee
+	scopes.GetUserPayees(userID, db).Find(&userPayees)
+	for i := range userPayees {
+		resolver.add(&userPayees[i])
+	}
+	return resolver
+}
+
+func (resolver *Resolver) add(payee *models.Payee) {
+	resolver.byName[Normalize(payee.Name)] = payee
+	for _, alias := range payee.Aliases {
+		if !alias.IsPattern {
+			resolver.byName[Normalize(alias.Name)] = payee
+			continue
+		}
+		compiled, err := regexp.Compile("(?i)" + alias.Name)
+		if err != nil {
+			continue
+		}
+		resolver.patterns = append(resolver.patterns, pattern{payee: payee, pattern: compiled})
+	}
+}
+
+// Resolve sets the payee of a transaction. The payee text, or the description
+// when there is no payee, is checked against pattern aliases first and then
+// matched by its normalised name. A payee's default category is applied to
+// transactions that are still uncategorised. New payees are created with db,
+// which should be the database transaction the transaction is saved in so
+// that they go away with it when saving fails.
+func (resolver *Resolver) Resolve(transaction *models.Transaction, db *gorm.DB) *models.Payee {
+	raw := strings.TrimSpace(transaction.Payee)
+	if raw == "" {
+		raw = strings.TrimSpace(transaction.Description)
+	}
+	if raw == "" {
+		return nil
+	}
+
+	payee := resolver.match(raw)
+	if payee == nil {
+		payee = resolver.create(Normalize(raw), db)
+		if payee == nil {
+			return nil
+		}
+	}
+
+	transaction.PayeeID = &payee.ID
+	transaction.Payee = payee.Name
+	if payee.DefaultCategory != nil && transaction.Category.Name == models.DefaultCategoryName {
+		transaction.CategoryID = payee.DefaultCategory.ID
+		transaction.Category = *payee.DefaultCategory
+	}
+	return payee
+}
+
+func (resolver *Resolver) match(raw string) *models.Payee {
+	for _, p := range resolver.patterns {
+		if p.pattern.MatchString(raw) {
+			return p.payee
+		}
+	}
+	return resolver.byName[Normalize(raw)]
+}
+
+func (resolver *Resolver) create(name string, db *gorm.DB) *models.Payee {
+	payee := &models.Payee{UserID: resolver.userID, Name: DisplayName(name)}
+	if err := db.Create(payee).Error; err != nil {
+		return nil
+	}
+	resolver.add(payee)
+	return payee
+}
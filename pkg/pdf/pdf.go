@@ -0,0 +1,185 @@
+// Package pdf writes simple PDF documents: pages of text in the standard
+// Helvetica fonts, lines and filled rectangles. It needs no font files, the
+// standard fonts are built into every PDF reader.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 page size in points.
+const (
+	A4Width  = 595.0
+	A4Height = 842.0
+)
+
+// Color is an RGB color with components between 0 and 1.
+type Color struct {
+	R, G, B float64
+}
+
+var Black = Color{0, 0, 0}
+
+// Document is a PDF being written. Coordinates are in points from the top
+// left corner of the page.
+type Document struct {
+	Width  float64
+	Height float64
+	pages  []*bytes.Buffer
+}
+
+func New() *Document {
+	return &Document{Width: A4Width, Height: A4Height}
+}
+
+// AddPage starts a new page. Drawing goes to the last page added.
+func (document *Document) AddPage() {
+	document.pages = append(document.pages, &bytes.Buffer{})
+}
+
+func (document *Document) page() *bytes.Buffer {
+	if len(document.pages) == 0 {
+		document.AddPage()
+	}
+	return document.pages[len(document.pages)-1]
+}
+
+// Text draws text with its baseline at y. Characters outside of Latin-1 are
+// drawn as question marks.
+func (document *Document) Text(x float64, y float64, size float64, bold bool, color Color, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(document.page(), "BT %s rg /%s %s Tf %s %s Td (%s) Tj ET\n",
+		color.operands(), font, number(size), number(x), number(document.Height-y), escape(text))
+}
+
+// TextRight draws text ending at x.
+func (document *Document) TextRight(x float64, y float64, size float64, bold bool, color Color, text string) {
+	document.Text(x-TextWidth(text, size, bold), y, size, bold, color, text)
+}
+
+// Rect fills a rectangle whose top left corner is at x, y.
+func (document *Document) Rect(x float64, y float64, width float64, height float64, color Color) {
+	fmt.Fprintf(document.page(), "%s rg %s %s %s %s re f\n",
+		color.operands(), number(x), number(document.Height-y-height), number(width), number(height))
+}
+
+// Line draws a straight line.
+func (document *Document) Line(x1 float64, y1 float64, x2 float64, y2 float64, width float64, color Color) {
+	fmt.Fprintf(document.page(), "%s RG %s w %s %s m %s %s l S\n",
+		color.operands(), number(width), number(x1), number(document.Height-y1), number(x2), number(document.Height-y2))
+}
+
+// WriteTo writes the document out.
+func (document *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(document.pages) == 0 {
+		document.AddPage()
+	}
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	// Objects 1 to 4 are the catalog, the page tree and the two fonts; each
+	// page is then followed by its content stream.
+	var kids []string
+	for i := range document.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+2*i))
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, content := range document.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			number(document.Width), number(document.Height), 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.WriteTo(w)
+}
+
+// helveticaWidths are the widths of the printable ASCII characters in
+// Helvetica, in thousandths of the font size.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// TextWidth measures text in points. Bold text is measured as slightly wider
+// than regular, which is close enough for laying out tables.
+func TextWidth(text string, size float64, bold bool) float64 {
+	total := 0
+	for _, r := range text {
+		if r >= 32 && r <= 126 {
+			total += helveticaWidths[r-32]
+		} else {
+			total += 556
+		}
+	}
+	width := float64(total) * size / 1000
+	if bold {
+		width *= 1.06
+	}
+	return width
+}
+
+// Truncate shortens text to fit in width, ending it with an ellipsis.
+func Truncate(text string, width float64, size float64, bold bool) string {
+	if TextWidth(text, size, bold) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && TextWidth(string(runes)+"...", size, bold) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+func (color Color) operands() string {
+	return number(color.R) + " " + number(color.G) + " " + number(color.B)
+}
+
+func number(value float64) string {
+	formatted := strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.3f", value), "0"), ".")
+	if formatted == "" || formatted == "-0" {
+		return "0"
+	}
+	return formatted
+}
+
+func escape(text string) string {
+	var escaped strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			escaped.WriteByte('\\')
+			escaped.WriteRune(r)
+		case r >= 32 && r <= 126:
+			escaped.WriteRune(r)
+		case r >= 160 && r <= 255:
+			fmt.Fprintf(&escaped, "\\%03o", r)
+		default:
+			escaped.WriteByte('?')
+		}
+	}
+	return escaped.String()
+}
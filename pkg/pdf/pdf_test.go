@@ -0,0 +1,149 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Groceries", "Groceries"},
+		{"Rent (March)", `Rent \(March\)`},
+		{`C:\budgets`, `C:\\budgets`},
+		{"Café", `Caf\351`},
+		{"£12.50", `\24312.50`},
+		{"\u00a0", `\240`},
+		{"ÿ", `\377`},
+		{"€5", "?5"},
+		{"日本", "??"},
+		{"tab\there", "tab?here"},
+	}
+	for _, test := range tests {
+		if got := escape(test.text); got != test.want {
+			t.Errorf("escape(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
+
+func TestNumber(t *testing.T) {
+	tests := []struct {
+		value float64
+		want  string
+	}{
+		{0, "0"},
+		{12, "12"},
+		{12.5, "12.5"},
+		{0.1234, "0.123"},
+		{-0.0001, "0"},
+		{-3.25, "-3.25"},
+		{595, "595"},
+	}
+	for _, test := range tests {
+		if got := number(test.value); got != test.want {
+			t.Errorf("number(%v) = %q, want %q", test.value, got, test.want)
+		}
+	}
+}
+
+func TestWriteToOffsets(t *testing.T) {
+	tests := []struct {
+		name  string
+		pages int
+	}{
+		{"empty", 0},
+		{"one page", 1},
+		{"three pages", 3},
+	}
+	for _, test := range tests {
+		document := New()
+		for i := 0; i < test.pages; i++ {
+			document.AddPage()
+			document.Text(40, 60, 12, i%2 == 0, Black, fmt.Sprintf("Page (%d) £ café", i+1))
+			document.Line(40, 70, 200, 70, 1, Black)
+			document.Rect(40, 80, 100, 20, Color{0.5, 0.5, 0.5})
+		}
+		var out bytes.Buffer
+		if _, err := document.WriteTo(&out); err != nil {
+			t.Fatalf("%s: WriteTo() error = %v", test.name, err)
+		}
+		checkOffsets(t, test.name, out.Bytes(), 4+2*max(test.pages, 1))
+	}
+}
+
+// checkOffsets checks that startxref points at the xref table and that every
+// entry of the table points at the object it numbers.
+func checkOffsets(t *testing.T, name string, written []byte, objects int) {
+	t.Helper()
+	match := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(written)
+	if match == nil {
+		t.Errorf("%s: no startxref at the end of the document", name)
+		return
+	}
+	xref, _ := strconv.Atoi(string(match[1]))
+	if !bytes.HasPrefix(written[xref:], []byte("xref\n")) {
+		t.Errorf("%s: startxref %d does not point at the xref table", name, xref)
+		return
+	}
+	lines := strings.Split(string(written[xref:]), "\n")
+	if want := fmt.Sprintf("0 %d", objects+1); lines[1] != want {
+		t.Errorf("%s: xref subsection = %q, want %q", name, lines[1], want)
+	}
+	for i := 1; i <= objects; i++ {
+		entry := lines[2+i]
+		if len(entry) != 19 || !strings.HasSuffix(entry, " 00000 n ") {
+			t.Errorf("%s: xref entry %d = %q", name, i, entry)
+			continue
+		}
+		offset, _ := strconv.Atoi(entry[:10])
+		if want := fmt.Sprintf("%d 0 obj\n", i); !bytes.HasPrefix(written[offset:], []byte(want)) {
+			t.Errorf("%s: xref entry %d points at %q, want %q", name, i, written[offset:offset+10], want)
+		}
+	}
+	if want := fmt.Sprintf("/Size %d", objects+1); !bytes.Contains(written, []byte(want)) {
+		t.Errorf("%s: trailer has no %q", name, want)
+	}
+}
+
+func TestWriteToStreamLength(t *testing.T) {
+	document := New()
+	document.Text(40, 60, 12, false, Black, "Total (£)")
+	var out bytes.Buffer
+	if _, err := document.WriteTo(&out); err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	match := regexp.MustCompile(`(?s)<< /Length (\d+) >>\nstream\n(.*?)endstream`).FindSubmatch(out.Bytes())
+	if match == nil {
+		t.Fatal("no content stream written")
+	}
+	if length, _ := strconv.Atoi(string(match[1])); length != len(match[2]) {
+		t.Errorf("stream /Length = %d, want %d", length, len(match[2]))
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		text  string
+		width float64
+		want  string
+	}{
+		{"Rent", 100, "Rent"},
+		{"Groceries and household", 54, "Groceries..."},
+		{"Groceries", 5, "..."},
+	}
+	for _, test := range tests {
+		got := Truncate(test.text, test.width, 10, false)
+		if got != test.want {
+			t.Errorf("Truncate(%q, %v) = %q, want %q", test.text, test.width, got, test.want)
+		}
+		if got != test.text && TextWidth(got, 10, false) > test.width && test.width >= TextWidth("...", 10, false) {
+			t.Errorf("Truncate(%q, %v) = %q, wider than the width", test.text, test.width, got)
+		}
+	}
+}
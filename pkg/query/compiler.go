@@ -0,0 +1,338 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/models"
+	"gorm.io/gorm"
+)
+
+// Compiled is a query turned into SQL over the transactions table. Values are
+// always bound as arguments and only whitelisted columns are referenced, so
+// user input never ends up in the SQL text.
+type Compiled struct {
+	Where string
+	Args  []interface{}
+	Order []string
+}
+
+// Scope applies the compiled query to a transactions query.
+func (compiled *Compiled) Scope(db *gorm.DB) *gorm.DB {
+	if compiled.Where != "" {
+		db = db.Where(compiled.Where, compiled.Args...)
+	}
+	for _, order := range compiled.Order {
+		db = db.Order(order)
+	}
+	return db
+}
+
+// FieldError describes a filter on an unknown field or with an invalid value.
+type FieldError struct {
+	Pos     int
+	Message string
+}
+
+func (err *FieldError) Error() string {
+	return fmt.Sprintf("invalid filter at position %d: %s", err.Pos+1, err.Message)
+}
+
+var sortColumns = map[string]string{
+	"date":        "transactions.date",
+	"amount":      "ABS(transactions.amount)",
+	"payee":       "transactions.payee",
+	"description": "transactions.description",
+	"created":     "transactions.created_at",
+}
+
+// Fields lists the fields that can be filtered on.
+var Fields = []string{"amount", "date", "category", "type", "tag", "payee", "account", "status", "description", "reference", "is", "has"}
+
+// Compile parses and compiles a query in one step.
+func Compile(input string) (*Compiled, error) {
+	query, err := Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	return query.Compile()
+}
+
+// Compile turns the query into SQL. Results are newest first unless the query
+// sorts them.
+func (query *Query) Compile() (*Compiled, error) {
+	compiled := &Compiled{}
+	if query.Root != nil {
+		where, args, err := compileNode(query.Root)
+		if err != nil {
+			return nil, err
+		}
+		compiled.Where = where
+		compiled.Args = args
+	}
+	for _, sort := range query.Sort {
+		column, ok := sortColumns[sort.Field]
+		if !ok {
+			return nil, &FieldError{Message: "cannot sort by " + strconv.Quote(sort.Field)}
+		}
+		direction := " ASC"
+		if sort.Descending {
+			direction = " DESC"
+		}
+		compiled.Order = append(compiled.Order, column+direction)
+	}
+	if len(compiled.Order) == 0 {
+		compiled.Order = []string{"transactions.date DESC"}
+	}
+	return compiled, nil
+}
+
+func compileNode(node Node) (string, []interface{}, error) {
+	switch n := node.(type) {
+	case And:
+		return compileBinary(n.Left, n.Right, "AND")
+	case Or:
+		return compileBinary(n.Left, n.Right, "OR")
+	case Not:
+		where, args, err := compileNode(n.Node)
+		if err != nil {
+			return "", nil, err
+		}
+		return "NOT (" + where + ")", args, nil
+	case Text:
+		pattern := likePattern(n.Value)
+		return "(LOWER(COALESCE(transactions.description, '')) LIKE ? OR LOWER(COALESCE(transactions.payee, '')) LIKE ? OR LOWER(COALESCE(transactions.reference, '')) LIKE ?)",
+			[]interface{}{pattern, pattern, pattern}, nil
+	case Filter:
+		return compileFilter(n)
+	}
+	return "", nil, fmt.Errorf("unknown query node %T", node)
+}
+
+func compileBinary(left, right Node, operator string) (string, []interface{}, error) {
+	leftWhere, leftArgs, err := compileNode(left)
+	if err != nil {
+		return "", nil, err
+	}
+	rightWhere, rightArgs, err := compileNode(right)
+	if err != nil {
+		return "", nil, err
+	}
+	return "(" + leftWhere + " " + operator + " " + rightWhere + ")", append(leftArgs, rightArgs...), nil
+}
+
+func compileFilter(filter Filter) (string, []interface{}, error) {
+	switch filter.Field {
+	case "amount":
+		return compileAmount(filter)
+	case "date":
+		return compileDate(filter)
+	}
+
+	if filter.Operator != ":" && filter.Operator != "=" && filter.Operator != "!=" {
+		return "", nil, &FieldError{Pos: filter.Pos, Message: fmt.Sprintf("%s only supports :, = and !=", filter.Field)}
+	}
+	value := strings.ToLower(filter.Value)
+	var where string
+	var args []interface{}
+	switch filter.Field {
+	case "category":
+		where = `(transactions.category_id IN (SELECT id FROM categories WHERE LOWER(name) = ?)
+			OR EXISTS (SELECT 1 FROM transaction_splits
+				INNER JOIN categories AS split_categories ON split_categories.id = transaction_splits.category_id
+				WHERE transaction_splits.transaction_id = transactions.id AND transaction_splits.deleted_at IS NULL
+				AND LOWER(split_categories.name) = ?))`
+		args = []interface{}{value, value}
+	case "type":
+		where = "transactions.transaction_type_id IN (SELECT id FROM categories WHERE LOWER(name) = ?)"
+		args = []interface{}{value}
+	case "tag":
+		where = `EXISTS (SELECT 1 FROM transaction_tags
+			INNER JOIN tags ON tags.id = transaction_tags.tag_id
+			WHERE transaction_tags.transaction_id = transactions.id AND LOWER(tags.name) = ?)`
+		args = []interface{}{value}
+	case "payee", "description", "reference":
+		column := "LOWER(COALESCE(transactions." + filter.Field + ", ''))"
+		if filter.Operator == "=" {
+			where = column + " = ?"
+			args = []interface{}{value}
+		} else {
+			where = column + " LIKE ?"
+			args = []interface{}{likePattern(value)}
+		}
+	case "account":
+		if accountId, err := strconv.Atoi(value); err == nil {
+			where = "transactions.account_id = ?"
+			args = []interface{}{accountId}
+		} else {
+			where = "transactions.account_id IN (SELECT id FROM accounts WHERE LOWER(account_name) = ?)"
+			args = []interface{}{value}
+		}
+	case "status":
+		where = "LOWER(COALESCE(transactions.transaction_status, '')) = ?"
+		args = []interface{}{value}
+	case "is":
+		switch value {
+		case "transfer":
+			where = "transactions.is_transfer = ?"
+			args = []interface{}{true}
+		case "split":
+			where = "EXISTS (SELECT 1 FROM transaction_splits WHERE transaction_splits.transaction_id = transactions.id AND transaction_splits.deleted_at IS NULL)"
+		case "pending":
+			where = "transactions.transaction_status = ?"
+			args = []interface{}{models.TransactionStatusPending}
+		case "recurring":
+			where = "transactions.recurring_id IS NOT NULL"
+		case "uncategorised", "uncategorized":
+			where = "transactions.category_id IN (SELECT id FROM categories WHERE name = ?)"
+			args = []interface{}{models.DefaultCategoryName}
+		default:
+			return "", nil, &FieldError{Pos: filter.Pos, Message: "is supports transfer, split, pending, recurring and uncategorised"}
+		}
+	case "has":
+		switch value {
+		case "attachment", "attachments":
+			where = "EXISTS (SELECT 1 FROM attachments WHERE attachments.transaction_id = transactions.id AND attachments.deleted_at IS NULL)"
+		case "tag", "tags":
+			where = "EXISTS (SELECT 1 FROM transaction_tags WHERE transaction_tags.transaction_id = transactions.id)"
+		case "payee":
+			where = "transactions.payee_id IS NOT NULL"
+		default:
+			return "", nil, &FieldError{Pos: filter.Pos, Message: "has supports attachment, tag and payee"}
+		}
+	default:
+		return "", nil, &FieldError{Pos: filter.Pos, Message: fmt.Sprintf("unknown field %q, use one of %s", filter.Field, strings.Join(Fields, ", "))}
+	}
+	if filter.Operator == "!=" {
+		where = "NOT (" + where + ")"
+	}
+	return where, args, nil
+}
+
+// compileAmount compares the absolute amount, so amount>50 finds both
+// spending and income over 50.
+func compileAmount(filter Filter) (string, []interface{}, error) {
+	const column = "ABS(transactions.amount)"
+	parse := func(text string) (float64, error) {
+		amount, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return 0, &FieldError{Pos: filter.Pos, Message: fmt.Sprintf("invalid amount %q", text)}
+		}
+		return amount, nil
+	}
+	if filter.Operator == ".." {
+		var clauses []string
+		var args []interface{}
+		if filter.Value != "" {
+			from, err := parse(filter.Value)
+			if err != nil {
+				return "", nil, err
+			}
+			clauses = append(clauses, column+" >= ?")
+			args = append(args, from)
+		}
+		if filter.ValueTo != "" {
+			to, err := parse(filter.ValueTo)
+			if err != nil {
+				return "", nil, err
+			}
+			clauses = append(clauses, column+" <= ?")
+			args = append(args, to)
+		}
+		if len(clauses) == 0 {
+			return "", nil, &FieldError{Pos: filter.Pos, Message: "empty amount range"}
+		}
+		return "(" + strings.Join(clauses, " AND ") + ")", args, nil
+	}
+	amount, err := parse(filter.Value)
+	if err != nil {
+		return "", nil, err
+	}
+	switch filter.Operator {
+	case ":", "=":
+		return "(" + column + " >= ? AND " + column + " < ?)", []interface{}{amount - 0.005, amount + 0.005}, nil
+	case "!=":
+		return "NOT (" + column + " >= ? AND " + column + " < ?)", []interface{}{amount - 0.005, amount + 0.005}, nil
+	default:
+		return column + " " + filter.Operator + " ?", []interface{}{amount}, nil
+	}
+}
+
+// compileDate accepts a day (2024-03-01), a month (2024-03) or a year (2024).
+// Equality matches the whole period and ranges include both ends.
+func compileDate(filter Filter) (string, []interface{}, error) {
+	const column = "transactions.date"
+	parse := func(text string) (time.Time, time.Time, error) {
+		start, end, ok := datePeriod(text)
+		if !ok {
+			return start, end, &FieldError{Pos: filter.Pos, Message: fmt.Sprintf("invalid date %q, use YYYY-MM-DD, YYYY-MM or YYYY", text)}
+		}
+		return start, end, nil
+	}
+	if filter.Operator == ".." {
+		var clauses []string
+		var args []interface{}
+		if filter.Value != "" {
+			start, _, err := parse(filter.Value)
+			if err != nil {
+				return "", nil, err
+			}
+			clauses = append(clauses, column+" >= ?")
+			args = append(args, start)
+		}
+		if filter.ValueTo != "" {
+			_, end, err := parse(filter.ValueTo)
+			if err != nil {
+				return "", nil, err
+			}
+			clauses = append(clauses, column+" < ?")
+			args = append(args, end)
+		}
+		if len(clauses) == 0 {
+			return "", nil, &FieldError{Pos: filter.Pos, Message: "empty date range"}
+		}
+		return "(" + strings.Join(clauses, " AND ") + ")", args, nil
+	}
+	start, end, err := parse(filter.Value)
+	if err != nil {
+		return "", nil, err
+	}
+	switch filter.Operator {
+	case ":", "=":
+		return "(" + column + " >= ? AND " + column + " < ?)", []interface{}{start, end}, nil
+	case "!=":
+		return "NOT (" + column + " >= ? AND " + column + " < ?)", []interface{}{start, end}, nil
+	case ">":
+		return column + " >= ?", []interface{}{end}, nil
+	case ">=":
+		return column + " >= ?", []interface{}{start}, nil
+	case "<":
+		return column + " < ?", []interface{}{start}, nil
+	default:
+		return column + " < ?", []interface{}{end}, nil
+	}
+}
+
+// datePeriod returns the start of the period a date names and the start of
+// the following period.
+func datePeriod(text string) (time.Time, time.Time, bool) {
+	if day, err := time.Parse(time.DateOnly, text); err == nil {
+		return day, day.AddDate(0, 0, 1), true
+	}
+	if month, err := time.Parse("2006-01", text); err == nil {
+		return month, month.AddDate(0, 1, 0), true
+	}
+	if year, err := time.Parse("2006", text); err == nil {
+		return year, year.AddDate(1, 0, 0), true
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// likePattern builds a case-insensitive contains pattern, escaping the LIKE
+// wildcards in the value.
+func likePattern(value string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(strings.ToLower(value))
+	return "%" + escaped + "%"
+}
@@ -0,0 +1,108 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenLParen
+	tokenRParen
+	tokenAnd
+	tokenOr
+	tokenNot
+)
+
+type token struct {
+	kind tokenKind
+	// text is the word with quotes removed. For a filter such as
+	// amount>=50 it holds the whole term.
+	text string
+	// quoted reports whether the word started with a quote, making it plain
+	// text rather than a keyword or a filter.
+	quoted bool
+	pos    int
+}
+
+// SyntaxError describes a query that cannot be parsed.
+type SyntaxError struct {
+	Pos     int
+	Message string
+}
+
+func (err *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid query at position %d: %s", err.Pos+1, err.Message)
+}
+
+// lex splits a query into tokens. Words end at whitespace or a parenthesis
+// outside of quotes, so category:"eating out" is a single word.
+func lex(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) && runes[i+1] != ')' && (i == 0 || !isWordRune(runes[i-1])):
+			tokens = append(tokens, token{kind: tokenNot, text: "-", pos: i})
+			i++
+		default:
+			start := i
+			var word strings.Builder
+			quoted := runes[i] == '"'
+			hasQuote := false
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' {
+				if runes[i] != '"' {
+					word.WriteRune(runes[i])
+					i++
+					continue
+				}
+				hasQuote = true
+				i++
+				closed := false
+				for i < len(runes) {
+					if runes[i] == '"' {
+						closed = true
+						i++
+						break
+					}
+					word.WriteRune(runes[i])
+					i++
+				}
+				if !closed {
+					return nil, &SyntaxError{Pos: start, Message: "unterminated quote"}
+				}
+			}
+			text := word.String()
+			kind := tokenWord
+			if !hasQuote {
+				switch text {
+				case "AND", "&&":
+					kind = tokenAnd
+				case "OR", "||":
+					kind = tokenOr
+				case "NOT", "!":
+					kind = tokenNot
+				}
+			}
+			tokens = append(tokens, token{kind: kind, text: text, quoted: quoted, pos: start})
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+func isWordRune(r rune) bool {
+	return !unicode.IsSpace(r) && r != '(' && r != ')'
+}
@@ -0,0 +1,262 @@
+package query
+
+import (
+	"strings"
+)
+
+// maxDepth limits how deeply groups and negations can nest.
+const maxDepth = 32
+
+// MaxLength is the longest query accepted.
+const MaxLength = 1000
+
+// Node is a node of a parsed query.
+type Node interface {
+	node()
+}
+
+type And struct {
+	Left, Right Node
+}
+
+type Or struct {
+	Left, Right Node
+}
+
+type Not struct {
+	Node Node
+}
+
+// Filter compares a field with a value, e.g. amount>=50 or
+// date:2024-01-01..2024-03-31. ValueTo is set for ranges.
+type Filter struct {
+	Field    string
+	Operator string
+	Value    string
+	ValueTo  string
+	Pos      int
+}
+
+// Text matches free text against the description, payee and reference.
+type Text struct {
+	Value string
+}
+
+func (And) node()    {}
+func (Or) node()     {}
+func (Not) node()    {}
+func (Filter) node() {}
+func (Text) node()   {}
+
+// Sort orders the results by a field.
+type Sort struct {
+	Field      string
+	Descending bool
+}
+
+// Query is a parsed search. Root is nil when the query only sorts.
+type Query struct {
+	Root Node
+	Sort []Sort
+}
+
+var operators = []string{">=", "<=", "!=", ":", "=", ">", "<"}
+
+// Parse reads a search query. Terms are joined with AND unless OR is given,
+// AND binds tighter than OR, parentheses group and NOT or a leading "-"
+// negates. A term is either field<op>value or free text, e.g.
+//
+//	category:groceries amount>50 -tag:reimbursed
+//	(payee:uber OR payee:bolt) date:2024-01-01..2024-06-30 sort:-amount
+func Parse(input string) (*Query, error) {
+	if len([]rune(input)) > MaxLength {
+		return nil, &SyntaxError{Pos: MaxLength, Message: "query is too long"}
+	}
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, query: &Query{}}
+	if p.peek().kind == tokenEOF {
+		return p.query, nil
+	}
+	root, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, &SyntaxError{Pos: next.pos, Message: "unexpected " + describe(next)}
+	}
+	p.query.Root = root
+	return p.query, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	query  *Query
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) parseOr(depth int) (Node, error) {
+	left, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOr {
+		p.next()
+		right, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = join(left, right, false)
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd(depth int) (Node, error) {
+	left, err := p.parseUnary(depth)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch p.peek().kind {
+		case tokenAnd:
+			p.next()
+		case tokenWord, tokenNot, tokenLParen:
+		default:
+			return left, nil
+		}
+		right, err := p.parseUnary(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = join(left, right, true)
+	}
+}
+
+func (p *parser) parseUnary(depth int) (Node, error) {
+	if depth > maxDepth {
+		return nil, &SyntaxError{Pos: p.peek().pos, Message: "query is nested too deeply"}
+	}
+	if p.peek().kind == tokenNot {
+		not := p.next()
+		operand, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if operand == nil {
+			return nil, &SyntaxError{Pos: not.pos, Message: "sort cannot be negated"}
+		}
+		return Not{Node: operand}, nil
+	}
+	return p.parsePrimary(depth)
+}
+
+func (p *parser) parsePrimary(depth int) (Node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenLParen:
+		inner, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, &SyntaxError{Pos: closing.pos, Message: "expected )"}
+		}
+		return inner, nil
+	case tokenWord:
+		return p.parseTerm(t)
+	}
+	return nil, &SyntaxError{Pos: t.pos, Message: "unexpected " + describe(t)}
+}
+
+// parseTerm turns a word into a filter, a sort or free text. Sort terms are
+// collected on the query and yield a nil node.
+func (p *parser) parseTerm(t token) (Node, error) {
+	if t.quoted {
+		return Text{Value: t.text}, nil
+	}
+	field, operator, value, ok := splitFilter(t.text)
+	if !ok {
+		return Text{Value: t.text}, nil
+	}
+	field = strings.ToLower(field)
+	if field == "sort" {
+		if operator != ":" || value == "" {
+			return nil, &SyntaxError{Pos: t.pos, Message: "sort must look like sort:field or sort:-field"}
+		}
+		for _, key := range strings.Split(value, ",") {
+			sort := Sort{Field: strings.ToLower(strings.TrimPrefix(key, "-")), Descending: strings.HasPrefix(key, "-")}
+			p.query.Sort = append(p.query.Sort, sort)
+		}
+		return nil, nil
+	}
+	if value == "" {
+		return nil, &SyntaxError{Pos: t.pos, Message: "missing value for " + field}
+	}
+	filter := Filter{Field: field, Operator: operator, Value: value, Pos: t.pos}
+	if operator == ":" {
+		if from, to, found := strings.Cut(value, ".."); found {
+			filter.Operator = ".."
+			filter.Value = from
+			filter.ValueTo = to
+		}
+	}
+	return filter, nil
+}
+
+// splitFilter finds the operator of field<op>value. Field names are letters
+// and underscores, anything else is free text.
+func splitFilter(text string) (field, operator, value string, ok bool) {
+	for i, r := range text {
+		if r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') {
+			continue
+		}
+		if i == 0 {
+			return "", "", "", false
+		}
+		for _, candidate := range operators {
+			if strings.HasPrefix(text[i:], candidate) {
+				return text[:i], candidate, text[i+len(candidate):], true
+			}
+		}
+		return "", "", "", false
+	}
+	return "", "", "", false
+}
+
+// join combines two nodes, dropping the nil nodes left by sort terms.
+func join(left, right Node, and bool) Node {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+	if and {
+		return And{Left: left, Right: right}
+	}
+	return Or{Left: left, Right: right}
+}
+
+func describe(t token) string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of query"
+	case tokenWord:
+		return "\"" + t.text + "\""
+	}
+	return t.text
+}
@@ -0,0 +1,292 @@
+package recurrence
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	Daily   = "DAILY"
+	Weekly  = "WEEKLY"
+	Monthly = "MONTHLY"
+	Yearly  = "YEARLY"
+)
+
+// maxPeriods bounds how far a rule is expanded so a bad rule cannot loop
+// forever.
+const maxPeriods = 100000
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Rule is a subset of the iCalendar RRULE: FREQ, INTERVAL, BYDAY (weekly),
+// BYMONTHDAY (monthly), COUNT and UNTIL, e.g. "FREQ=MONTHLY;BYMONTHDAY=1;COUNT=12".
+// Month days past the end of a month fall on its last day, so BYMONTHDAY=31
+// means the last day of every month. Negative month days count from the end.
+type Rule struct {
+	Freq       string
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonthDay []int
+	Count      int
+	Until      time.Time
+}
+
+// Parse reads a rule from its RRULE text. The "RRULE:" prefix is optional.
+func Parse(text string) (Rule, error) {
+	rule := Rule{Interval: 1}
+	text = strings.TrimPrefix(strings.TrimSpace(strings.ToUpper(text)), "RRULE:")
+	if text == "" {
+		return rule, fmt.Errorf("empty recurrence rule")
+	}
+	for _, part := range strings.Split(text, ";") {
+		if part == "" {
+			continue
+		}
+		key, value, found := strings.Cut(part, "=")
+		if !found {
+			return rule, fmt.Errorf("invalid recurrence rule part %q", part)
+		}
+		switch key {
+		case "FREQ":
+			switch value {
+			case Daily, Weekly, Monthly, Yearly:
+				rule.Freq = value
+			default:
+				return rule, fmt.Errorf("unsupported frequency %q", value)
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return rule, fmt.Errorf("invalid interval %q", value)
+			}
+			rule.Interval = interval
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := weekdays[day]
+				if !ok {
+					return rule, fmt.Errorf("invalid weekday %q", day)
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(value, ",") {
+				monthDay, err := strconv.Atoi(day)
+				if err != nil || monthDay == 0 || monthDay < -31 || monthDay > 31 {
+					return rule, fmt.Errorf("invalid month day %q", day)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, monthDay)
+			}
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 {
+				return rule, fmt.Errorf("invalid count %q", value)
+			}
+			rule.Count = count
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return rule, err
+			}
+			rule.Until = until
+		default:
+			return rule, fmt.Errorf("unsupported recurrence rule part %q", key)
+		}
+	}
+	if rule.Freq == "" {
+		return rule, fmt.Errorf("recurrence rule has no FREQ")
+	}
+	if len(rule.ByDay) > 0 && rule.Freq != Weekly {
+		return rule, fmt.Errorf("BYDAY is only supported with FREQ=WEEKLY")
+	}
+	if len(rule.ByMonthDay) > 0 && rule.Freq != Monthly {
+		return rule, fmt.Errorf("BYMONTHDAY is only supported with FREQ=MONTHLY")
+	}
+	return rule, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102", time.DateOnly, "20060102T150405Z"} {
+		if until, err := time.Parse(layout, value); err == nil {
+			return until, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid until date %q", value)
+}
+
+// String formats the rule back into RRULE text.
+func (rule Rule) String() string {
+	parts := []string{"FREQ=" + rule.Freq}
+	if rule.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(rule.Interval))
+	}
+	if len(rule.ByDay) > 0 {
+		var days []string
+		for _, weekday := range rule.ByDay {
+			for name, day := range weekdays {
+				if day == weekday {
+					days = append(days, name)
+				}
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(rule.ByMonthDay) > 0 {
+		var days []string
+		for _, day := range rule.ByMonthDay {
+			days = append(days, strconv.Itoa(day))
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if rule.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(rule.Count))
+	}
+	if !rule.Until.IsZero() {
+		parts = append(parts, "UNTIL="+rule.Until.Format("20060102"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Between returns the occurrences of the rule, starting at start, that fall
+// within [from, to]. At most limit occurrences are returned when limit is
+// positive.
+func (rule Rule) Between(start, from, to time.Time, limit int) []time.Time {
+	var occurrences []time.Time
+	rule.each(start, func(occurrence time.Time) bool {
+		if occurrence.After(to) {
+			return false
+		}
+		if !occurrence.Before(from) {
+			occurrences = append(occurrences, occurrence)
+		}
+		return limit <= 0 || len(occurrences) < limit
+	})
+	return occurrences
+}
+
+// Next returns the first occurrence strictly after the given time. The second
+// value is false once the rule has ended.
+func (rule Rule) Next(start, after time.Time) (time.Time, bool) {
+	var next time.Time
+	found := false
+	rule.each(start, func(occurrence time.Time) bool {
+		if occurrence.After(after) {
+			next = occurrence
+			found = true
+			return false
+		}
+		return true
+	})
+	return next, found
+}
+
+// First returns the first occurrence on or after start.
+func (rule Rule) First(start time.Time) (time.Time, bool) {
+	return rule.Next(start, truncateDay(start).Add(-time.Nanosecond))
+}
+
+// each calls yield with every occurrence in order until yield returns false or
+// the rule ends through COUNT or UNTIL.
+func (rule Rule) each(start time.Time, yield func(time.Time) bool) {
+	start = truncateDay(start)
+	until := truncateDay(rule.Until)
+	interval := rule.Interval
+	if interval < 1 {
+		interval = 1
+	}
+	count := 0
+	for period := 0; period < maxPeriods; period++ {
+		for _, occurrence := range rule.period(start, period*interval) {
+			if occurrence.Before(start) {
+				continue
+			}
+			if !rule.Until.IsZero() && occurrence.After(until) {
+				return
+			}
+			count++
+			if !yield(occurrence) {
+				return
+			}
+			if rule.Count > 0 && count >= rule.Count {
+				return
+			}
+		}
+	}
+}
+
+// period returns the sorted occurrences in the period that is offset
+// frequency units after the one containing start.
+func (rule Rule) period(start time.Time, offset int) []time.Time {
+	switch rule.Freq {
+	case Daily:
+		return []time.Time{start.AddDate(0, 0, offset)}
+	case Weekly:
+		if len(rule.ByDay) == 0 {
+			return []time.Time{start.AddDate(0, 0, 7*offset)}
+		}
+		monday := start.AddDate(0, 0, -((int(start.Weekday())+6)%7)+7*offset)
+		var days []time.Time
+		for _, weekday := range rule.ByDay {
+			days = append(days, monday.AddDate(0, 0, (int(weekday)+6)%7))
+		}
+		return sortDays(days)
+	case Monthly:
+		firstOfMonth := time.Date(start.Year(), start.Month()+time.Month(offset), 1, 0, 0, 0, 0, start.Location())
+		monthDays := rule.ByMonthDay
+		if len(monthDays) == 0 {
+			monthDays = []int{start.Day()}
+		}
+		var days []time.Time
+		for _, monthDay := range monthDays {
+			days = append(days, dayOfMonth(firstOfMonth, monthDay))
+		}
+		return sortDays(days)
+	case Yearly:
+		firstOfMonth := time.Date(start.Year()+offset, start.Month(), 1, 0, 0, 0, 0, start.Location())
+		return []time.Time{dayOfMonth(firstOfMonth, start.Day())}
+	}
+	return nil
+}
+
+func dayOfMonth(firstOfMonth time.Time, monthDay int) time.Time {
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	day := monthDay
+	if day < 0 {
+		day = lastDay + day + 1
+	}
+	if day < 1 {
+		day = 1
+	}
+	if day > lastDay {
+		day = lastDay
+	}
+	return firstOfMonth.AddDate(0, 0, day-1)
+}
+
+func sortDays(days []time.Time) []time.Time {
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	var unique []time.Time
+	for _, day := range days {
+		if len(unique) == 0 || !day.Equal(unique[len(unique)-1]) {
+			unique = append(unique, day)
+		}
+	}
+	return unique
+}
+
+func truncateDay(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
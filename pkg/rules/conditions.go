@@ -0,0 +1,161 @@
+package rules
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/christo-andrew/haven/internal/models"
+)
+
+var textOperators = []string{
+	models.RuleOperatorMatches,
+	models.RuleOperatorContains,
+	models.RuleOperatorEquals,
+	models.RuleOperatorStartsWith,
+}
+
+var amountOperators = []string{
+	models.RuleOperatorEquals,
+	models.RuleOperatorGt,
+	models.RuleOperatorGte,
+	models.RuleOperatorLt,
+	models.RuleOperatorLte,
+	models.RuleOperatorBetween,
+}
+
+// ValidateCondition checks that a condition uses an operator supported by its
+// field and that its values can be evaluated.
+func ValidateCondition(condition models.RuleCondition) error {
+	switch condition.Field {
+	case models.RuleFieldDescription, models.RuleFieldPayee:
+		if !contains(textOperators, condition.Operator) {
+			return fmt.Errorf("operator %q is not supported for %s", condition.Operator, condition.Field)
+		}
+		if condition.Operator == models.RuleOperatorMatches {
+			if _, err := regexp.Compile(condition.Value); err != nil {
+				return fmt.Errorf("invalid pattern %q: %w", condition.Value, err)
+			}
+		}
+	case models.RuleFieldAmount:
+		if !contains(amountOperators, condition.Operator) {
+			return fmt.Errorf("operator %q is not supported for amount", condition.Operator)
+		}
+		if _, err := strconv.ParseFloat(condition.Value, 64); err != nil {
+			return fmt.Errorf("invalid amount %q", condition.Value)
+		}
+		if condition.Operator == models.RuleOperatorBetween {
+			if _, err := strconv.ParseFloat(condition.ValueTo, 64); err != nil {
+				return fmt.Errorf("invalid amount %q", condition.ValueTo)
+			}
+		}
+	case models.RuleFieldAccount:
+		if condition.Operator != models.RuleOperatorEquals {
+			return fmt.Errorf("operator %q is not supported for account", condition.Operator)
+		}
+		if _, err := strconv.Atoi(condition.Value); err != nil {
+			return fmt.Errorf("invalid account id %q", condition.Value)
+		}
+	case models.RuleFieldDirection:
+		if condition.Operator != models.RuleOperatorEquals {
+			return fmt.Errorf("operator %q is not supported for direction", condition.Operator)
+		}
+		if condition.Value != "debit" && condition.Value != "credit" {
+			return fmt.Errorf("direction must be debit or credit")
+		}
+	default:
+		return fmt.Errorf("unknown condition field %q", condition.Field)
+	}
+	return nil
+}
+
+// condition is a compiled RuleCondition, regular expressions and numbers are
+// parsed once when the engine is built rather than for every transaction.
+type condition struct {
+	models.RuleCondition
+	pattern *regexp.Regexp
+	from    float64
+	to      float64
+}
+
+func compileCondition(ruleCondition models.RuleCondition) (condition, error) {
+	compiled := condition{RuleCondition: ruleCondition}
+	if err := ValidateCondition(ruleCondition); err != nil {
+		return compiled, err
+	}
+	switch ruleCondition.Field {
+	case models.RuleFieldDescription, models.RuleFieldPayee:
+		if ruleCondition.Operator == models.RuleOperatorMatches {
+			compiled.pattern = regexp.MustCompile("(?i)" + ruleCondition.Value)
+		}
+	case models.RuleFieldAmount:
+		compiled.from, _ = strconv.ParseFloat(ruleCondition.Value, 64)
+		compiled.to, _ = strconv.ParseFloat(ruleCondition.ValueTo, 64)
+	case models.RuleFieldAccount:
+		accountId, _ := strconv.Atoi(ruleCondition.Value)
+		compiled.from = float64(accountId)
+	}
+	return compiled, nil
+}
+
+// matches evaluates the condition against a transaction. Amount conditions
+// compare the absolute amount, use a direction condition to tell spending and
+// income apart.
+func (c condition) matches(transaction *models.Transaction) bool {
+	switch c.Field {
+	case models.RuleFieldDescription:
+		return c.matchesText(transaction.Description)
+	case models.RuleFieldPayee:
+		return c.matchesText(transaction.Payee)
+	case models.RuleFieldAmount:
+		return c.matchesAmount(math.Abs(transaction.Amount))
+	case models.RuleFieldAccount:
+		return transaction.AccountID == int(c.from)
+	case models.RuleFieldDirection:
+		return transaction.Direction() == c.Value
+	}
+	return false
+}
+
+func (c condition) matchesText(text string) bool {
+	switch c.Operator {
+	case models.RuleOperatorMatches:
+		return c.pattern.MatchString(text)
+	case models.RuleOperatorContains:
+		return strings.Contains(strings.ToLower(text), strings.ToLower(c.Value))
+	case models.RuleOperatorEquals:
+		return strings.EqualFold(strings.TrimSpace(text), strings.TrimSpace(c.Value))
+	case models.RuleOperatorStartsWith:
+		return strings.HasPrefix(strings.ToLower(text), strings.ToLower(c.Value))
+	}
+	return false
+}
+
+func (c condition) matchesAmount(amount float64) bool {
+	switch c.Operator {
+	case models.RuleOperatorEquals:
+		return math.Abs(amount-c.from) < 0.005
+	case models.RuleOperatorGt:
+		return amount > c.from
+	case models.RuleOperatorGte:
+		return amount >= c.from
+	case models.RuleOperatorLt:
+		return amount < c.from
+	case models.RuleOperatorLte:
+		return amount <= c.from
+	case models.RuleOperatorBetween:
+		return amount >= c.from && amount <= c.to
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,223 @@
+package rules
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/database/scopes"
+	"gorm.io/gorm"
+)
+
+// Change describes a single field a rule changed on a transaction.
+type Change struct {
+	RuleID   int    `json:"rule_id"`
+	RuleName string `json:"rule"`
+	Field    string `json:"field"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	tag      *models.Tag
+}
+
+type compiledRule struct {
+	models.Rule
+	conditions []condition
+}
+
+// matches reports whether every condition of the rule holds. A rule without
+// conditions never matches so that a half-configured rule cannot rewrite a
+// user's whole history.
+func (rule compiledRule) matches(transaction *models.Transaction) bool {
+	if len(rule.conditions) == 0 {
+		return false
+	}
+	for _, c := range rule.conditions {
+		if !c.matches(transaction) {
+			return false
+		}
+	}
+	return true
+}
+
+// Engine applies a user's rules to transactions in priority order.
+type Engine struct {
+	db         *gorm.DB
+	rules      []compiledRule
+	categories map[int]*models.Category
+	tags       map[int]*models.Tag
+}
+
+// NewEngine loads the enabled rules of a user. When rule ids are given only
+// those rules are loaded.
+func NewEngine(userId uint, db *gorm.DB, ruleIds ...int) *Engine {
+	var userRules []models.Rule
+	query := scopes.GetUserRules(userId, db).Where("enabled = ?", true)
+	if len(ruleIds) > 0 {
+		query = query.Where("id IN ?", ruleIds)
+	}
+	query.Find(&userRules)
+	return NewEngineFromRules(userRules, db)
+}
+
+// NewEngineFromRules builds an engine from rules that are already loaded.
+// Rules are expected to be sorted by priority.
+func NewEngineFromRules(rules []models.Rule, db *gorm.DB) *Engine {
+	engine := &Engine{
+		db:         db,
+		categories: make(map[int]*models.Category),
+		tags:       make(map[int]*models.Tag),
+	}
+	for _, rule := range rules {
+		compiled := compiledRule{Rule: rule}
+		valid := true
+		for _, ruleCondition := range rule.Conditions {
+			c, err := compileCondition(ruleCondition)
+			if err != nil {
+				log.Printf("skipping rule %d: %v", rule.ID, err)
+				valid = false
+				break
+			}
+			compiled.conditions = append(compiled.conditions, c)
+		}
+		if valid {
+			engine.rules = append(engine.rules, compiled)
+		}
+	}
+	return engine
+}
+
+// Apply runs the rules against the transaction, changing it in place, and
+// returns what was changed. Nothing is written to the database.
+func (engine *Engine) Apply(transaction *models.Transaction) []Change {
+	var changes []Change
+	for _, rule := range engine.rules {
+		if !rule.matches(transaction) {
+			continue
+		}
+		for _, action := range rule.Actions {
+			change, changed := engine.applyAction(action, transaction)
+			if !changed {
+				continue
+			}
+			change.RuleID = rule.ID
+			change.RuleName = rule.Name
+			changes = append(changes, change)
+		}
+		if rule.StopProcessing {
+			break
+		}
+	}
+	return changes
+}
+
+func (engine *Engine) applyAction(action models.RuleAction, transaction *models.Transaction) (Change, bool) {
+	switch action.Type {
+	case models.RuleActionSetCategory:
+		category := engine.category(action.Value)
+		if category == nil || transaction.CategoryID == category.ID {
+			return Change{}, false
+		}
+		change := Change{Field: "category", From: transaction.Category.Name, To: category.Name}
+		transaction.CategoryID = category.ID
+		transaction.Category = *category
+		return change, true
+	case models.RuleActionSetType:
+		transactionType := engine.category(action.Value)
+		if transactionType == nil || transaction.TransactionTypeID == transactionType.ID {
+			return Change{}, false
+		}
+		change := Change{Field: "transaction_type", From: transaction.TransactionType.Name, To: transactionType.Name}
+		transaction.TransactionTypeID = transactionType.ID
+		transaction.TransactionType = *transactionType
+		return change, true
+	case models.RuleActionSetPayee:
+		if transaction.Payee == action.Value {
+			return Change{}, false
+		}
+		change := Change{Field: "payee", From: transaction.Payee, To: action.Value}
+		transaction.Payee = action.Value
+		return change, true
+	case models.RuleActionAddTag:
+		tag := engine.tag(action.Value)
+		if tag == nil {
+			return Change{}, false
+		}
+		for _, existing := range transaction.Tags {
+			if existing.ID == tag.ID {
+				return Change{}, false
+			}
+		}
+		transaction.Tags = append(transaction.Tags, *tag)
+		return Change{Field: "tags", To: tag.Name, tag: tag}, true
+	case models.RuleActionMarkTransfer:
+		if transaction.IsTransfer {
+			return Change{}, false
+		}
+		transaction.IsTransfer = true
+		return Change{Field: "is_transfer", From: "false", To: "true"}, true
+	}
+	return Change{}, false
+}
+
+// Save writes the changes made by Apply to an existing transaction.
+func (engine *Engine) Save(transaction *models.Transaction, changes []Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	return engine.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.Transaction{}).Where("id = ?", transaction.ID).Updates(map[string]interface{}{
+			"category_id":         transaction.CategoryID,
+			"transaction_type_id": transaction.TransactionTypeID,
+			"payee":               transaction.Payee,
+			"is_transfer":         transaction.IsTransfer,
+		}).Error
+		if err != nil {
+			return err
+		}
+		for _, change := range changes {
+			if change.tag == nil {
+				continue
+			}
+			if err := tx.Model(transaction).Association("Tags").Append(change.tag); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (engine *Engine) category(value string) *models.Category {
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return nil
+	}
+	if category, ok := engine.categories[id]; ok {
+		return category
+	}
+	var category models.Category
+	engine.db.First(&category, id)
+	if category.ID == 0 {
+		engine.categories[id] = nil
+		return nil
+	}
+	engine.categories[id] = &category
+	return &category
+}
+
+func (engine *Engine) tag(value string) *models.Tag {
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return nil
+	}
+	if tag, ok := engine.tags[id]; ok {
+		return tag
+	}
+	var tag models.Tag
+	engine.db.First(&tag, id)
+	if tag.ID == 0 {
+		engine.tags[id] = nil
+		return nil
+	}
+	engine.tags[id] = &tag
+	return &tag
+}
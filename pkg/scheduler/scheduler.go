@@ -0,0 +1,74 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job is a unit of background work run by the scheduler.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler runs jobs on fixed intervals in background goroutines. Every job
+// runs once when the scheduler starts and then after each interval. A job is
+// never run concurrently with itself.
+type Scheduler struct {
+	jobs   []Job
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+// Every registers a job. Jobs must be registered before Start is called.
+func (scheduler *Scheduler) Every(interval time.Duration, name string, run func(ctx context.Context) error) {
+	scheduler.jobs = append(scheduler.jobs, Job{Name: name, Interval: interval, Run: run})
+}
+
+func (scheduler *Scheduler) Start(ctx context.Context) {
+	ctx, scheduler.cancel = context.WithCancel(ctx)
+	for _, job := range scheduler.jobs {
+		scheduler.wg.Add(1)
+		go scheduler.loop(ctx, job)
+	}
+}
+
+// Stop cancels the running jobs and waits for them to return.
+func (scheduler *Scheduler) Stop() {
+	if scheduler.cancel != nil {
+		scheduler.cancel()
+	}
+	scheduler.wg.Wait()
+}
+
+func (scheduler *Scheduler) loop(ctx context.Context, job Job) {
+	defer scheduler.wg.Done()
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+	for {
+		runJob(ctx, job)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func runJob(ctx context.Context, job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("scheduler: job %s panicked: %v", job.Name, r)
+		}
+	}()
+	if err := job.Run(ctx); err != nil {
+		log.Printf("scheduler: job %s failed: %v", job.Name, err)
+	}
+}
@@ -0,0 +1,45 @@
+package search
+
+import (
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/database/scopes"
+	"gorm.io/gorm"
+)
+
+const (
+	transactionMatch = "MATCH(transactions.description, transactions.payee, transactions.reference, transactions.notes) AGAINST (? IN NATURAL LANGUAGE MODE)"
+	tagMatch         = "MATCH(tags.name) AGAINST (? IN NATURAL LANGUAGE MODE)"
+	transactionTags  = "FROM transaction_tags INNER JOIN tags ON tags.id = transaction_tags.tag_id WHERE transaction_tags.transaction_id = transactions.id AND tags.deleted_at IS NULL"
+)
+
+// FullTextSearcher searches with the MySQL FULLTEXT indexes created by the
+// migrations. MySQL ranks the matches; tag matches add to the score of the
+// transaction.
+type FullTextSearcher struct {
+	db *gorm.DB
+}
+
+func NewFullTextSearcher(db *gorm.DB) *FullTextSearcher {
+	return &FullTextSearcher{db: db}
+}
+
+func (searcher *FullTextSearcher) Search(userId int, text string, offset int, limit int) ([]Hit, int, error) {
+	matching := searcher.db.Model(&models.Transaction{}).
+		Scopes(scopes.UserTransactions(userId)).
+		Where("("+transactionMatch+" OR EXISTS (SELECT 1 "+transactionTags+" AND "+tagMatch+"))", text, text).
+		Session(&gorm.Session{})
+
+	var total int64
+	if err := matching.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var hits []Hit
+	err := matching.
+		Select("transactions.id AS transaction_id, "+transactionMatch+" + COALESCE((SELECT MAX("+tagMatch+") "+transactionTags+"), 0) AS score", text, text).
+		Order("score DESC").
+		Order("transactions.date DESC").
+		Offset(offset).
+		Limit(limit).
+		Scan(&hits).Error
+	return hits, int(total), err
+}
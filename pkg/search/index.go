@@ -0,0 +1,199 @@
+package search
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/database/scopes"
+	"gorm.io/gorm"
+)
+
+// fieldWeights make a match in the payee or a tag count for more than one in
+// a long description or a reference number.
+var fieldWeights = map[string]float64{
+	"description": 1,
+	"payee":       1.5,
+	"reference":   0.5,
+	"notes":       1,
+	"tags":        1.5,
+}
+
+// BM25 parameters.
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// IndexSearcher keeps an inverted index of each user's transactions in
+// memory for databases without full-text search. An index is rebuilt when
+// the user's transactions or tags have changed since it was built.
+type IndexSearcher struct {
+	db      *gorm.DB
+	mu      sync.Mutex
+	indexes map[int]*index
+}
+
+func NewIndexSearcher(db *gorm.DB) *IndexSearcher {
+	return &IndexSearcher{db: db, indexes: make(map[int]*index)}
+}
+
+func (searcher *IndexSearcher) Search(userId int, text string, offset int, limit int) ([]Hit, int, error) {
+	idx, err := searcher.index(userId)
+	if err != nil {
+		return nil, 0, err
+	}
+	hits := idx.search(queryTerms(text))
+	total := len(hits)
+	if offset >= total {
+		return nil, total, nil
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return hits[offset:end], total, nil
+}
+
+func (searcher *IndexSearcher) index(userId int) (*index, error) {
+	version, err := searcher.version(userId)
+	if err != nil {
+		return nil, err
+	}
+	searcher.mu.Lock()
+	defer searcher.mu.Unlock()
+	if idx, ok := searcher.indexes[userId]; ok && idx.version == version {
+		return idx, nil
+	}
+	var transactions []models.Transaction
+	err = searcher.db.Model(&models.Transaction{}).
+		Scopes(scopes.UserTransactions(userId)).
+		Preload("Tags").
+		Select("id", "date", "description", "payee", "reference", "notes").
+		Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+	idx := buildIndex(transactions)
+	idx.version = version
+	searcher.indexes[userId] = idx
+	return idx, nil
+}
+
+// version summarises the state of a user's transactions and tags. Any
+// insert, update or delete changes a count or a last modified time.
+func (searcher *IndexSearcher) version(userId int) (string, error) {
+	var count, tagCount int64
+	var updated, tagUpdated sql.NullString
+	err := searcher.db.Model(&models.Transaction{}).
+		Scopes(scopes.UserTransactions(userId)).
+		Select("COUNT(*), MAX(transactions.updated_at)").
+		Row().Scan(&count, &updated)
+	if err != nil {
+		return "", err
+	}
+	userTransactions := searcher.db.Model(&models.Transaction{}).Select("id").Scopes(scopes.UserTransactions(userId))
+	err = searcher.db.Table("transaction_tags").
+		Joins("INNER JOIN tags ON tags.id = transaction_tags.tag_id AND tags.deleted_at IS NULL").
+		Where("transaction_tags.transaction_id IN (?)", userTransactions).
+		Select("COUNT(*), MAX(tags.updated_at)").
+		Row().Scan(&tagCount, &tagUpdated)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d|%s|%d|%s", count, updated.String, tagCount, tagUpdated.String), nil
+}
+
+type index struct {
+	version string
+	// postings maps a word to how often it appears in each transaction,
+	// weighted by field and normalised by the length of the field (BM25F).
+	postings map[string]map[int]float64
+	dates    map[int]time.Time
+}
+
+func buildIndex(transactions []models.Transaction) *index {
+	idx := &index{
+		postings: make(map[string]map[int]float64),
+		dates:    make(map[int]time.Time, len(transactions)),
+	}
+	words := make([]map[string][]string, len(transactions))
+	averageLengths := make(map[string]float64, len(Fields))
+	for i, transaction := range transactions {
+		words[i] = make(map[string][]string, len(Fields))
+		for _, field := range Fields {
+			words[i][field] = tokenize(fieldText(transaction, field))
+			averageLengths[field] += float64(len(words[i][field]))
+		}
+	}
+	for field := range averageLengths {
+		averageLengths[field] /= float64(len(transactions))
+	}
+
+	for i, transaction := range transactions {
+		idx.dates[transaction.ID] = transaction.Date
+		for _, field := range Fields {
+			fieldWords := words[i][field]
+			norm := 1 - b + b*float64(len(fieldWords))/averageLengths[field]
+			for _, word := range fieldWords {
+				postings, ok := idx.postings[word]
+				if !ok {
+					postings = make(map[int]float64)
+					idx.postings[word] = postings
+				}
+				postings[transaction.ID] += fieldWeights[field] / norm
+			}
+		}
+	}
+	return idx
+}
+
+// search scores every transaction containing at least one of the terms,
+// newest first among equal scores.
+func (idx *index) search(terms []string) []Hit {
+	documents := float64(len(idx.dates))
+	scores := make(map[int]float64)
+	for _, term := range terms {
+		// A term may match several words by prefix; each transaction counts
+		// the best matching word once.
+		frequencies := make(map[int]float64)
+		for word, postings := range idx.postings {
+			if !matches(word, term) {
+				continue
+			}
+			for id, frequency := range postings {
+				if frequency > frequencies[id] {
+					frequencies[id] = frequency
+				}
+			}
+		}
+		if len(frequencies) == 0 {
+			continue
+		}
+		matching := float64(len(frequencies))
+		idf := math.Log(1 + (documents-matching+0.5)/(matching+0.5))
+		for id, frequency := range frequencies {
+			scores[id] += idf * frequency * (k1 + 1) / (frequency + k1)
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, Hit{TransactionID: id, Score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		left, right := idx.dates[hits[i].TransactionID], idx.dates[hits[j].TransactionID]
+		if !left.Equal(right) {
+			return left.After(right)
+		}
+		return hits[i].TransactionID > hits[j].TransactionID
+	})
+	return hits
+}
@@ -0,0 +1,108 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/christo-andrew/haven/internal/models"
+	"gorm.io/gorm"
+)
+
+// Hit is a transaction matching a search and how well it matches.
+type Hit struct {
+	TransactionID int
+	Score         float64
+}
+
+// Result is a matching transaction with the parts of it that matched.
+type Result struct {
+	Transaction models.Transaction
+	Score       float64
+	Snippets    []Snippet
+}
+
+// Searcher finds the transactions of a user matching free text, best match
+// first. It returns one page of hits and the total number of matches.
+type Searcher interface {
+	Search(userId int, text string, offset int, limit int) ([]Hit, int, error)
+}
+
+// New picks the search backend for the database: FULLTEXT indexes on MySQL
+// and an in-process index everywhere else.
+func New(db *gorm.DB) Searcher {
+	if db.Dialector.Name() == "mysql" {
+		return NewFullTextSearcher(db)
+	}
+	return NewIndexSearcher(db)
+}
+
+// Fields are the parts of a transaction that are searched, in the order
+// snippets are returned.
+var Fields = []string{"description", "payee", "reference", "notes", "tags"}
+
+// fieldText returns the searchable text of one field of a transaction.
+func fieldText(transaction models.Transaction, field string) string {
+	switch field {
+	case "description":
+		return transaction.Description
+	case "payee":
+		return transaction.Payee
+	case "reference":
+		return transaction.Reference
+	case "notes":
+		return transaction.Notes
+	case "tags":
+		names := make([]string, 0, len(transaction.Tags))
+		for _, tag := range transaction.Tags {
+			names = append(names, tag.Name)
+		}
+		return strings.Join(names, ", ")
+	}
+	return ""
+}
+
+// stopWords are dropped from queries so "that hotel in mombasa" ranks on the
+// words that matter.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "at": true, "by": true, "for": true, "from": true,
+	"in": true, "of": true, "on": true, "or": true, "that": true, "the": true, "this": true,
+	"to": true, "with": true,
+}
+
+// tokenize splits text into lower case words.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// queryTerms returns the distinct words of a query without stop words, unless
+// the query is nothing but stop words.
+func queryTerms(text string) []string {
+	var terms, all []string
+	seen := make(map[string]bool)
+	for _, word := range tokenize(text) {
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+		all = append(all, word)
+		if !stopWords[word] {
+			terms = append(terms, word)
+		}
+	}
+	if len(terms) == 0 {
+		return all
+	}
+	return terms
+}
+
+// matches reports whether a word of a document matches a query term. Terms of
+// three or more characters also match as prefixes, so "mombas" finds
+// "Mombasa".
+func matches(word, term string) bool {
+	if len(term) >= 3 {
+		return strings.HasPrefix(word, term)
+	}
+	return word == term
+}
@@ -0,0 +1,109 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/christo-andrew/haven/internal/models"
+)
+
+// snippetLength is roughly how many characters of a field a snippet shows.
+const snippetLength = 120
+
+// snippetContext is how many characters are kept before the first match.
+const snippetContext = 30
+
+// Snippet is an excerpt of a field with the matching words wrapped in
+// <mark> tags. The rest of the text is HTML escaped.
+type Snippet struct {
+	Field string
+	Text  string
+}
+
+// Snippets highlights the words of a search in each field of a transaction
+// that contains them.
+func Snippets(transaction models.Transaction, text string) []Snippet {
+	terms := queryTerms(text)
+	var snippets []Snippet
+	for _, field := range Fields {
+		if snippet, ok := highlight(fieldText(transaction, field), terms); ok {
+			snippets = append(snippets, Snippet{Field: field, Text: snippet})
+		}
+	}
+	return snippets
+}
+
+type span struct {
+	start, end int
+}
+
+func highlight(text string, terms []string) (string, bool) {
+	runes := []rune(text)
+	var marks []span
+	for start := 0; start < len(runes); {
+		if !isWordRune(runes[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(runes) && isWordRune(runes[end]) {
+			end++
+		}
+		word := strings.ToLower(string(runes[start:end]))
+		for _, term := range terms {
+			if matches(word, term) {
+				marks = append(marks, span{start, end})
+				break
+			}
+		}
+		start = end
+	}
+	if len(marks) == 0 {
+		return "", false
+	}
+
+	from, to := 0, len(runes)
+	if len(runes) > snippetLength {
+		from = marks[0].start - snippetContext
+		if from < 0 {
+			from = 0
+		}
+		// Start on a word boundary rather than in the middle of a word.
+		for from > 0 && from < marks[0].start && isWordRune(runes[from-1]) {
+			from++
+		}
+		to = from + snippetLength
+		if to > len(runes) {
+			to = len(runes)
+		}
+		for to < len(runes) && to > marks[0].end && isWordRune(runes[to]) {
+			to--
+		}
+	}
+
+	var builder strings.Builder
+	if from > 0 {
+		builder.WriteString("…")
+	}
+	position := from
+	for _, mark := range marks {
+		if mark.start < from || mark.end > to {
+			continue
+		}
+		builder.WriteString(html.EscapeString(string(runes[position:mark.start])))
+		builder.WriteString("<mark>")
+		builder.WriteString(html.EscapeString(string(runes[mark.start:mark.end])))
+		builder.WriteString("</mark>")
+		position = mark.end
+	}
+	builder.WriteString(html.EscapeString(string(runes[position:to])))
+	if to < len(runes) {
+		builder.WriteString("…")
+	}
+	return builder.String(), true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
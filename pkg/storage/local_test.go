@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStorageRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	local, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := "attachments/1/42/receipt.pdf"
+	if err := local.Put(ctx, key, strings.NewReader("receipt"), "application/pdf"); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	body, err := local.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	data, err := io.ReadAll(body)
+	body.Close()
+	if err != nil || string(data) != "receipt" {
+		t.Fatalf("get returned %q, %v", data, err)
+	}
+
+	if err := local.Put(ctx, key, strings.NewReader("replaced"), ""); err != nil {
+		t.Fatalf("overwrite: %v", err)
+	}
+	body, _ = local.Get(ctx, key)
+	data, _ = io.ReadAll(body)
+	body.Close()
+	if string(data) != "replaced" {
+		t.Fatalf("overwrite left %q", data)
+	}
+
+	if err := local.Delete(ctx, key); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := local.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("get after delete: want ErrNotFound, got %v", err)
+	}
+	if err := local.Delete(ctx, key); err != nil {
+		t.Fatalf("deleting a missing object: %v", err)
+	}
+}
+
+func TestLocalStorageLeavesNoTemporaryFiles(t *testing.T) {
+	ctx := context.Background()
+	local, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := local.Put(ctx, "a/b", strings.NewReader("data"), ""); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(filepath.Join(local.Root, "a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "b" {
+		t.Fatalf("unexpected files %v", entries)
+	}
+}
+
+func TestLocalStorageKeepsKeysBelowRoot(t *testing.T) {
+	ctx := context.Background()
+	parent := t.TempDir()
+	local, err := NewLocalStorage(filepath.Join(parent, "root"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := local.Put(ctx, "../outside", strings.NewReader("data"), ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(parent, "outside")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("object was written outside the root: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(local.Root, "outside")); err != nil {
+		t.Fatalf("object was not written below the root: %v", err)
+	}
+}
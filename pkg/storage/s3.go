@@ -0,0 +1,215 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// S3Config describes an S3 compatible bucket. Endpoint is the base URL of the
+// service, e.g. "https://s3.eu-west-1.amazonaws.com" or "http://localhost:9000"
+// for MinIO. Path style addressing puts the bucket in the path rather than the
+// host name, which is what most self-hosted services expect.
+type S3Config struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	UsePathStyle    bool
+}
+
+// S3Storage talks to an S3 compatible service over its REST API, signing
+// requests with AWS Signature Version 4.
+type S3Storage struct {
+	config   S3Config
+	endpoint *url.URL
+	client   *http.Client
+	now      func() time.Time
+}
+
+func NewS3Storage(config S3Config) (*S3Storage, error) {
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", config.Endpoint)
+	}
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("s3 bucket cannot be empty")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	return &S3Storage{
+		config:   config,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 60 * time.Second},
+		now:      time.Now,
+	}, nil
+}
+
+// Put uploads the object in a single request. The body is buffered because
+// the payload hash is part of the signature.
+func (s3 *S3Storage) Put(ctx context.Context, key string, body io.Reader, contentType string) error {
+	payload, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	request, err := s3.request(ctx, http.MethodPut, key, payload)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	response, err := s3.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	return s3.check(response)
+}
+
+func (s3 *S3Storage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	request, err := s3.request(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := s3.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if err := s3.check(response); err != nil {
+		response.Body.Close()
+		return nil, err
+	}
+	return response.Body, nil
+}
+
+// Delete removes the object. S3 reports success for keys that do not exist.
+func (s3 *S3Storage) Delete(ctx context.Context, key string) error {
+	request, err := s3.request(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	response, err := s3.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	err = s3.check(response)
+	if err == ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+func (s3 *S3Storage) check(response *http.Response) error {
+	if response.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if response.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("s3 request failed with status %d: %s", response.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+func (s3 *S3Storage) request(ctx context.Context, method string, key string, payload []byte) (*http.Request, error) {
+	if key == "" || strings.HasPrefix(key, "/") {
+		return nil, fmt.Errorf("invalid storage key %q", key)
+	}
+	target := *s3.endpoint
+	basePath := strings.TrimSuffix(target.Path, "/")
+	objectPath := "/" + key
+	if s3.config.UsePathStyle {
+		objectPath = "/" + s3.config.Bucket + objectPath
+	} else {
+		target.Host = s3.config.Bucket + "." + target.Host
+	}
+	target.Path = basePath + objectPath
+	target.RawPath = uriEncode(basePath) + uriEncode(objectPath)
+
+	request, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	request.ContentLength = int64(len(payload))
+	s3.sign(request, target.RawPath, payload)
+	return request, nil
+}
+
+// sign adds the AWS Signature Version 4 headers to a request. Only the host,
+// date and payload hash are signed, which is all S3 requires.
+func (s3 *S3Storage) sign(request *http.Request, canonicalURI string, payload []byte) {
+	now := s3.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	request.Header.Set("X-Amz-Date", amzDate)
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "host:" + request.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		canonicalURI,
+		request.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s3.config.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	signingKey := hmacSHA256([]byte("AWS4"+s3.config.SecretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, s3.config.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	request.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3.config.AccessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+// uriEncode escapes a path the way AWS expects in canonical requests: every
+// byte except unreserved characters and slashes is percent encoded.
+func uriEncode(value string) string {
+	var builder strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			builder.WriteByte(c)
+		case c == '/':
+			builder.WriteByte(c)
+		default:
+			fmt.Fprintf(&builder, "%%%02X", c)
+		}
+	}
+	return builder.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
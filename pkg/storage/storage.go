@@ -0,0 +1,18 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned when an object does not exist in the backend.
+var ErrNotFound = errors.New("object not found")
+
+// Storage is a blob store for uploaded files. Keys are slash separated paths
+// such as "attachments/1/42/3f2a9c".
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
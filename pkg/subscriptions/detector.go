@@ -0,0 +1,268 @@
+package subscriptions
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/christo-andrew/haven/internal/models"
+	"github.com/christo-andrew/haven/pkg/payees"
+	"github.com/christo-andrew/haven/pkg/recurrence"
+	"github.com/christo-andrew/haven/pkg/utils"
+)
+
+// Cadence is a billing period a series of charges can follow.
+type Cadence struct {
+	Name string
+	Days float64
+	// Tolerance is how many days an interval may drift from Days and still
+	// count as this cadence.
+	Tolerance float64
+	freq      string
+	interval  int
+}
+
+var Cadences = []Cadence{
+	{Name: "weekly", Days: 7, Tolerance: 1, freq: recurrence.Weekly, interval: 1},
+	{Name: "biweekly", Days: 14, Tolerance: 2, freq: recurrence.Weekly, interval: 2},
+	{Name: "monthly", Days: 30.4, Tolerance: 4, freq: recurrence.Monthly, interval: 1},
+	{Name: "quarterly", Days: 91.3, Tolerance: 8, freq: recurrence.Monthly, interval: 3},
+	{Name: "yearly", Days: 365.25, Tolerance: 12, freq: recurrence.Yearly, interval: 1},
+}
+
+// Rule returns the recurrence that repeats a charge of this cadence which last
+// happened on the given date.
+func (cadence Cadence) Rule(last time.Time) recurrence.Rule {
+	rule := recurrence.Rule{Freq: cadence.freq, Interval: cadence.interval}
+	if cadence.freq == recurrence.Monthly {
+		rule.ByMonthDay = []int{last.Day()}
+	}
+	return rule
+}
+
+// Subscription is a series of periodic charges to the same payee.
+type Subscription struct {
+	Key               string    `json:"key"`
+	Name              string    `json:"name"`
+	PayeeID           *int      `json:"payee_id"`
+	AccountID         int       `json:"account_id"`
+	CategoryID        int       `json:"category_id"`
+	TransactionTypeID int       `json:"transaction_type_id"`
+	Currency          string    `json:"currency"`
+	Cadence           string    `json:"cadence"`
+	Recurrence        string    `json:"recurrence"`
+	Amount            float64   `json:"amount"`
+	AverageAmount     float64   `json:"average_amount"`
+	Occurrences       int       `json:"occurrences"`
+	FirstDate         time.Time `json:"first_date"`
+	LastDate          time.Time `json:"last_date"`
+	NextDate          time.Time `json:"next_date"`
+	PriceIncreased    bool      `json:"price_increased"`
+	PreviousAmount    float64   `json:"previous_amount,omitempty"`
+	Stopped           bool      `json:"stopped"`
+	TransactionIDs    []int     `json:"transaction_ids"`
+}
+
+// Detector finds subscriptions in a transaction history.
+type Detector struct {
+	// MinOccurrences is the number of charges needed before a series counts.
+	MinOccurrences int
+	// AmountTolerance is how far, as a fraction of the typical amount, a
+	// charge may be from the typical charge of its series.
+	AmountTolerance float64
+	// Regularity is the share of intervals that must match the cadence.
+	Regularity float64
+}
+
+var DefaultDetector = &Detector{
+	MinOccurrences:  3,
+	AmountTolerance: 0.25,
+	Regularity:      0.75,
+}
+
+func Detect(transactions []models.Transaction, now time.Time) []Subscription {
+	return DefaultDetector.Detect(transactions, now)
+}
+
+// Detect groups debit transactions by payee, or by normalised description when
+// no payee is linked, and returns the groups that repeat on a regular cadence
+// with similar amounts. Transfers and transactions posted by a recurring
+// template are ignored. Subscriptions are sorted by their next expected date.
+func (detector *Detector) Detect(transactions []models.Transaction, now time.Time) []Subscription {
+	groups := make(map[string][]models.Transaction)
+	var keys []string
+	for _, transaction := range transactions {
+		if transaction.IsTransfer || transaction.RecurringID != nil || transaction.Direction() != "debit" {
+			continue
+		}
+		key := Key(transaction)
+		if key == "" {
+			continue
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], transaction)
+	}
+
+	var detected []Subscription
+	for _, key := range keys {
+		if subscription, ok := detector.detect(key, groups[key], now); ok {
+			detected = append(detected, subscription)
+		}
+	}
+	sort.SliceStable(detected, func(i, j int) bool { return detected[i].NextDate.Before(detected[j].NextDate) })
+	return detected
+}
+
+// Key identifies the series a transaction belongs to.
+func Key(transaction models.Transaction) string {
+	if transaction.PayeeID != nil {
+		return "payee:" + strconv.Itoa(*transaction.PayeeID)
+	}
+	text := transaction.Payee
+	if text == "" {
+		text = transaction.Description
+	}
+	normalized := payees.Normalize(text)
+	if normalized == "" {
+		return ""
+	}
+	return "description:" + normalized
+}
+
+func (detector *Detector) detect(key string, series []models.Transaction, now time.Time) (Subscription, bool) {
+	sort.SliceStable(series, func(i, j int) bool { return series[i].Date.Before(series[j].Date) })
+	series = oneChargePerDay(series)
+	if len(series) < detector.MinOccurrences {
+		return Subscription{}, false
+	}
+
+	amounts := make([]float64, len(series))
+	for i, transaction := range series {
+		amounts[i] = math.Abs(transaction.Amount)
+	}
+	typical := median(amounts)
+	if typical == 0 {
+		return Subscription{}, false
+	}
+	similar := 0
+	for _, amount := range amounts {
+		if math.Abs(amount-typical) <= typical*detector.AmountTolerance {
+			similar++
+		}
+	}
+	if float64(similar) < float64(len(amounts))*detector.Regularity {
+		return Subscription{}, false
+	}
+
+	intervals := make([]float64, len(series)-1)
+	for i := 1; i < len(series); i++ {
+		intervals[i-1] = series[i].Date.Sub(series[i-1].Date).Hours() / 24
+	}
+	cadence, ok := detector.cadence(intervals)
+	if !ok {
+		return Subscription{}, false
+	}
+
+	last := series[len(series)-1]
+	rule := cadence.Rule(last.Date)
+	nextDate, _ := rule.Next(last.Date, last.Date)
+	subscription := Subscription{
+		Key:               key,
+		Name:              subscriptionName(last),
+		PayeeID:           last.PayeeID,
+		AccountID:         last.AccountID,
+		CategoryID:        last.CategoryID,
+		TransactionTypeID: last.TransactionTypeID,
+		Currency:          last.Currency,
+		Cadence:           cadence.Name,
+		Recurrence:        rule.String(),
+		Amount:            last.Amount,
+		AverageAmount:     utils.RoundToCents(mean(amounts)),
+		Occurrences:       len(series),
+		FirstDate:         series[0].Date,
+		LastDate:          last.Date,
+		NextDate:          nextDate,
+	}
+	for _, transaction := range series {
+		subscription.TransactionIDs = append(subscription.TransactionIDs, transaction.ID)
+	}
+
+	lastAmount := amounts[len(amounts)-1]
+	previousAmount := amounts[len(amounts)-2]
+	if utils.RoundToCents(lastAmount) > utils.RoundToCents(previousAmount) {
+		subscription.PriceIncreased = true
+		subscription.PreviousAmount = previousAmount
+	}
+
+	grace := math.Max(cadence.Tolerance*2, 7)
+	subscription.Stopped = now.Sub(subscription.NextDate).Hours()/24 > grace
+	return subscription, true
+}
+
+// cadence picks the cadence most intervals agree with.
+func (detector *Detector) cadence(intervals []float64) (Cadence, bool) {
+	typical := median(intervals)
+	for _, cadence := range Cadences {
+		if math.Abs(typical-cadence.Days) > cadence.Tolerance {
+			continue
+		}
+		matching := 0
+		for _, interval := range intervals {
+			if math.Abs(interval-cadence.Days) <= cadence.Tolerance {
+				matching++
+			}
+		}
+		if float64(matching) >= float64(len(intervals))*detector.Regularity {
+			return cadence, true
+		}
+	}
+	return Cadence{}, false
+}
+
+// oneChargePerDay keeps the first charge of every day, a payee charged twice on
+// the same day is a retry or a separate purchase, not another period.
+func oneChargePerDay(series []models.Transaction) []models.Transaction {
+	var result []models.Transaction
+	for _, transaction := range series {
+		if len(result) > 0 && sameDay(result[len(result)-1].Date, transaction.Date) {
+			continue
+		}
+		result = append(result, transaction)
+	}
+	return result
+}
+
+func subscriptionName(transaction models.Transaction) string {
+	if transaction.Payee != "" {
+		return transaction.Payee
+	}
+	return payees.DisplayName(payees.Normalize(transaction.Description))
+}
+
+func sameDay(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+func mean(values []float64) float64 {
+	var total float64
+	for _, value := range values {
+		total += value
+	}
+	return total / float64(len(values))
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"io"
 	"log"
+	"math"
 	"os"
 	"strconv"
 	"strings"
@@ -112,3 +113,7 @@ func ConvertToUnixTime(timeStr string) time.Time {
 	t, _ := strconv.ParseInt(timeStr, 10, 64)
 	return time.Unix(t, 0)
 }
+
+func RoundToCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}